@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Recomputes the aggregated metrics from an existing pr_metrics.csv without calling the API and
+// returns the exit code
+func runAggregate(args []string) int {
+	flags := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON config file with the CSV layout, SLOs, and category rules")
+	localeName := flags.String("locale", "", "Locale of the CSV files, e.g. de-DE for comma decimals and semicolon delimiters")
+	outputDir := flags.String("output-dir", "", "Output directory for the recomputed files (defaults to the directory of PATH, replacing its aggregates)")
+	dependencyUpdates := flags.Bool("dependency-updates", false, "Write dependency_updates.csv and leave dependency update PRs out of the aggregated metrics")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	quiet := flags.Bool("quiet", false, "Only print errors to the console")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s aggregate [flags] PATH\n\nPATH is an output directory or a pr_metrics.csv file, possibly renamed by --output-name-template.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   *quiet,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitValidation
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+	if _, err := applyLocale(cfg, *localeName); err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	inputNamer, err := outputNamer(flags.Arg(0))
+	if err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+	namer := inputNamer
+	if *outputDir != "" {
+		if namer, err = output.NewFileNamer(*outputDir, "", nil); err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+
+	csvWriter := output.NewCSVWriter(logger, cfg.CSV)
+	prMetrics, err := csvWriter.ReadPRMetricsCSV(inputNamer.Path("pr_metrics.csv"))
+	if err != nil {
+		logger.Error("Failed to read PR metrics: %v", err)
+		return exitError
+	}
+	logger.Info("Read %d pull requests from %s", len(prMetrics), inputNamer.Path("pr_metrics.csv"))
+
+	if _, err := writeAggregates(logger, cfg, csvWriter, namer, prMetrics, *dependencyUpdates); err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+
+	logger.Info("Successfully recomputed aggregated metrics for %d pull requests in directory: %s", len(prMetrics), namer.Dir())
+	return exitOK
+}
+
+// Writes the PR metrics with their weekly and monthly aggregates, and the SLO, category, and
+// dependency update breakdowns the config and flags ask for, and returns the weekly aggregates
+func writeAggregates(logger *utils.Logger, cfg *config.Config, csvWriter *output.CSVWriter, namer *output.FileNamer, prMetrics []*api.PRMetrics, dependencyUpdates bool) ([]*api.AggregatedMetrics, error) {
+	calculator := metrics.NewCalculator(nil, logger, metrics.Options{})
+
+	aggregatedPRs := prMetrics
+	var dependencyUpdatePRs []*api.PRMetrics
+	if dependencyUpdates {
+		dependencyUpdatePRs, aggregatedPRs = metrics.SplitDependencyUpdates(prMetrics)
+		logger.Info("Found %d dependency update PRs; leaving them out of the aggregated metrics", len(dependencyUpdatePRs))
+	}
+
+	weeklyMetrics, err := calculator.CalculateWeeklyAggregatedMetrics(aggregatedPRs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate weekly metrics: %v", err)
+	}
+	monthlyMetrics, err := calculator.CalculateMonthlyAggregatedMetrics(aggregatedPRs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate monthly metrics: %v", err)
+	}
+	if err := csvWriter.WriteToDirectory(namer, prMetrics, weeklyMetrics, monthlyMetrics); err != nil {
+		return nil, fmt.Errorf("failed to write CSV files: %v", err)
+	}
+
+	if len(cfg.SLOs) > 0 {
+		attainments := metrics.CalculateSLOAttainment(aggregatedPRs, cfg.SLOs)
+		if err := csvWriter.WriteSLOCSV(namer.Path("slo_metrics.csv"), attainments); err != nil {
+			return nil, fmt.Errorf("failed to write SLO metrics: %v", err)
+		}
+	}
+
+	if len(cfg.Categories) > 0 {
+		categoryMetrics, err := calculator.CalculateCategoryAggregatedMetrics(aggregatedPRs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate category metrics: %v", err)
+		}
+		if err := csvWriter.WriteCategoryMetricsCSV(namer.Path("category_metrics.csv"), categoryMetrics); err != nil {
+			return nil, fmt.Errorf("failed to write category metrics: %v", err)
+		}
+	}
+
+	if dependencyUpdates {
+		updates := metrics.CalculateDependencyUpdateMetrics(dependencyUpdatePRs)
+		if err := csvWriter.WriteDependencyUpdatesCSV(namer.Path("dependency_updates.csv"), updates); err != nil {
+			return nil, fmt.Errorf("failed to write dependency update metrics: %v", err)
+		}
+	}
+	return weeklyMetrics, nil
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Subcommand of the tool. Commands without a run function share the flags of a regular run.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+// Subcommands in the order they are listed in the help message
+var commands = []command{
+	{name: "collect", summary: "Fetch PRs and write their metrics (the default when no command is given)"},
+	{name: "backfill", summary: "Collect a long range month by month, resuming where an interrupted run stopped"},
+	{name: "prefetch", summary: "Fetch the API responses into --cache-dir or --record without computing metrics"},
+	{name: "aggregate", summary: "Recompute the aggregated metrics from an existing pr_metrics.csv without calling the API", run: runAggregate},
+	{name: "report", summary: "Combine the outputs of several runs into aggregated metrics and a report without calling the API", run: runReport},
+	{name: "diff", summary: "Compare the pr_metrics.csv of two runs", run: runDiff},
+	{name: "migrate-output", summary: "Upgrade outputs written by an earlier release to the current schema", run: runMigrateOutput},
+	{name: "tui", summary: "Browse the PRs of an output directory in the terminal", run: runTUI},
+	{name: "serve", summary: "Serve an output directory and its metrics as JSON over HTTP", run: runServe},
+	{name: "version", summary: "Print the version and build details", run: runVersion},
+	{name: "self-update", summary: "Replace the binary with the latest release", run: runSelfUpdate},
+	{name: "completion", summary: "Print a completion script for bash, zsh, or fish"},
+}
+
+// Returns the subcommand named by the first argument, or nil if the arguments start with a flag
+func findCommand(args []string) *command {
+	if len(args) == 0 {
+		return nil
+	}
+	for i := range commands {
+		if commands[i].name == args[0] {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// Prints the usage of the tool with its subcommands and the flags of a regular run
+func printUsage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintf(out, "Usage: %s [command] [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nRun '%s COMMAND -h' for the flags of aggregate, report, diff, migrate-output, tui, serve, version, and self-update.\n\nFlags of collect, backfill, and prefetch:\n", os.Args[0])
+	flags.PrintDefaults()
+}
+
+// Prints the completion script for the shell named in the arguments and returns the exit code
+func runCompletion(args []string, runFlags *flag.FlagSet) int {
+	flags := flag.NewFlagSet("completion", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s completion bash|zsh|fish\n\n", os.Args[0])
+		fmt.Fprintf(flags.Output(), "Load the script in the shell's startup file, for example:\n  bash: source <(github-pr-metrics completion bash)\n  zsh:  source <(github-pr-metrics completion zsh)\n  fish: github-pr-metrics completion fish | source\n")
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitValidation
+	}
+
+	var runFlagList []*flag.Flag
+	runFlags.VisitAll(func(f *flag.Flag) {
+		runFlagList = append(runFlagList, f)
+	})
+	sort.Slice(runFlagList, func(i, j int) bool {
+		return runFlagList[i].Name < runFlagList[j].Name
+	})
+
+	switch flags.Arg(0) {
+	case "bash":
+		writeBashCompletion(os.Stdout, runFlagList)
+	case "zsh":
+		writeZshCompletion(os.Stdout, runFlagList)
+	case "fish":
+		writeFishCompletion(os.Stdout, runFlagList)
+	default:
+		fmt.Fprintf(os.Stderr, "Unsupported shell %q: must be 'bash', 'zsh', or 'fish'\n", flags.Arg(0))
+		return exitValidation
+	}
+	return exitOK
+}
+
+// Returns the flag as typed on the command line, with one dash for shorthands and two otherwise
+func flagSpelling(f *flag.Flag) string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// Reports whether the flag takes no value, like --verbose
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// Returns the names of the subcommands
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+// Writes a bash completion script completing subcommands, run flags, and file names
+func writeBashCompletion(w io.Writer, runFlags []*flag.Flag) {
+	var spellings, valueFlags []string
+	for _, f := range runFlags {
+		spellings = append(spellings, flagSpelling(f))
+		if !isBoolFlag(f) {
+			valueFlags = append(valueFlags, flagSpelling(f))
+		}
+	}
+
+	fmt.Fprintf(w, `# bash completion for github-pr-metrics
+_github_pr_metrics() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [ "$COMP_CWORD" -eq 1 ] && [[ "$cur" != -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+        return
+    fi
+    case "${COMP_WORDS[1]}" in
+        completion)
+            COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+            return
+            ;;
+    esac
+    case " %s " in
+        *" $prev "*)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return
+            ;;
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+complete -o filenames -F _github_pr_metrics github-pr-metrics
+`, strings.Join(commandNames(), " "), strings.Join(valueFlags, " "), strings.Join(spellings, " "))
+}
+
+// Writes a zsh completion script completing subcommands and run flags with their descriptions
+func writeZshCompletion(w io.Writer, runFlags []*flag.Flag) {
+	escape := strings.NewReplacer(`\`, `\\`, `'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`)
+
+	fmt.Fprintln(w, "#compdef github-pr-metrics")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "_github_pr_metrics() {")
+	fmt.Fprintln(w, "    if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then")
+	fmt.Fprintln(w, "        local -a commands")
+	fmt.Fprintln(w, "        commands=(")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "            '%s:%s'\n", cmd.name, escape.Replace(cmd.summary))
+	}
+	fmt.Fprintln(w, "        )")
+	fmt.Fprintln(w, "        _describe 'command' commands")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, "    if [[ $words[2] == completion ]]; then")
+	fmt.Fprintln(w, "        _values 'shell' bash zsh fish")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, "    _arguments \\")
+	for _, f := range runFlags {
+		value := ": :_files"
+		if isBoolFlag(f) {
+			value = ""
+		}
+		fmt.Fprintf(w, "        '%s[%s]%s' \\\n", flagSpelling(f), escape.Replace(f.Usage), value)
+	}
+	fmt.Fprintln(w, "        '*:file:_files'")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "compdef _github_pr_metrics github-pr-metrics")
+}
+
+// Writes a fish completion script completing subcommands and run flags with their descriptions
+func writeFishCompletion(w io.Writer, runFlags []*flag.Flag) {
+	quote := func(s string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+	}
+
+	fmt.Fprintln(w, "# fish completion for github-pr-metrics")
+	fmt.Fprintln(w, "complete -c github-pr-metrics -f")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "complete -c github-pr-metrics -n __fish_use_subcommand -a %s -d %s\n", cmd.name, quote(cmd.summary))
+	}
+	fmt.Fprintln(w, "complete -c github-pr-metrics -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'")
+	for _, f := range runFlags {
+		option := "-l " + f.Name
+		if len(f.Name) == 1 {
+			option = "-s " + f.Name
+		}
+		if !isBoolFlag(f) {
+			option += " -r -F"
+		}
+		fmt.Fprintf(w, "complete -c github-pr-metrics %s -d %s\n", option, quote(f.Usage))
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Compares the pr_metrics.csv of two output directories and returns the exit code
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON config file the outputs were written with, for the CSV layout")
+	localeName := flags.String("locale", "", "Locale the outputs were written with, for the CSV layout")
+	format := flags.String("format", "text", "Output format (text, json)")
+	exitCode := flags.Bool("exit-code", false, "Exit with 1 when the snapshots differ")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s diff [flags] OLD NEW\n\nOLD and NEW are output directories or pr_metrics.csv files.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   !*verbose,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitValidation
+	}
+	if *format != "text" && *format != "json" {
+		logger.Error("Diff format must be 'text' or 'json'")
+		return exitValidation
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+	if _, err := applyLocale(cfg, *localeName); err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	csvReader := output.NewCSVWriter(logger, cfg.CSV)
+	snapshots := make([][]*api.PRMetrics, 2)
+	for i, path := range flags.Args() {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pr_metrics.csv")
+		}
+		snapshots[i], err = csvReader.ReadPRMetricsCSV(path)
+		if err != nil {
+			logger.Error("Failed to read %s: %v", path, err)
+			return exitError
+		}
+	}
+
+	aggregator := metrics.NewAggregatedMetricsCalculator(logger)
+	diff := output.DiffSnapshots(snapshots[0], snapshots[1],
+		aggregator.CalculateOverallAggregatedMetrics(snapshots[0]), aggregator.CalculateOverallAggregatedMetrics(snapshots[1]))
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(diff)
+	} else {
+		err = diff.WriteText(os.Stdout)
+	}
+	if err != nil {
+		logger.Error("Failed to write diff: %v", err)
+		return exitError
+	}
+
+	if *exitCode && !diff.IsEmpty() {
+		return 1
+	}
+	return exitOK
+}
@@ -2,41 +2,218 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"math/rand/v2"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/fukuchancat/github-pr-metrics/internal/agreement"
+	"github.com/fukuchancat/github-pr-metrics/internal/alert"
 	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/backfill"
+	"github.com/fukuchancat/github-pr-metrics/internal/calendar"
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/jira"
+	"github.com/fukuchancat/github-pr-metrics/internal/localgit"
 	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/notify"
 	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/internal/tui"
+	"github.com/fukuchancat/github-pr-metrics/internal/upload"
 	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
 )
 
+// Exit codes reported to automation
+const (
+	exitOK             = 0
+	exitError          = 1 // Unexpected failure
+	exitValidation     = 2 // Invalid command line arguments
+	exitAuth           = 3 // Missing or rejected credentials
+	exitRateLimit      = 4 // API rate limit exceeded
+	exitPartialFailure = 5 // Some PRs failed and --strict is set
+)
+
+// Rate limit reserve the backfill subcommand paces requests to when --min-remaining is not given
+const backfillMinRemaining = 100
+
 func main() {
+	// Subcommands such as diff and version take their own flags
+	cmd := findCommand(os.Args[1:])
+	if cmd != nil && cmd.run != nil {
+		os.Exit(cmd.run(os.Args[2:]))
+	}
+
 	// Parse command line arguments
-	githubURL := flag.String("url", "https://api.github.com", "GitHub API URL")
-	token := flag.String("token", "", "GitHub Personal Access Token")
+	configPath := flag.String("config", "", "JSON config file with output settings")
+	provider := flag.String("provider", api.ProviderGitHub, "Source code hosting provider (github, gitlab, bitbucket, gitea, azure-devops)")
+	githubURL := flag.String("url", "https://api.github.com", "API URL (defaults to the provider's public API)")
+	token := flag.String("token", "", "Personal Access Token; comma-separate several GitHub tokens to rotate between them")
+	tokenFile := flag.String("token-file", "", "File of GitHub tokens to rotate between, one per line")
 	repo := flag.String("repo", "", "Repository name in format 'owner/repo'")
 	startDate := flag.String("start-date", "", "Start date for PR filtering (format: YYYY-MM-DD)")
 	endDate := flag.String("end-date", "", "End date for PR filtering (format: YYYY-MM-DD)")
+	dateField := flag.String("date-field", api.DateFieldCreated, "PR timestamp the start/end dates apply to (created, merged, closed)")
+	state := flag.String("state", api.StateAll, "Current state of the PRs to analyze (merged, closed, open, all); closed means closed without merging")
+	updatedSince := flag.String("updated-since", "", "Also include PRs updated on or after this date, even if created earlier (format: YYYY-MM-DD)")
 	outputDir := flag.String("output-dir", "output", "Output directory for CSV files")
+	localeName := flag.String("locale", "", "Format the CSV files and the report for a region, e.g. de-DE for comma decimals and semicolon delimiters (en-US, en-GB, de-DE, fr-FR, es-ES, it-IT, nl-NL, pt-BR, ja-JP)")
+	compression := flag.String("compress", "", "Compress the outputs once written: gzip each file, or zip them into one outputs.zip (gzip, zip)")
+	uploadURL := flag.String("upload", "", "Upload the outputs after the run under a per-run prefix of this bucket, e.g. s3://bucket/prefix or gs://bucket/prefix")
+	outputNameTemplate := flag.String("output-name-template", "", "Template for output file names, e.g. '{repo}_{start}_{end}_{file}' (placeholders: owner, repo, start, end, file)")
+	commitDate := flag.String("commit-date", metrics.CommitDateSourceAuthor, "Commit date used for commit timing metrics (author, committer)")
+	clampCommitTimes := flag.Bool("clamp-commit-times", false, "Clamp commit times after the merge to the merge time")
+	businessHours := flag.String("business-hours", "", "Measure durations in working hours only, Monday to Friday within these hours (e.g. 09:00-18:00)")
+	timezone := flag.String("timezone", "UTC", "Time zone of the working hours, of --heatmap, and of --review-latency-by-creation-time (IANA name, e.g. Asia/Tokyo)")
+	holidaysFile := flag.String("holidays", "", "File of holidays to skip in business hours, one YYYY-MM-DD date per line")
+	holidayCountry := flag.String("holiday-country", "", "Skip the public holidays of this country in business hours (JP, US)")
+	dataQualityPolicy := flag.String("data-quality", metrics.DataQualityPolicyReport, "Handling of impossible values such as negative durations (report, clamp, exclude)")
+	reportFormats := flag.String("report", "", "Also render a report as report.html, report.md, and/or report.json (comma-separated: html, md, json)")
+	chartFormats := flag.String("charts", "", "Also render weekly lead time, throughput, and review latency charts as images (comma-separated: svg, png)")
+	notifyDestinations := flag.Bool("notify", false, "Send the summary to the destinations in the notify section of the config file")
+	appendMode := flag.Bool("append", false, "Merge PRs into an existing pr_metrics.csv, replacing rows of the same PR number, and recompute aggregates over all of them")
+	anonymize := flag.Bool("anonymize", false, "Replace author and reviewer logins with stable pseudonyms in all outputs")
+	anonymizeSalt := flag.String("anonymize-salt", "", "Secret mixed into the pseudonyms so they cannot be matched to logins by hashing known names")
+	localGitDir := flag.String("local-git", "", "Local clone for commit-level analysis (touched functions, test change ratio, renames); cloned if missing")
+	localGitURL := flag.String("local-git-url", "", "URL to clone --local-git from (defaults to the GitHub repository)")
+	ciMetrics := flag.Bool("ci-metrics", false, "Fetch the check runs of each PR's head commit to measure how long CI waited for a runner and how often failed checks were re-run (one more request per PR)")
+	hotspots := flag.Bool("hotspots", false, "Also write hotspots.csv listing the most frequently changed files and directories")
+	cohorts := flag.Bool("cohorts", false, "Also write cohort_metrics.csv with the share of the PRs opened each week that were merged within 1, 3, 7, 14, and 30 days")
+	cadence := flag.Bool("cadence", false, "Also write author_cadence.csv and team_cadence.csv with the median days between consecutive PRs of each author, overall and per week and month")
+	heatmap := flag.Bool("heatmap", false, "Also write heatmap.csv counting PR creations, reviews, and merges by day of week and hour of day per week and month")
+	creationTimeLatency := flag.Bool("review-latency-by-creation-time", false, "Also write review_latency_by_creation_time.csv with the median hours to first review by the day of week and hour of day PRs were opened")
+	reviewRouting := flag.Bool("review-routing", false, "Also write review_routing.csv comparing requested reviewers, actual reviewers, and code owners per week and month")
+	busFactor := flag.Bool("bus-factor", false, "Also write bus_factor.csv counting the distinct reviewers of each component per week and month, flagging components with only one")
+	busFactorDepth := flag.Int("bus-factor-depth", 1, "Number of leading directories that name a component for --bus-factor, e.g. 2 for internal/api")
+	dependencyUpdates := flag.Bool("dependency-updates", false, "Write dependency_updates.csv for PRs opened by Dependabot, Renovate, and the bots in the config file, and leave them out of the aggregated metrics")
+	commentsByFileType := flag.Bool("comments-by-file-type", false, "Also write comments_by_file_type.csv with the weekly and monthly review comments on source, test, config, and docs files")
+	languages := flag.Bool("languages", false, "Also write language_metrics.csv with the weekly and monthly mix of changed lines by language")
+	leaderboard := flag.Bool("leaderboard", false, "Also write leaderboard.csv ranking reviewers, PR authors, and files (tune with the config file)")
+	xlsx := flag.Bool("xlsx", false, "Also write all metrics to a single metrics.xlsx workbook")
+	eventsFormat := flag.String("events", "", "Also export the PR event stream as events.csv or events.jsonl (csv, jsonl)")
+	timelineFormats := flag.String("timelines", "", "Also export per-PR timelines of commits, comments, reviews, and merge as timelines.json and/or a Gantt-style timelines.svg (comma-separated: json, svg)")
+	timelineCount := flag.Int("timeline-count", 10, "Number of slowest PRs, by total lifetime, to export timelines for")
+	timelinePRs := flag.String("timeline-prs", "", "Export timelines of these PR numbers instead of the slowest (comma-separated)")
+	replayDir := flag.String("replay", "", "Read recorded API responses from this directory instead of calling the API")
+	recordDir := flag.String("record", "", "Save raw API responses to this directory for later replay")
+	proxy := flag.String("proxy", "", "HTTP proxy URL for all API requests (defaults to the HTTPS_PROXY environment variable)")
+	caCert := flag.String("ca-cert", "", "PEM file of CA certificates to trust, e.g. for a server with a private CA")
+	insecureSkipVerify := flag.Bool("insecure-skip-verify", false, "Skip TLS certificate verification (insecure; for testing only)")
+	cacheDir := flag.String("cache-dir", "", "Cache responses in this directory, following their Cache-Control headers and revalidating them with ETags on later runs")
+	cacheTTL := flag.Duration("cache-ttl", 0, "Serve cached responses younger than this without revalidating, e.g. 24h (0 follows the max-age sent by the API)")
+	maxRequests := flag.Int("max-requests", 0, "Maximum number of API requests for the run; stops early and writes partial results when reached (0 for unlimited)")
+	minRemaining := flag.Int("min-remaining", 0, "Rate limit reserve to leave untouched; requests are paced to stay above it (0 for none)")
+	sampleSize := flag.Int("sample", 0, "Analyze a random sample of this many PRs for quick approximate numbers (0 for all)")
+	sampleSeed := flag.Uint64("sample-seed", 0, "Seed of the --sample draw, to analyze the same sample again (0 for a random seed, recorded in run_report.json)")
+	maxPRs := flag.Int("max-prs", 0, "Analyze at most this many PRs, keeping the most recently created (0 for all)")
+	strict := flag.Bool("strict", false, "Exit with a non-zero status if any PR failed")
+	onError := flag.String("on-error", metrics.ErrorPolicySkip, "What to do when fetching a PR's data fails (skip: warn and go on, fail: stop at once, retry: retry the request, then skip)")
 	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	logFormat := flag.String("log-format", utils.LogFormatText, "Log output format (text, json)")
+	logFile := flag.String("log-file", "", "Also write logs to this file, rotating it when it reaches 10 MB")
+	quiet := flag.Bool("quiet", false, "Only print errors to the console")
+	browse := flag.Bool("tui", false, "Show live fetch progress instead of logs, then browse the PRs in the terminal when the run finishes")
 	help := flag.Bool("help", false, "Show help message")
 
 	// Define short options
-	flag.StringVar(githubURL, "u", "https://api.github.com", "GitHub API URL (shorthand)")
-	flag.StringVar(token, "t", "", "GitHub Personal Access Token (shorthand)")
+	flag.StringVar(provider, "p", api.ProviderGitHub, "Source code hosting provider (shorthand)")
+	flag.StringVar(githubURL, "u", "https://api.github.com", "API URL (shorthand)")
+	flag.StringVar(token, "t", "", "Personal Access Token (shorthand)")
 	flag.StringVar(repo, "r", "", "Repository name in format 'owner/repo' (shorthand)")
 	flag.StringVar(startDate, "s", "", "Start date for PR filtering (shorthand)")
 	flag.StringVar(endDate, "e", "", "End date for PR filtering (shorthand)")
 	flag.StringVar(outputDir, "o", "output", "Output directory for CSV files (shorthand)")
 	flag.BoolVar(verbose, "v", false, "Enable verbose logging (shorthand)")
+	flag.StringVar(replayDir, "from-raw", "", "Recompute metrics from raw responses saved with --record (alias of --replay)")
 	flag.BoolVar(help, "h", false, "Show help message (shorthand)")
 
-	flag.Parse()
+	flag.Usage = func() {
+		printUsage(flag.CommandLine)
+	}
+
+	// The completion script lists the flags of a regular run
+	if cmd != nil && cmd.name == "completion" {
+		os.Exit(runCompletion(os.Args[2:], flag.CommandLine))
+	}
+
+	// The collect, backfill, and prefetch subcommands take the same flags as a regular run
+	backfillMode := cmd != nil && cmd.name == "backfill"
+	prefetchMode := cmd != nil && cmd.name == "prefetch"
+	if cmd != nil {
+		// The command line uses ExitOnError, so errors never reach here
+		_ = flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
 
 	// Create logger
-	logger := utils.NewLogger(*verbose)
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Format:  *logFormat,
+		Quiet:   *quiet || (*browse && !*verbose),
+		File:    *logFile,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitValidation)
+	}
+
+	// Track the run for run_report.json, which is written on every exit
+	startedAt := time.Now()
+	report := &api.RunReport{}
+	namer, _ := output.NewFileNamer(*outputDir, "", nil)
+	var client api.Provider
+	var uploader *upload.Uploader
+	outputsWritten := false
+	exit := func(code int) {
+		report.ExitCode = code
+		report.DurationSeconds = time.Since(startedAt).Seconds()
+		if client != nil {
+			usage := client.Usage()
+			report.APIRequests = usage.Requests
+			report.APIEndpoints = usage.Endpoints
+			if usage.RateLimitRemaining >= 0 {
+				report.RateLimitRemaining = &usage.RateLimitRemaining
+			}
+			logAPIUsage(logger, usage)
+		}
+
+		// Upload the outputs before the run report, so the report records whether the upload succeeded
+		runReportPath := namer.Path("run_report.json")
+		uploadOutputs := uploader != nil && outputsWritten
+		if uploadOutputs {
+			files := slices.DeleteFunc(namer.Existing(), func(path string) bool { return path == runReportPath })
+			location, err := uploader.Upload(namer.Dir(), files, startedAt)
+			if err != nil {
+				report.Error = fmt.Sprintf("Failed to upload outputs: %v", err)
+				logger.Error("Failed to upload outputs: %v", err)
+				if code == exitOK {
+					code = exitError
+					report.ExitCode = code
+				}
+			} else {
+				report.UploadLocation = location
+				logger.Info("Uploaded %d output files to %s", len(files), location)
+			}
+		}
+
+		if err := output.NewRunReportWriter(logger).Write(runReportPath, report); err != nil {
+			logger.Warn("Failed to write run report: %v", err)
+		} else if uploadOutputs {
+			if _, err := uploader.Upload(namer.Dir(), []string{runReportPath}, startedAt); err != nil {
+				logger.Warn("Failed to upload run report: %v", err)
+			}
+		}
+		os.Exit(code)
+	}
+	fatal := func(code int, format string, v ...any) {
+		report.Error = fmt.Sprintf(format, v...)
+		logger.Error(format, v...)
+		exit(code)
+	}
 
 	// Show help message if requested
 	if *help {
@@ -44,30 +221,235 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Load the config file if given
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			fatal(exitValidation, "%v", err)
+		}
+	}
+	locale, err := applyLocale(cfg, *localeName)
+	if err != nil {
+		fatal(exitValidation, "%v", err)
+	}
+
 	// Validate required arguments
-	if *token == "" {
-		logger.Fatal("GitHub Personal Access Token is required")
+	tokens, err := loadTokens(*token, *tokenFile)
+	if err != nil {
+		fatal(exitValidation, "Failed to load tokens: %v", err)
+	}
+	if len(tokens) == 0 && *replayDir == "" {
+		fatal(exitValidation, "Personal Access Token is required")
+	}
+	if len(tokens) > 1 && *provider != api.ProviderGitHub {
+		fatal(exitValidation, "Token rotation is only supported for the %s provider", api.ProviderGitHub)
 	}
 
 	if *repo == "" {
-		logger.Fatal("Repository name is required")
+		fatal(exitValidation, "Repository name is required")
+	}
+
+	if *dateField != api.DateFieldCreated && *dateField != api.DateFieldMerged && *dateField != api.DateFieldClosed {
+		fatal(exitValidation, "Date field must be 'created', 'merged', or 'closed'")
+	}
+
+	switch *onError {
+	case metrics.ErrorPolicySkip, metrics.ErrorPolicyFail, metrics.ErrorPolicyRetry:
+	default:
+		fatal(exitValidation, "On error must be 'skip', 'fail', or 'retry'")
+	}
+
+	switch *state {
+	case api.StateAll, api.StateOpen, api.StateMerged, api.StateClosed:
+	default:
+		fatal(exitValidation, "State must be 'merged', 'closed', 'open', or 'all'")
+	}
+
+	if *commitDate != metrics.CommitDateSourceAuthor && *commitDate != metrics.CommitDateSourceCommitter {
+		fatal(exitValidation, "Commit date must be 'author' or 'committer'")
+	}
+
+	if *dataQualityPolicy != metrics.DataQualityPolicyReport && *dataQualityPolicy != metrics.DataQualityPolicyClamp && *dataQualityPolicy != metrics.DataQualityPolicyExclude {
+		fatal(exitValidation, "Data quality policy must be 'report', 'clamp', or 'exclude'")
+	}
+
+	if *eventsFormat != "" && *eventsFormat != output.EventFormatCSV && *eventsFormat != output.EventFormatJSONL {
+		fatal(exitValidation, "Events format must be 'csv' or 'jsonl'")
+	}
+
+	var timelineFormatList []string
+	if *timelineFormats != "" {
+		timelineFormatList = strings.Split(*timelineFormats, ",")
+		for _, format := range timelineFormatList {
+			if format != output.TimelineFormatJSON && format != output.TimelineFormatSVG {
+				fatal(exitValidation, "Timeline format must be 'json' or 'svg'")
+			}
+		}
+	}
+	if *timelineCount < 1 {
+		fatal(exitValidation, "Timeline count must be at least 1")
+	}
+	if *busFactorDepth < 1 {
+		fatal(exitValidation, "Bus factor depth must be at least 1")
+	}
+	var timelinePRNumbers []int
+	if *timelinePRs != "" {
+		for _, field := range strings.Split(*timelinePRs, ",") {
+			number, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(field, "#")))
+			if err != nil {
+				fatal(exitValidation, "Invalid PR number in --timeline-prs: %s", field)
+			}
+			timelinePRNumbers = append(timelinePRNumbers, number)
+		}
+	}
+
+	var reportFormatList []string
+	if *reportFormats != "" {
+		reportFormatList = strings.Split(*reportFormats, ",")
+		for _, format := range reportFormatList {
+			if format != output.ReportFormatHTML && format != output.ReportFormatMarkdown && format != output.ReportFormatJSON {
+				fatal(exitValidation, "Report format must be 'html', 'md', or 'json'")
+			}
+		}
+	}
+
+	var chartFormatList []string
+	if *chartFormats != "" {
+		chartFormatList = strings.Split(*chartFormats, ",")
+		for _, format := range chartFormatList {
+			if format != output.ChartFormatSVG && format != output.ChartFormatPNG {
+				fatal(exitValidation, "Chart format must be 'svg' or 'png'")
+			}
+		}
+	}
+
+	if len(cfg.WorkingAgreements) > 0 && len(reportFormatList) == 0 && !*notifyDestinations {
+		logger.Warn("Working agreements are scored in the report; add --report to see them")
+	}
+
+	if *notifyDestinations && cfg.Notify.IsEmpty() {
+		fatal(exitValidation, "Notifications require a notify section in the config file")
+	}
+
+	if *logFormat != utils.LogFormatText && *logFormat != utils.LogFormatJSON {
+		fatal(exitValidation, "Log format must be 'text' or 'json'")
+	}
+
+	if *maxRequests < 0 || *minRemaining < 0 {
+		fatal(exitValidation, "Request budget options must not be negative")
+	}
+
+	if *compression != "" && *compression != output.CompressionGzip && *compression != output.CompressionZip {
+		fatal(exitValidation, "Compression must be 'gzip' or 'zip'")
+	}
+	if *compression == output.CompressionZip && (*appendMode || backfillMode) {
+		fatal(exitValidation, "--compress zip cannot be combined with --append or backfill, which read the outputs back; use gzip")
+	}
+
+	if *uploadURL != "" {
+		destination, err := upload.ParseDestination(*uploadURL)
+		if err != nil {
+			fatal(exitValidation, "%v", err)
+		}
+		uploader = upload.NewUploader(destination, logger)
+	}
+
+	if *sampleSize < 0 || *maxPRs < 0 {
+		fatal(exitValidation, "Sample size and PR limit must not be negative")
+	}
+	if *sampleSize > 0 && *maxPRs > 0 {
+		fatal(exitValidation, "--sample and --max-prs cannot be combined")
+	}
+
+	if backfillMode {
+		if *startDate == "" || *endDate == "" {
+			fatal(exitValidation, "Backfill requires --start-date and --end-date")
+		}
+		if *updatedSince != "" {
+			fatal(exitValidation, "Backfill does not support --updated-since")
+		}
+		if *sampleSize > 0 || *maxPRs > 0 {
+			fatal(exitValidation, "Backfill does not support --sample or --max-prs")
+		}
+		// Pace requests so a long backfill does not run into the rate limit
+		if !isFlagSet("min-remaining") {
+			*minRemaining = backfillMinRemaining
+		}
+	}
+
+	if prefetchMode {
+		if *browse {
+			fatal(exitValidation, "Prefetch calculates no metrics to browse; remove --tui")
+		}
+		if *cacheDir == "" && *recordDir == "" {
+			fatal(exitValidation, "Prefetch keeps the responses in --cache-dir or --record; set one of them")
+		}
+		if *replayDir != "" {
+			fatal(exitValidation, "Prefetch cannot be combined with --replay")
+		}
+	}
+
+	if *cacheTTL < 0 {
+		fatal(exitValidation, "Cache TTL must not be negative")
+	}
+	if *cacheTTL > 0 && *cacheDir == "" {
+		fatal(exitValidation, "Cache TTL applies only to the response cache; set --cache-dir")
+	}
+
+	// Build the business calendar if durations are measured in working hours
+	var businessCalendar *calendar.Calendar
+	if *businessHours != "" {
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			fatal(exitValidation, "Invalid time zone: %v", err)
+		}
+		businessCalendar, err = calendar.New(location, *businessHours)
+		if err != nil {
+			fatal(exitValidation, "%v", err)
+		}
+		if *holidayCountry != "" {
+			if err := businessCalendar.SetCountry(*holidayCountry); err != nil {
+				fatal(exitValidation, "%v", err)
+			}
+		}
+		if *holidaysFile != "" {
+			if err := businessCalendar.LoadHolidays(*holidaysFile); err != nil {
+				fatal(exitValidation, "Failed to load holidays: %v", err)
+			}
+		}
+	} else if *holidaysFile != "" || *holidayCountry != "" {
+		fatal(exitValidation, "Holidays apply only to business hours; set --business-hours")
 	}
 
 	// Parse repository owner and name
-	parts := strings.Split(*repo, "/")
-	if len(parts) != 2 {
-		logger.Fatal("Repository name must be in format 'owner/repo'")
+	owner, repoName, err := parseRepository(*provider, *repo)
+	if err != nil {
+		fatal(exitValidation, "%v", err)
+	}
+	logger = logger.With("repo", *repo)
+
+	// Use the provider's public API unless a URL was given explicitly
+	if !isFlagSet("url", "u") {
+		*githubURL = api.DefaultAPIURL(*provider)
+		if *githubURL == "" && *replayDir == "" {
+			fatal(exitValidation, "API URL is required for the %s provider", *provider)
+		}
+	}
+	if *provider == api.ProviderGitHub {
+		if normalized := api.NormalizeGitHubURL(*githubURL); normalized != *githubURL {
+			logger.Debug("Using %s as the GitHub API URL for %s", normalized, *githubURL)
+			*githubURL = normalized
+		}
 	}
-	owner, repoName := parts[0], parts[1]
 
 	// Parse dates
 	var start, end time.Time
-	var err error
 
 	if *startDate != "" {
 		start, err = time.Parse("2006-01-02", *startDate)
 		if err != nil {
-			logger.Fatal("Invalid start date format: %v", err)
+			fatal(exitValidation, "Invalid start date format: %v", err)
 		}
 	} else {
 		// Default to 7 days ago
@@ -77,58 +459,780 @@ func main() {
 	if *endDate != "" {
 		end, err = time.Parse("2006-01-02", *endDate)
 		if err != nil {
-			logger.Fatal("Invalid end date format: %v", err)
+			fatal(exitValidation, "Invalid end date format: %v", err)
 		}
 	} else {
 		// Default to today
 		end = time.Now()
 	}
 
+	var updated time.Time
+	if *updatedSince != "" {
+		updated, err = time.Parse("2006-01-02", *updatedSince)
+		if err != nil {
+			fatal(exitValidation, "Invalid updated-since date format: %v", err)
+		}
+	}
+
+	// Name output files after the repository and date range if requested
+	if *outputNameTemplate != "" {
+		templateNamer, err := output.NewFileNamer(*outputDir, *outputNameTemplate, map[string]string{
+			"owner": owner,
+			"repo":  repoName,
+			"start": start.Format("2006-01-02"),
+			"end":   end.Format("2006-01-02"),
+		})
+		if err != nil {
+			fatal(exitValidation, "%v", err)
+		}
+		namer = templateNamer
+	}
+
 	logger.Info("Fetching PR metrics for %s/%s from %s to %s", owner, repoName, start.Format("2006-01-02"), end.Format("2006-01-02"))
 
-	// Create GitHub API client
-	client, err := api.NewClient(*githubURL, *token, logger)
+	// Create API client for the selected provider, or replay recorded responses
+	var apiClient api.Provider
+	if *replayDir != "" {
+		apiClient, err = api.NewFixtureClient(*replayDir, logger)
+	} else {
+		apiClient, err = api.NewProvider(*provider, *githubURL, tokens, logger)
+	}
 	if err != nil {
-		logger.Fatal("Failed to create GitHub API client: %v", err)
+		fatal(exitValidation, "Failed to create API client: %v", err)
+	}
+	client = apiClient
+	if *proxy != "" || *caCert != "" || *insecureSkipVerify || !cfg.HTTP.IsEmpty() {
+		if *insecureSkipVerify {
+			logger.Warn("TLS certificate verification is disabled")
+		}
+		if err := client.ConfigureTransport(api.TransportOptions{
+			ProxyURL:           *proxy,
+			CACertFile:         *caCert,
+			InsecureSkipVerify: *insecureSkipVerify,
+			Headers:            cfg.HTTP,
+		}); err != nil {
+			fatal(exitValidation, "Failed to configure network settings: %v", err)
+		}
+	}
+	// Verify the token can read the repository before the long fetch; cached responses would hide a revoked token
+	if checker, ok := client.(api.AccessChecker); ok {
+		if err := checker.CheckAccess(owner, repoName); err != nil {
+			fatal(exitCodeForError(err), "Cannot access %s/%s: %v", owner, repoName, err)
+		}
+	}
+	if *cacheDir != "" {
+		if err := client.EnableResponseCache(api.CacheOptions{
+			Dir: *cacheDir,
+			TTL: *cacheTTL,
+		}); err != nil {
+			fatal(exitError, "Failed to create response cache: %v", err)
+		}
 	}
+	client.SetRequestBudget(api.RequestBudget{
+		MaxRequests:  *maxRequests,
+		MinRemaining: *minRemaining,
+	})
+	if *onError == metrics.ErrorPolicyRetry {
+		client = api.NewRetryingProvider(client, logger)
+	}
+	if *recordDir != "" {
+		client = api.NewRecordingProvider(client, *recordDir, logger)
+	}
+	// Replays are converted like the provider they were recorded from
+	converter := api.NewConverter(*provider)
+	source := api.NewSource(client, converter)
 
-	// Get pull requests
-	logger.Debug("Fetching pull requests...")
-	prs, err := client.GetPullRequests(owner, repoName, start, end)
+	// Describe the repository for comparisons across repositories; the run goes on with only its name
+	repository, err := client.GetRepository(owner, repoName)
 	if err != nil {
-		logger.Fatal("Failed to fetch pull requests: %v", err)
+		logger.Warn("Failed to get repository metadata: %v", err)
+	}
+	if repository != nil {
+		report.Repositories = append(report.Repositories, api.NewRepository(repository))
+	} else {
+		report.Repositories = append(report.Repositories, api.Repository{FullName: owner + "/" + repoName})
+	}
+
+	// Get pull requests; a backfill lists them month by month instead, so completed months are
+	// not listed again when it resumes
+	listPullRequests := func(start, end time.Time) []*github.PullRequest {
+		logger.Debug("Fetching pull requests...")
+		prs, err := client.GetPullRequests(owner, repoName, api.PullRequestQuery{
+			StartDate:    start,
+			EndDate:      end,
+			DateField:    *dateField,
+			UpdatedSince: updated,
+			State:        *state,
+		})
+		if err != nil {
+			fatal(exitCodeForError(err), "Failed to fetch pull requests: %v", err)
+		}
+		report.PRsFetched += len(prs)
+		return prs
+	}
+	report.ReposProcessed = 1
+	var prs []*github.PullRequest
+	if !backfillMode {
+		prs = listPullRequests(start, end)
+		logger.Info("Found %d pull requests", len(prs))
 	}
 
-	logger.Info("Found %d pull requests", len(prs))
+	// Narrow huge ranges down to a random sample or the most recent PRs if requested
+	if *sampleSize > 0 || *maxPRs > 0 {
+		sampling := &api.Sampling{Method: api.SamplingMethodMostRecent}
+		fetched := len(prs)
+		if *sampleSize > 0 {
+			sampling.Method = api.SamplingMethodRandom
+			sampling.Seed = *sampleSeed
+			if sampling.Seed == 0 {
+				sampling.Seed = rand.Uint64()
+			}
+			prs = samplePullRequests(prs, *sampleSize, sampling.Seed)
+		} else {
+			prs = mostRecentPullRequests(prs, *maxPRs)
+		}
+
+		sampling.PRsAnalyzed = len(prs)
+		if fetched > 0 {
+			sampling.Fraction = float64(len(prs)) / float64(fetched)
+		}
+		report.Sampling = sampling
+		if len(prs) < fetched {
+			logger.Warn("Analyzing %d of %d pull requests (%.1f%%); metrics are approximate", len(prs), fetched, sampling.Fraction*100)
+		}
+	}
+
+	// Only fill the response cache or recording when prefetching
+	if prefetchMode {
+		prefetcher := metrics.NewPrefetcher(source, logger, cfg.SizeExclusions.GitAttributes, *ciMetrics)
+		fetched := prefetcher.PrefetchAll(owner, repoName, api.NewPullRequests(converter, prs))
+		report.PRsFailed = len(prs) - fetched
+
+		prErrors := prefetcher.Errors()
+		if err := os.MkdirAll(namer.Dir(), 0755); err != nil {
+			fatal(exitError, "Failed to create output directory: %v", err)
+		}
+		if err := output.NewCSVWriter(logger, cfg.CSV).WriteErrorsCSV(namer.Path("errors.csv"), prErrors); err != nil {
+			fatal(exitError, "Failed to write errors: %v", err)
+		}
+		if len(prErrors) > 0 {
+			logger.Warn("Encountered %d errors while prefetching; see errors.csv for details", len(prErrors))
+		}
+		if client.Usage().BudgetExhausted {
+			fatal(exitRateLimit, "Stopped early because the API request budget was exhausted; run the same command again to continue")
+		}
+		if len(prErrors) > 0 && *strict {
+			fatal(exitPartialFailure, "Exiting with failure because --strict is set")
+		}
+		exit(exitOK)
+	}
+
+	// Open the local clone for commit-level analysis if requested
+	var localRepository *localgit.Repository
+	if *localGitDir != "" {
+		cloneURL := *localGitURL
+		if cloneURL == "" {
+			cloneURL = defaultCloneURL(*provider, *githubURL, owner, repoName)
+		}
+		localRepository, err = localgit.Open(*localGitDir, cloneURL, logger)
+		if err != nil {
+			fatal(exitError, "Failed to open local clone: %v", err)
+		}
+	}
+
+	// Connect to Jira to measure lead times from issue creation if configured
+	var jiraClient *jira.Client
+	if cfg.IssueKeys.Jira != nil {
+		jiraClient, err = jira.NewClient(*cfg.IssueKeys.Jira, logger)
+		if err != nil {
+			fatal(exitAuth, "Failed to connect to Jira: %v", err)
+		}
+	}
+
+	// Show how far the fetch has come in place of the logs when the PRs are browsed afterward
+	var progress *tui.Progress
+	var onProgress func(done, total int)
+	if *browse {
+		progress = tui.NewProgress(os.Stderr, isTerminal(os.Stderr))
+		onProgress = progress.Update
+	}
 
 	// Calculate metrics for each pull request
-	calculator := metrics.NewCalculator(client, logger)
-	prMetrics, err := calculator.CalculateAllPRMetrics(owner, repoName, prs)
-	if err != nil {
-		logger.Fatal("Failed to calculate PR metrics: %v", err)
+	calculator := metrics.NewCalculator(source, logger, metrics.Options{
+		CommitDateSource:  *commitDate,
+		ClampCommitTimes:  *clampCommitTimes,
+		Calendar:          businessCalendar,
+		LocalGit:          localRepository,
+		TestFilePatterns:  cfg.TestFiles.Patterns,
+		SizeExclusions:    cfg.SizeExclusions,
+		Categories:        cfg.Categories,
+		IssueKeys:         cfg.IssueKeys,
+		Jira:              jiraClient,
+		DependencyUpdates: cfg.DependencyUpdates,
+		OnError:           *onError,
+		CIMetrics:         *ciMetrics,
+		Progress:          onProgress,
+	})
+	calculate := func(prs []*github.PullRequest) ([]*api.PRMetrics, []*api.DataQualityIssue) {
+		prMetrics, err := calculator.CalculateAllPRMetrics(owner, repoName, api.NewPullRequests(converter, prs))
+		if err != nil {
+			fatal(exitError, "Stopping because --on-error is %s: %v", *onError, err)
+		}
+
+		// Detect impossible values and apply the data quality policy
+		prMetrics, dataQualityIssues := metrics.NewDataQualityChecker(logger).Check(prMetrics, *dataQualityPolicy)
+
+		// Pseudonymize logins before anything is written
+		if *anonymize {
+			output.NewAnonymizer(*anonymizeSalt).AnonymizePRMetrics(prMetrics)
+		}
+
+		return prMetrics, dataQualityIssues
+	}
+
+	// Merge with the PRs collected by earlier runs
+	csvWriter := output.NewCSVWriter(logger, cfg.CSV)
+	readExistingMetrics := func() []*api.PRMetrics {
+		metadata, err := output.ReadMetadata(namer.Path("metadata.json"))
+		if err != nil {
+			fatal(exitError, "Failed to read existing metadata: %v", err)
+		}
+		existingMetrics, err := csvWriter.ReadPRMetricsCSVVersion(namer.Path("pr_metrics.csv"), metadata.SchemaVersion)
+		if err != nil && !os.IsNotExist(err) {
+			fatal(exitError, "Failed to read existing PR metrics: %v", err)
+		}
+		return existingMetrics
+	}
+
+	var prMetrics []*api.PRMetrics
+	var dataQualityIssues []*api.DataQualityIssue
+	if backfillMode {
+		// Process one month at a time, checkpointing after each so an interrupted backfill can resume
+		checkpointPath := namer.Path("backfill_checkpoint.json")
+		checkpoint := backfill.NewCheckpoint(*repo, start, end, *dateField)
+		savedCheckpoint, err := backfill.LoadCheckpoint(checkpointPath)
+		switch {
+		case err == nil && !savedCheckpoint.Matches(checkpoint):
+			fatal(exitValidation, "%s belongs to a different backfill; delete it or use another output directory", checkpointPath)
+		case err == nil:
+			checkpoint = savedCheckpoint
+		case !os.IsNotExist(err):
+			fatal(exitError, "Failed to read backfill checkpoint: %v", err)
+		}
+
+		if len(checkpoint.CompletedChunks) > 0 || *appendMode {
+			prMetrics = readExistingMetrics()
+		}
+		if err := os.MkdirAll(namer.Dir(), 0755); err != nil {
+			fatal(exitError, "Failed to create output directory: %v", err)
+		}
+
+		chunks := backfill.MonthlyChunks(start, end)
+		for i, chunk := range chunks {
+			if checkpoint.IsCompleted(chunk) {
+				logger.Debug("Skipping %s, completed by an earlier run", chunk.Name)
+				continue
+			}
+
+			chunkPRs := listPullRequests(chunk.Start, chunk.End)
+			logger.Info("Backfilling %s (%d of %d): %d pull requests", chunk.Name, i+1, len(chunks), len(chunkPRs))
+			if progress != nil {
+				progress.Start(fmt.Sprintf("Backfilling %s (%d of %d)", chunk.Name, i+1, len(chunks)))
+			}
+
+			skippedBefore := countSkipped(calculator.Errors())
+			chunkMetrics, chunkIssues := calculate(chunkPRs)
+			prMetrics = output.MergePRMetrics(prMetrics, chunkMetrics)
+			dataQualityIssues = append(dataQualityIssues, chunkIssues...)
+
+			if err := csvWriter.WriteCSV(namer.Path("pr_metrics.csv"), prMetrics); err != nil {
+				fatal(exitError, "Failed to write PR metrics: %v", err)
+			}
+
+			// A month with skipped PRs is processed again when the backfill is resumed
+			if client.Usage().BudgetExhausted {
+				logger.Warn("Request budget exhausted during %s; run the same command again to resume", chunk.Name)
+				break
+			}
+			if skipped := countSkipped(calculator.Errors()) - skippedBefore; skipped > 0 {
+				logger.Warn("%d pull requests of %s were skipped; run the same command again to retry them", skipped, chunk.Name)
+				continue
+			}
+
+			checkpoint.Complete(chunk)
+			if err := checkpoint.Save(checkpointPath); err != nil {
+				fatal(exitError, "Failed to save backfill checkpoint: %v", err)
+			}
+		}
+	} else {
+		if progress != nil {
+			progress.Start("Fetching PR data")
+		}
+		prMetrics, dataQualityIssues = calculate(prs)
+		if *appendMode {
+			prMetrics = output.MergePRMetrics(readExistingMetrics(), prMetrics)
+			logger.Info("Merged into %d PRs collected so far", len(prMetrics))
+		}
+	}
+
+	if progress != nil {
+		progress.Finish()
+	}
+
+	// Link stacked PRs to their parents across all PRs, including those of earlier runs
+	if stacked := calculator.DetectStacks(prMetrics); stacked > 0 {
+		logger.Info("Found %d stacked pull requests", stacked)
+	}
+
+	// Keep dependency updates from skewing the aggregates of human PRs if requested
+	aggregatedPRs := prMetrics
+	var dependencyUpdatePRs []*api.PRMetrics
+	if *dependencyUpdates {
+		dependencyUpdatePRs, aggregatedPRs = metrics.SplitDependencyUpdates(prMetrics)
+		logger.Info("Found %d dependency update PRs; leaving them out of the aggregated metrics", len(dependencyUpdatePRs))
 	}
 
 	// Calculate weekly and monthly aggregated metrics
 	logger.Debug("Calculating weekly aggregated metrics...")
-	weeklyMetrics, err := calculator.CalculateWeeklyAggregatedMetrics(prMetrics)
+	weeklyMetrics, err := calculator.CalculateWeeklyAggregatedMetrics(aggregatedPRs)
 	if err != nil {
-		logger.Fatal("Failed to calculate weekly metrics: %v", err)
+		fatal(exitError, "Failed to calculate weekly metrics: %v", err)
 	}
 	logger.Info("Calculated metrics for %d weeks", len(weeklyMetrics))
 
 	logger.Debug("Calculating monthly aggregated metrics...")
-	monthlyMetrics, err := calculator.CalculateMonthlyAggregatedMetrics(prMetrics)
+	monthlyMetrics, err := calculator.CalculateMonthlyAggregatedMetrics(aggregatedPRs)
 	if err != nil {
-		logger.Fatal("Failed to calculate monthly metrics: %v", err)
+		fatal(exitError, "Failed to calculate monthly metrics: %v", err)
 	}
 	logger.Info("Calculated metrics for %d months", len(monthlyMetrics))
 
+	// Check the alert rules against the most recent periods
+	alerts := alert.Evaluate(cfg.Alerts, weeklyMetrics, monthlyMetrics)
+	for _, triggered := range alerts {
+		logger.Warn("Alert triggered: %s", triggered.Message())
+	}
+	report.Alerts = alerts
+
 	// Write metrics to CSV files in the output directory
-	csvWriter := output.NewCSVWriter(logger)
-	err = csvWriter.WriteToDirectory(*outputDir, prMetrics, weeklyMetrics, monthlyMetrics)
+	err = csvWriter.WriteToDirectory(namer, prMetrics, weeklyMetrics, monthlyMetrics)
 	if err != nil {
-		logger.Fatal("Failed to write CSV files: %v", err)
+		fatal(exitError, "Failed to write CSV files: %v", err)
+	}
+
+	// Write data quality issues alongside the metrics
+	if err := csvWriter.WriteDataQualityCSV(namer.Path("data_quality.csv"), dataQualityIssues); err != nil {
+		fatal(exitError, "Failed to write data quality report: %v", err)
+	}
+
+	// Track label SLOs if the config defines any
+	if len(cfg.SLOs) > 0 {
+		attainments := metrics.CalculateSLOAttainment(aggregatedPRs, cfg.SLOs)
+		if err := csvWriter.WriteSLOCSV(namer.Path("slo_metrics.csv"), attainments); err != nil {
+			fatal(exitError, "Failed to write SLO metrics: %v", err)
+		}
+	}
+
+	// Break the aggregates down by category if the config defines category rules
+	if len(cfg.Categories) > 0 {
+		categoryMetrics, err := calculator.CalculateCategoryAggregatedMetrics(aggregatedPRs)
+		if err != nil {
+			fatal(exitError, "Failed to calculate category metrics: %v", err)
+		}
+		if err := csvWriter.WriteCategoryMetricsCSV(namer.Path("category_metrics.csv"), categoryMetrics); err != nil {
+			fatal(exitError, "Failed to write category metrics: %v", err)
+		}
+	}
+
+	// Report dependency updates separately if requested
+	if *dependencyUpdates {
+		updates := metrics.CalculateDependencyUpdateMetrics(dependencyUpdatePRs)
+		if err := csvWriter.WriteDependencyUpdatesCSV(namer.Path("dependency_updates.csv"), updates); err != nil {
+			fatal(exitError, "Failed to write dependency update metrics: %v", err)
+		}
+	}
+
+	// Export the normalized event stream if requested
+	if *eventsFormat != "" {
+		eventsFilePath := namer.Path("events." + *eventsFormat)
+		if err := output.NewEventWriter(logger).Write(eventsFilePath, *eventsFormat, prMetrics); err != nil {
+			fatal(exitError, "Failed to write events: %v", err)
+		}
+	}
+
+	// Lay out the slowest or chosen PRs as timelines if requested
+	if len(timelineFormatList) > 0 {
+		selected := output.SelectTimelinePRs(prMetrics, timelinePRNumbers, *timelineCount)
+		if err := output.NewTimelineWriter(logger).WriteToDirectory(namer, timelineFormatList, selected); err != nil {
+			fatal(exitError, "Failed to write timelines: %v", err)
+		}
+	}
+
+	// Rank reviewers, authors, and files if requested
+	if *leaderboard {
+		leaderboardOptions := cfg.Leaderboard
+		if *anonymize {
+			// Logins are already pseudonyms
+			leaderboardOptions.Anonymize = false
+		}
+		entries := output.BuildLeaderboards(prMetrics, leaderboardOptions)
+		if err := csvWriter.WriteLeaderboardCSV(namer.Path("leaderboard.csv"), entries); err != nil {
+			fatal(exitError, "Failed to write leaderboard: %v", err)
+		}
+	}
+
+	// Compare review latency between teams if the config maps logins to teams
+	if len(cfg.Teams) > 0 {
+		teams := cfg.Teams
+		if *anonymize {
+			teams = teams.Pseudonymized(output.NewAnonymizer(*anonymizeSalt))
+		}
+		latency := output.BuildTeamReviewLatency(prMetrics, teams)
+		if err := csvWriter.WriteTeamReviewLatencyCSV(namer.Path("team_review_latency.csv"), latency); err != nil {
+			fatal(exitError, "Failed to write team review latency: %v", err)
+		}
+	}
+
+	// Find frequently changed files and directories if requested
+	if *hotspots {
+		if err := csvWriter.WriteHotspotsCSV(namer.Path("hotspots.csv"), output.BuildHotspots(prMetrics)); err != nil {
+			fatal(exitError, "Failed to write hotspots: %v", err)
+		}
+	}
+
+	// Follow the PRs opened each week until they merge if requested
+	if *cohorts {
+		if err := csvWriter.WriteCohortsCSV(namer.Path("cohort_metrics.csv"), metrics.CalculateCohorts(aggregatedPRs)); err != nil {
+			fatal(exitError, "Failed to write cohort metrics: %v", err)
+		}
+	}
+
+	// Measure how often authors open PRs if requested
+	if *cadence {
+		if err := csvWriter.WriteAuthorCadenceCSV(namer.Path("author_cadence.csv"), metrics.CalculateAuthorCadence(aggregatedPRs)); err != nil {
+			fatal(exitError, "Failed to write author cadence: %v", err)
+		}
+		if err := csvWriter.WriteTeamCadenceCSV(namer.Path("team_cadence.csv"), metrics.CalculateTeamCadence(aggregatedPRs)); err != nil {
+			fatal(exitError, "Failed to write team cadence: %v", err)
+		}
+	}
+
+	// Lay out when PRs are opened, reviewed, and merged if requested
+	if *heatmap {
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			fatal(exitValidation, "Invalid time zone: %v", err)
+		}
+		if err := csvWriter.WriteHeatmapCSV(namer.Path("heatmap.csv"), metrics.CalculateHeatmap(aggregatedPRs, location)); err != nil {
+			fatal(exitError, "Failed to write heatmap: %v", err)
+		}
+	}
+
+	// Relate the wait for a first review to when the PR was opened if requested
+	if *creationTimeLatency {
+		location, err := time.LoadLocation(*timezone)
+		if err != nil {
+			fatal(exitValidation, "Invalid time zone: %v", err)
+		}
+		latency := metrics.CalculateCreationTimeReviewLatency(aggregatedPRs, location)
+		if err := csvWriter.WriteCreationTimeReviewLatencyCSV(namer.Path("review_latency_by_creation_time.csv"), latency); err != nil {
+			fatal(exitError, "Failed to write review latency by creation time: %v", err)
+		}
+	}
+
+	// Compare review requests with the reviews they led to if requested
+	if *reviewRouting {
+		if err := csvWriter.WriteReviewRoutingCSV(namer.Path("review_routing.csv"), metrics.CalculateReviewRouting(aggregatedPRs)); err != nil {
+			fatal(exitError, "Failed to write review routing: %v", err)
+		}
+	}
+
+	// Count the reviewers of each component if requested
+	if *busFactor {
+		busFactors := metrics.CalculateBusFactor(aggregatedPRs, *busFactorDepth)
+		if err := csvWriter.WriteBusFactorCSV(namer.Path("bus_factor.csv"), busFactors); err != nil {
+			fatal(exitError, "Failed to write bus factor: %v", err)
+		}
+	}
+
+	// Break changed lines down by language if requested
+	if *languages {
+		if err := csvWriter.WriteLanguageMixCSV(namer.Path("language_metrics.csv"), metrics.CalculateLanguageMix(prMetrics)); err != nil {
+			fatal(exitError, "Failed to write language mix: %v", err)
+		}
+	}
+
+	// Break review comments down by the type of file they were left on if requested
+	if *commentsByFileType {
+		fileTypeComments := metrics.CalculateFileTypeComments(aggregatedPRs, cfg.TestFiles.Patterns)
+		if err := csvWriter.WriteFileTypeCommentsCSV(namer.Path("comments_by_file_type.csv"), fileTypeComments); err != nil {
+			fatal(exitError, "Failed to write comments by file type: %v", err)
+		}
+	}
+
+	// Write the workbook if requested
+	if *xlsx {
+		overallMetrics := calculator.CalculateOverallAggregatedMetrics(aggregatedPRs)
+		if err := output.NewXLSXWriter(logger).Write(namer.Path("metrics.xlsx"), prMetrics, weeklyMetrics, monthlyMetrics, overallMetrics); err != nil {
+			fatal(exitError, "Failed to write XLSX workbook: %v", err)
+		}
+	}
+
+	// Render the report if requested
+	summaryReport := output.NewReport(*repo, start, end, prMetrics, weeklyMetrics)
+	summaryReport.Alerts = alerts
+	summaryReport.Locale = locale
+	if len(cfg.WorkingAgreements) > 0 {
+		summaryReport.WorkingAgreements = agreement.Score(cfg.WorkingAgreements, aggregatedPRs)
+	}
+	if len(reportFormatList) > 0 {
+		if err := output.NewReportWriter(logger).WriteToDirectory(namer, reportFormatList, summaryReport); err != nil {
+			fatal(exitError, "Failed to write report: %v", err)
+		}
+	}
+
+	// Render the charts if requested
+	if len(chartFormatList) > 0 {
+		if err := output.NewChartWriter(logger).WriteToDirectory(namer, chartFormatList, weeklyMetrics); err != nil {
+			fatal(exitError, "Failed to write charts: %v", err)
+		}
+	}
+
+	// Send the summary to the configured destinations, and always when an alert was triggered
+	if *notifyDestinations || (len(alerts) > 0 && !cfg.Notify.IsEmpty()) {
+		if err := notify.Send(cfg.Notify, summaryReport, logger); err != nil {
+			fatal(exitError, "Failed to send notifications: %v", err)
+		}
 	}
 
 	logger.Info("Successfully wrote metrics for %d pull requests to directory: %s", len(prMetrics), *outputDir)
+
+	// Compare the last week of the range with the weeks before it, so the usual question is
+	// answered without opening the files; the browser shows the PRs instead
+	if !*quiet && !*browse {
+		reference := end
+		if now := time.Now(); reference.After(now) {
+			reference = now
+		}
+		if err := output.WriteTrendSummary(os.Stdout, weeklyMetrics, reference); err != nil {
+			logger.Warn("Failed to print the trend summary: %v", err)
+		}
+	}
+
+	// Summarize failures and write them to errors.csv
+	prErrors := calculator.Errors()
+	if err := csvWriter.WriteErrorsCSV(namer.Path("errors.csv"), prErrors); err != nil {
+		fatal(exitError, "Failed to write errors: %v", err)
+	}
+	if len(prErrors) > 0 {
+		skipped := countSkipped(prErrors)
+		logger.Warn("Encountered %d errors (%d PRs skipped); see errors.csv for details", len(prErrors), skipped)
+
+		report.PRsFailed = skipped
+	}
+
+	// Compress the outputs if requested, leaving the run report written on exit as it is
+	if *compression != "" {
+		if _, err := output.CompressOutputs(namer, *compression, logger); err != nil {
+			fatal(exitError, "Failed to compress outputs: %v", err)
+		}
+	}
+	outputsWritten = true
+
+	// Browse the PRs once everything is written, including the partial results of a run that
+	// stopped early
+	if *browse {
+		var events []api.PREvent
+		for _, pr := range prMetrics {
+			events = append(events, pr.Events...)
+		}
+		if err := tui.NewBrowser(prMetrics, events, os.Stdin, os.Stdout, isTerminal(os.Stdout)).Run(); err != nil {
+			logger.Error("%v", err)
+		}
+	}
+
+	// Partial results have been written; report why the run stopped early
+	if client.Usage().BudgetExhausted {
+		fatal(exitRateLimit, "Stopped early because the API request budget was exhausted")
+	}
+
+	if len(prErrors) > 0 && *strict {
+		fatal(exitPartialFailure, "Exiting with failure because --strict is set")
+	}
+
+	exit(exitOK)
+}
+
+// Counts the PRs left out of the outputs because of errors
+func countSkipped(prErrors []*api.PRError) int {
+	skipped := 0
+	for _, prError := range prErrors {
+		if prError.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// Draws a uniform random sample of PRs, keeping them in their original order
+func samplePullRequests(prs []*github.PullRequest, size int, seed uint64) []*github.PullRequest {
+	if len(prs) <= size {
+		return prs
+	}
+
+	indexes := rand.New(rand.NewPCG(seed, seed)).Perm(len(prs))[:size]
+	slices.Sort(indexes)
+	sample := make([]*github.PullRequest, size)
+	for i, index := range indexes {
+		sample[i] = prs[index]
+	}
+	return sample
+}
+
+// Keeps the most recently created PRs, in their original order
+func mostRecentPullRequests(prs []*github.PullRequest, limit int) []*github.PullRequest {
+	if len(prs) <= limit {
+		return prs
+	}
+
+	newest := slices.Clone(prs)
+	slices.SortStableFunc(newest, func(a, b *github.PullRequest) int {
+		return b.GetCreatedAt().Compare(a.GetCreatedAt().Time)
+	})
+	kept := make(map[*github.PullRequest]bool, limit)
+	for _, pr := range newest[:limit] {
+		kept[pr] = true
+	}
+
+	var recent []*github.PullRequest
+	for _, pr := range prs {
+		if kept[pr] {
+			recent = append(recent, pr)
+		}
+	}
+	return recent
+}
+
+// Maps an API error to the exit code automation can react to
+func exitCodeForError(err error) int {
+	switch {
+	case api.IsRateLimitError(err):
+		return exitRateLimit
+	case api.IsAuthError(err):
+		return exitAuth
+	default:
+		return exitError
+	}
+}
+
+// Collects the tokens given on the command line and in the token file, skipping
+// blank lines and lines starting with #
+func loadTokens(value, file string) ([]string, error) {
+	var tokens []string
+	for _, token := range strings.Split(value, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				tokens = append(tokens, line)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// Logs where the API requests of the run went, with the busiest endpoints first
+func logAPIUsage(logger *utils.Logger, usage api.APIUsage) {
+	if usage.Requests == 0 {
+		return
+	}
+
+	var bytes int64
+	for _, endpoint := range usage.Endpoints {
+		bytes += endpoint.Bytes
+		logger.Debug("API endpoint %s: %d requests (%d from cache), %d bytes, latency p50 %.0fms, p90 %.0fms, p99 %.0fms",
+			endpoint.Endpoint, endpoint.Requests, endpoint.CacheHits, endpoint.Bytes, endpoint.LatencyP50Ms, endpoint.LatencyP90Ms, endpoint.LatencyP99Ms)
+	}
+
+	if usage.RateLimitRemaining >= 0 {
+		logger.Info("Made %d API requests to %d endpoints (%d bytes received, rate limit remaining: %d)", usage.Requests, len(usage.Endpoints), bytes, usage.RateLimitRemaining)
+	} else {
+		logger.Info("Made %d API requests to %d endpoints (%d bytes received)", usage.Requests, len(usage.Endpoints), bytes)
+	}
+}
+
+// Derives the clone URL of a GitHub or GitHub Enterprise repository from the API URL,
+// returning an empty string for other providers
+func defaultCloneURL(provider, apiURL, owner, repo string) string {
+	if provider != api.ProviderGitHub {
+		return ""
+	}
+	webURL := strings.TrimSuffix(strings.TrimSuffix(apiURL, "/"), "/api/v3")
+	if webURL == "https://api.github.com" {
+		webURL = "https://github.com"
+	}
+	return webURL + "/" + owner + "/" + repo + ".git"
+}
+
+// Splits a repository name into owner and name, allowing nested groups for GitLab
+// and "organization/project/repo" for Azure DevOps
+func parseRepository(provider, repo string) (string, string, error) {
+	if provider == api.ProviderAzureDevOps {
+		parts := strings.Split(repo, "/")
+		if len(parts) != 3 {
+			return "", "", fmt.Errorf("repository name must be in format 'organization/project/repo'")
+		}
+		return parts[0] + "/" + parts[1], parts[2], nil
+	}
+
+	if provider == api.ProviderGitLab {
+		index := strings.LastIndex(repo, "/")
+		if index <= 0 || index == len(repo)-1 {
+			return "", "", fmt.Errorf("repository name must be in format 'group/project'")
+		}
+		return repo[:index], repo[index+1:], nil
+	}
+
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("repository name must be in format 'owner/repo'")
+	}
+	return parts[0], parts[1], nil
+}
+
+// Reports whether any of the named flags was given on the command line
+func isFlagSet(names ...string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if slices.Contains(names, f.Name) {
+			set = true
+		}
+	})
+	return set
+}
+
+// Fills the CSV layout the config file left unset from the named locale, and returns the locale
+// for the report, or the default formatting if none is named
+func applyLocale(cfg *config.Config, name string) (output.Locale, error) {
+	if name == "" {
+		return output.Locale{}, nil
+	}
+	locale, err := output.LookupLocale(name)
+	if err != nil {
+		return output.Locale{}, err
+	}
+	cfg.CSV = cfg.CSV.WithLocale(locale)
+	if err := cfg.CSV.Validate(); err != nil {
+		return output.Locale{}, fmt.Errorf("invalid CSV layout for locale %s: %v", locale.Name, err)
+	}
+	return locale, nil
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Rewrites the outputs of an earlier release in the current schema and returns the exit code
+func runMigrateOutput(args []string) int {
+	flags := flag.NewFlagSet("migrate-output", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON config file the outputs were written with, for the CSV layout")
+	localeName := flags.String("locale", "", "Locale the outputs were written with, for the CSV layout")
+	force := flags.Bool("force", false, "Rewrite the outputs even if they are already in the current schema")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s migrate-output [flags] PATH\n\nPATH is an output directory or a pr_metrics.csv file, possibly renamed by --output-name-template.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   !*verbose,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitValidation
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+	if _, err := applyLocale(cfg, *localeName); err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	namer, err := outputNamer(flags.Arg(0))
+	if err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	metadata, err := output.ReadMetadata(namer.Path("metadata.json"))
+	if err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+	switch {
+	case metadata.SchemaVersion > output.SchemaVersion:
+		logger.Error("Outputs use schema version %d, newer than version %d of this release; upgrade the tool instead", metadata.SchemaVersion, output.SchemaVersion)
+		return exitValidation
+	case metadata.SchemaVersion == output.SchemaVersion && !*force:
+		fmt.Printf("Outputs are already in schema version %d\n", output.SchemaVersion)
+		return exitOK
+	}
+
+	csvWriter := output.NewCSVWriter(logger, cfg.CSV)
+	prMetrics, err := csvWriter.ReadPRMetricsCSVVersion(namer.Path("pr_metrics.csv"), metadata.SchemaVersion)
+	if err != nil {
+		logger.Error("Failed to read PR metrics: %v", err)
+		return exitError
+	}
+
+	calculator := metrics.NewAggregatedMetricsCalculator(logger)
+	weeklyMetrics, err := calculator.CalculateWeeklyAggregatedMetrics(prMetrics)
+	if err != nil {
+		logger.Error("Failed to calculate weekly metrics: %v", err)
+		return exitError
+	}
+	monthlyMetrics, err := calculator.CalculateMonthlyAggregatedMetrics(prMetrics)
+	if err != nil {
+		logger.Error("Failed to calculate monthly metrics: %v", err)
+		return exitError
+	}
+
+	if err := csvWriter.WriteToDirectory(namer, prMetrics, weeklyMetrics, monthlyMetrics); err != nil {
+		logger.Error("Failed to write CSV files: %v", err)
+		return exitError
+	}
+
+	fmt.Printf("Migrated %d PRs from schema version %d to %d\n", len(prMetrics), metadata.SchemaVersion, output.SchemaVersion)
+	return exitOK
+}
+
+// Returns the namer of the outputs in a directory or next to a pr_metrics.csv file. A file
+// renamed by --output-name-template gives the template the other files were named with.
+func outputNamer(path string) (*output.FileNamer, error) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return output.NewFileNamer(path, "", nil)
+	}
+
+	// A gzipped file is read through the name it had before --compress
+	name := strings.TrimSuffix(filepath.Base(path), ".gz")
+	if !strings.Contains(name, "pr_metrics.csv") {
+		return nil, fmt.Errorf("%s is not a pr_metrics.csv file", path)
+	}
+	template := ""
+	if name != "pr_metrics.csv" {
+		template = strings.Replace(name, "pr_metrics.csv", output.FileNamePlaceholder, 1)
+	}
+	return output.NewFileNamer(filepath.Dir(path), template, nil)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/agreement"
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Merges the outputs of several runs into combined aggregates and a report without calling the API
+// and returns the exit code
+func runReport(args []string) int {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON config file with the CSV layout, SLOs, category rules, and working agreements")
+	localeName := flags.String("locale", "", "Locale of the CSV files and the report, e.g. de-DE for comma decimals and semicolon delimiters")
+	outputDir := flags.String("output-dir", "output", "Output directory for the combined files")
+	formats := flags.String("format", "html,md", "Report formats to render (comma-separated: html, md, json)")
+	dependencyUpdates := flags.Bool("dependency-updates", false, "Write dependency_updates.csv and leave dependency update PRs out of the aggregated metrics")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	quiet := flags.Bool("quiet", false, "Only print errors to the console")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s report [flags] PATH...\n\nEach PATH is an output directory or a pr_metrics.csv file of an earlier run, for example one per\nrepository or one per time range. PRs collected by more than one run of the same repository are\ncounted once, taking the row of the later PATH.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   *quiet,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitValidation
+	}
+
+	formatList := strings.Split(*formats, ",")
+	for _, format := range formatList {
+		if format != output.ReportFormatHTML && format != output.ReportFormatMarkdown && format != output.ReportFormatJSON {
+			logger.Error("Report format must be 'html', 'md', or 'json'")
+			return exitValidation
+		}
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+	locale, err := applyLocale(cfg, *localeName)
+	if err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	namer, err := output.NewFileNamer(*outputDir, "", nil)
+	if err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	csvWriter := output.NewCSVWriter(logger, cfg.CSV)
+
+	// Merge the runs of each repository, so overlapping time ranges count a PR once
+	var repositories []string
+	prMetricsByRepository := make(map[string][]*api.PRMetrics)
+	for _, path := range flags.Args() {
+		inputNamer, err := outputNamer(path)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+		prMetrics, err := csvWriter.ReadPRMetricsCSV(inputNamer.Path("pr_metrics.csv"))
+		if err != nil {
+			logger.Error("Failed to read PR metrics: %v", err)
+			return exitError
+		}
+		repository := runRepository(inputNamer)
+		if repository == "" {
+			repository = path
+		}
+		logger.Info("Read %d pull requests of %s from %s", len(prMetrics), repository, inputNamer.Path("pr_metrics.csv"))
+
+		if _, exists := prMetricsByRepository[repository]; !exists {
+			repositories = append(repositories, repository)
+		}
+		prMetricsByRepository[repository] = output.MergePRMetrics(prMetricsByRepository[repository], prMetrics)
+	}
+
+	var prMetrics []*api.PRMetrics
+	for _, repository := range repositories {
+		prMetrics = append(prMetrics, prMetricsByRepository[repository]...)
+	}
+	sort.SliceStable(prMetrics, func(i, j int) bool {
+		return prMetrics[i].CreatedAt.Before(prMetrics[j].CreatedAt)
+	})
+
+	weeklyMetrics, err := writeAggregates(logger, cfg, csvWriter, namer, prMetrics, *dependencyUpdates)
+	if err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+
+	// The report covers the creation dates of the merged PRs
+	var start, end time.Time
+	for _, pr := range prMetrics {
+		if start.IsZero() || pr.CreatedAt.Before(start) {
+			start = pr.CreatedAt
+		}
+		if pr.CreatedAt.After(end) {
+			end = pr.CreatedAt
+		}
+	}
+
+	summaryReport := output.NewReport(strings.Join(repositories, ", "), start, end, prMetrics, weeklyMetrics)
+	summaryReport.Locale = locale
+	if len(cfg.WorkingAgreements) > 0 {
+		aggregatedPRs := prMetrics
+		if *dependencyUpdates {
+			_, aggregatedPRs = metrics.SplitDependencyUpdates(prMetrics)
+		}
+		summaryReport.WorkingAgreements = agreement.Score(cfg.WorkingAgreements, aggregatedPRs)
+	}
+	if err := output.NewReportWriter(logger).WriteToDirectory(namer, formatList, summaryReport); err != nil {
+		logger.Error("Failed to write report: %v", err)
+		return exitError
+	}
+
+	logger.Info("Successfully combined %d pull requests of %d repositories in directory: %s", len(prMetrics), len(repositories), namer.Dir())
+	return exitOK
+}
+
+// Returns the repository recorded in the run_report.json next to the outputs, or an empty string
+// if the run recorded none
+func runRepository(namer *output.FileNamer) string {
+	data, err := os.ReadFile(namer.Path("run_report.json"))
+	if err != nil {
+		return ""
+	}
+	var report api.RunReport
+	if err := json.Unmarshal(data, &report); err != nil || len(report.Repositories) == 0 {
+		return ""
+	}
+	return report.Repositories[0].FullName
+}
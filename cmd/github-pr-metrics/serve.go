@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+const serveShutdownTimeout = 10 * time.Second
+
+// Serves the outputs of a run over HTTP until interrupted and returns the exit code
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "Address to listen on; use :8080 to accept connections from other hosts")
+	configPath := flags.String("config", "", "JSON config file the outputs were written with, for the CSV layout")
+	localeName := flags.String("locale", "", "Locale the outputs were written with, for the CSV layout")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	quiet := flags.Bool("quiet", false, "Only print errors to the console")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s serve [flags] PATH\n\nPATH is an output directory or a pr_metrics.csv file, possibly renamed by --output-name-template.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   *quiet,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitValidation
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+	if _, err := applyLocale(cfg, *localeName); err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	namer, err := outputNamer(flags.Arg(0))
+	if err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newServeHandler(logger, output.NewCSVWriter(logger, cfg.CSV), namer),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Stop accepting connections on Ctrl-C or SIGTERM and let requests in flight finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Serving %s on http://%s", namer.Dir(), *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("Failed to serve: %v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Routes the JSON API over pr_metrics.csv and serves every other path as a file of the output
+// directory, so the HTML report and charts open in a browser. The CSV is read on each request,
+// so a scheduled run writing into the directory is picked up without a restart.
+func newServeHandler(logger *utils.Logger, csvWriter *output.CSVWriter, namer *output.FileNamer) http.Handler {
+	readPRMetrics := func(w http.ResponseWriter) ([]*api.PRMetrics, bool) {
+		prMetrics, err := csvWriter.ReadPRMetricsCSV(namer.Path("pr_metrics.csv"))
+		if err != nil {
+			logger.Error("Failed to read PR metrics: %v", err)
+			http.Error(w, "failed to read pr_metrics.csv", http.StatusInternalServerError)
+			return nil, false
+		}
+		return prMetrics, true
+	}
+	calculator := metrics.NewCalculator(nil, logger, metrics.Options{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/prs", func(w http.ResponseWriter, r *http.Request) {
+		if prMetrics, ok := readPRMetrics(w); ok {
+			writeJSON(w, logger, prMetrics)
+		}
+	})
+	mux.HandleFunc("GET /api/weekly", func(w http.ResponseWriter, r *http.Request) {
+		prMetrics, ok := readPRMetrics(w)
+		if !ok {
+			return
+		}
+		weekly, err := calculator.CalculateWeeklyAggregatedMetrics(prMetrics)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, logger, weekly)
+	})
+	mux.HandleFunc("GET /api/monthly", func(w http.ResponseWriter, r *http.Request) {
+		prMetrics, ok := readPRMetrics(w)
+		if !ok {
+			return
+		}
+		monthly, err := calculator.CalculateMonthlyAggregatedMetrics(prMetrics)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, logger, monthly)
+	})
+	mux.HandleFunc("GET /api/overall", func(w http.ResponseWriter, r *http.Request) {
+		if prMetrics, ok := readPRMetrics(w); ok {
+			writeJSON(w, logger, calculator.CalculateOverallAggregatedMetrics(prMetrics))
+		}
+	})
+	mux.Handle("GET /", http.FileServer(http.Dir(namer.Dir())))
+	return mux
+}
+
+// Writes a value as the JSON body of a response
+func writeJSON(w http.ResponseWriter, logger *utils.Logger, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.Debug("Failed to write response: %v", err)
+	}
+}
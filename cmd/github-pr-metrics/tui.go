@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/config"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/internal/tui"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Browses the outputs of a run interactively and returns the exit code
+func runTUI(args []string) int {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	configPath := flags.String("config", "", "JSON config file the outputs were written with, for the CSV layout")
+	localeName := flags.String("locale", "", "Locale the outputs were written with, for the CSV layout")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s tui [flags] PATH\n\nPATH is an output directory or a pr_metrics.csv file, possibly renamed by --output-name-template.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   !*verbose,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitValidation
+	}
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitValidation
+		}
+	}
+	if _, err := applyLocale(cfg, *localeName); err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+
+	namer, err := outputNamer(flags.Arg(0))
+	if err != nil {
+		logger.Error("%v", err)
+		return exitValidation
+	}
+	prMetrics, err := output.NewCSVWriter(logger, cfg.CSV).ReadPRMetricsCSV(namer.Path("pr_metrics.csv"))
+	if err != nil {
+		logger.Error("Failed to read PR metrics: %v", err)
+		return exitError
+	}
+
+	// The timeline is only available when the run exported events
+	var events []api.PREvent
+	for _, format := range []string{output.EventFormatJSONL, output.EventFormatCSV} {
+		eventsPath := namer.Path("events." + format)
+		if _, err := os.Stat(eventsPath); err != nil {
+			// A gzipped file left by --compress gzip is read in its place
+			if _, err := os.Stat(eventsPath + ".gz"); err != nil {
+				continue
+			}
+		}
+		events, err = output.NewEventWriter(logger).Read(eventsPath)
+		if err != nil {
+			logger.Error("Failed to read %s: %v", eventsPath, err)
+			return exitError
+		}
+		break
+	}
+
+	if err := tui.NewBrowser(prMetrics, events, os.Stdin, os.Stdout, isTerminal(os.Stdout)).Run(); err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Reports whether a file is an interactive terminal, where the screen can be redrawn
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
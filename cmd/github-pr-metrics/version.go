@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/internal/selfupdate"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Release version, set by release builds with -ldflags "-X main.version=v1.2.3"
+var version string
+
+// Returns the release version, the module version for go install builds, or (devel)
+func currentVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// Prints the version, build details, and output schema version, and returns the exit code
+func runVersion(args []string) int {
+	flags := flag.NewFlagSet("version", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s version\n", os.Args[0])
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	fmt.Printf("github-pr-metrics %s\n", currentVersion())
+	if info, ok := debug.ReadBuildInfo(); ok {
+		settings := make(map[string]string)
+		for _, setting := range info.Settings {
+			settings[setting.Key] = setting.Value
+		}
+		if revision := settings["vcs.revision"]; revision != "" {
+			if settings["vcs.modified"] == "true" {
+				revision += " (modified)"
+			}
+			fmt.Printf("commit: %s\n", revision)
+		}
+		if builtAt := settings["vcs.time"]; builtAt != "" {
+			fmt.Printf("commit time: %s\n", builtAt)
+		}
+	}
+	fmt.Printf("go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("output schema version: %d\n", output.SchemaVersion)
+	return exitOK
+}
+
+// Replaces the running binary with the latest release and returns the exit code
+func runSelfUpdate(args []string) int {
+	flags := flag.NewFlagSet("self-update", flag.ExitOnError)
+	check := flags.Bool("check", false, "Only report whether a newer release exists, exiting with 1 if it does")
+	force := flags.Bool("force", false, "Install the latest release even if it is not newer, or the running binary is a development build")
+	token := flags.String("token", "", "GitHub token for a higher API rate limit (optional)")
+	verbose := flags.Bool("verbose", false, "Enable verbose logging")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s self-update [flags]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	// The flag set uses ExitOnError, so errors never reach here
+	_ = flags.Parse(args)
+
+	logger, err := utils.NewLogger(utils.LoggerOptions{
+		Verbose: *verbose,
+		Quiet:   !*verbose,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitValidation
+	}
+
+	updater := selfupdate.NewUpdater(selfupdate.LatestReleaseURL, *token, logger)
+	release, err := updater.LatestRelease()
+	if err != nil {
+		logger.Error("Failed to check the latest release: %v", err)
+		return exitError
+	}
+
+	current := currentVersion()
+	development := current == "(devel)"
+	newer := !development && selfupdate.IsNewer(current, release.TagName)
+	if *check {
+		if newer {
+			fmt.Printf("%s is available (running %s): %s\n", release.TagName, current, release.HTMLURL)
+			return 1
+		}
+		fmt.Printf("%s is the latest release (running %s)\n", release.TagName, current)
+		return exitOK
+	}
+	if !newer && !*force {
+		if development {
+			logger.Error("Running a development build; pass --force to replace it with %s", release.TagName)
+			return exitValidation
+		}
+		fmt.Printf("Already up to date (%s)\n", current)
+		return exitOK
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		logger.Error("Failed to locate the running binary: %v", err)
+		return exitError
+	}
+	if err := updater.Install(release, executable); err != nil {
+		logger.Error("Failed to install %s: %v", release.TagName, err)
+		return exitError
+	}
+
+	fmt.Printf("Updated from %s to %s\n", current, release.TagName)
+	return exitOK
+}
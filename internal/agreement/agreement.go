@@ -0,0 +1,173 @@
+package agreement
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+)
+
+// Supported comparisons of a PR's value against the threshold
+const (
+	ComparisonGreater        = ">"
+	ComparisonGreaterOrEqual = ">="
+	ComparisonLess           = "<"
+	ComparisonLessOrEqual    = "<="
+)
+
+// Separates the columns of a metric that adds several up, as in "Additions + Deletions"
+const metricSeparator = "+"
+
+// Expectation the team holds every merged PR to, such as staying under 400 changed lines
+type Agreement struct {
+	Name       string  `json:"name"`       // Shown in the report; defaults to the condition, such as "Additions + Deletions < 400"
+	Metric     string  `json:"metric"`     // Numeric or true/false column of pr_metrics.csv, or columns joined with + to add them up
+	Comparison string  `json:"comparison"` // >, >=, <, or <=, which a PR's value must satisfy to keep the agreement
+	Threshold  float64 `json:"threshold"`  // Value the metric is compared against; true counts as 1 and false as 0
+}
+
+// Validates the metric and comparison
+func (a *Agreement) Validate() error {
+	for _, column := range a.columns() {
+		if !slices.Contains(output.PRColumns(), column) {
+			return fmt.Errorf("unknown working agreement metric: %q", column)
+		}
+		if _, ok := numericValue(&api.PRMetrics{}, column); !ok {
+			return fmt.Errorf("working agreement metric must be numeric or true/false: %q", column)
+		}
+	}
+	switch a.Comparison {
+	case ComparisonGreater, ComparisonGreaterOrEqual, ComparisonLess, ComparisonLessOrEqual:
+	default:
+		return fmt.Errorf("invalid working agreement comparison %q: must be '>', '>=', '<', or '<='", a.Comparison)
+	}
+	return nil
+}
+
+// Returns the name of the agreement, or its condition if it has none
+func (a *Agreement) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return fmt.Sprintf("%s %s %s", strings.Join(a.columns(), " + "), a.Comparison, strconv.FormatFloat(a.Threshold, 'f', -1, 64))
+}
+
+// Returns the columns added up into the metric
+func (a *Agreement) columns() []string {
+	var columns []string
+	for _, column := range strings.Split(a.Metric, metricSeparator) {
+		columns = append(columns, strings.TrimSpace(column))
+	}
+	return columns
+}
+
+// Reports whether the PR kept the agreement
+func (a *Agreement) keptBy(pr *api.PRMetrics) bool {
+	value := 0.0
+	for _, column := range a.columns() {
+		columnValue, _ := numericValue(pr, column)
+		value += columnValue
+	}
+
+	switch a.Comparison {
+	case ComparisonGreater:
+		return value > a.Threshold
+	case ComparisonGreaterOrEqual:
+		return value >= a.Threshold
+	case ComparisonLess:
+		return value < a.Threshold
+	default:
+		return value <= a.Threshold
+	}
+}
+
+// Returns a column of the PR as a number, counting true as 1 and false as 0
+func numericValue(pr *api.PRMetrics, column string) (float64, bool) {
+	value, _ := output.PRMetricField(pr, column)
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// Scores merged PRs against the agreements per ISO week and calendar month, grouping PRs by
+// merge date like the aggregated metrics
+func Score(agreements []Agreement, prMetrics []*api.PRMetrics) *api.AgreementScorecard {
+	scorecard := &api.AgreementScorecard{
+		Overall: make([]float64, len(agreements)),
+	}
+	for i := range agreements {
+		scorecard.Agreements = append(scorecard.Agreements, agreements[i].Label())
+	}
+
+	var merged []*api.PRMetrics
+	for _, pr := range prMetrics {
+		if !pr.MergedAt.IsZero() {
+			merged = append(merged, pr)
+		}
+	}
+	scorecard.PRCount = len(merged)
+	if overall := scorePeriod(agreements, merged); overall != nil {
+		scorecard.Overall = overall
+	}
+
+	for _, granularity := range []string{metrics.GranularityWeek, metrics.GranularityMonth} {
+		periods := make(map[string]*api.AgreementScore)
+		periodPRs := make(map[string][]*api.PRMetrics)
+		for _, pr := range merged {
+			period, startDate, _ := metrics.CalendarPeriod(pr.MergedAt, granularity)
+			if _, exists := periods[period]; !exists {
+				periods[period] = &api.AgreementScore{Period: period, StartDate: startDate}
+			}
+			periodPRs[period] = append(periodPRs[period], pr)
+		}
+
+		scores := make([]api.AgreementScore, 0, len(periods))
+		for period, score := range periods {
+			score.PRCount = len(periodPRs[period])
+			score.PassPercent = scorePeriod(agreements, periodPRs[period])
+			scores = append(scores, *score)
+		}
+		sort.Slice(scores, func(i, j int) bool {
+			return scores[i].Period < scores[j].Period
+		})
+
+		if granularity == metrics.GranularityWeek {
+			scorecard.Weeks = scores
+		} else {
+			scorecard.Months = scores
+		}
+	}
+	return scorecard
+}
+
+// Returns the share of the PRs keeping each agreement, or nil if there are none
+func scorePeriod(agreements []Agreement, prs []*api.PRMetrics) []float64 {
+	if len(prs) == 0 {
+		return nil
+	}
+	percents := make([]float64, len(agreements))
+	for i := range agreements {
+		kept := 0
+		for _, pr := range prs {
+			if agreements[i].keptBy(pr) {
+				kept++
+			}
+		}
+		percents[i] = float64(kept) / float64(len(prs)) * 100
+	}
+	return percents
+}
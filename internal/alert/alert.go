@@ -0,0 +1,132 @@
+package alert
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+)
+
+// Supported comparisons of a metric against the threshold
+const (
+	ComparisonGreater        = ">"
+	ComparisonGreaterOrEqual = ">="
+	ComparisonLess           = "<"
+	ComparisonLessOrEqual    = "<="
+)
+
+// Periods a rule is evaluated over
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Threshold on an aggregated metric, checked against the most recent periods after each run
+type Rule struct {
+	Name        string  `json:"name"`        // Identifies the rule in alerts; defaults to the metric
+	Metric      string  `json:"metric"`      // Column of the weekly and monthly CSVs, such as "Median Time to Approval (Hours)"
+	Comparison  string  `json:"comparison"`  // >, >=, <, or <=
+	Threshold   float64 `json:"threshold"`   // Value the metric is compared against
+	Period      string  `json:"period"`      // week (default) or month
+	Consecutive int     `json:"consecutive"` // Most recent periods that must all cross the threshold; defaults to 1
+}
+
+// Validates the metric, comparison, and period
+func (r *Rule) Validate() error {
+	if !slices.Contains(output.AggregatedColumns(), r.Metric) {
+		return fmt.Errorf("unknown alert metric: %q", r.Metric)
+	}
+	if _, ok := output.AggregatedMetricValue(&api.AggregatedMetrics{}, r.Metric); !ok {
+		return fmt.Errorf("alert metric must be numeric: %q", r.Metric)
+	}
+	switch r.Comparison {
+	case ComparisonGreater, ComparisonGreaterOrEqual, ComparisonLess, ComparisonLessOrEqual:
+	default:
+		return fmt.Errorf("invalid alert comparison %q: must be '>', '>=', '<', or '<='", r.Comparison)
+	}
+	switch r.Period {
+	case "", PeriodWeek, PeriodMonth:
+	default:
+		return fmt.Errorf("invalid alert period %q: must be 'week' or 'month'", r.Period)
+	}
+	if r.Consecutive < 0 {
+		return fmt.Errorf("alert consecutive periods must not be negative: %d", r.Consecutive)
+	}
+	return nil
+}
+
+// Reports whether a value crosses the threshold
+func (r *Rule) crosses(value float64) bool {
+	switch r.Comparison {
+	case ComparisonGreater:
+		return value > r.Threshold
+	case ComparisonGreaterOrEqual:
+		return value >= r.Threshold
+	case ComparisonLess:
+		return value < r.Threshold
+	default:
+		return value <= r.Threshold
+	}
+}
+
+// Reports whether next is the calendar period right after previous, since periods without
+// merged PRs have no entry and would otherwise close a gap in a run of consecutive periods
+func (r *Rule) follows(previous, next *api.AggregatedMetrics) bool {
+	expected := previous.StartDate.AddDate(0, 0, 7)
+	if r.Period == PeriodMonth {
+		expected = previous.StartDate.AddDate(0, 1, 0)
+	}
+	return sameDay(expected, next.StartDate)
+}
+
+// Reports whether two times fall on the same calendar date
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// Evaluates the rules against the weekly and monthly metrics, returning an alert
+// for each rule whose most recent adjacent periods all crossed its threshold
+func Evaluate(rules []Rule, weeklyMetrics, monthlyMetrics []*api.AggregatedMetrics) []api.Alert {
+	var alerts []api.Alert
+	for _, rule := range rules {
+		metrics := weeklyMetrics
+		if rule.Period == PeriodMonth {
+			metrics = monthlyMetrics
+		}
+		consecutive := max(rule.Consecutive, 1)
+		if len(metrics) < consecutive {
+			continue
+		}
+
+		name := rule.Name
+		if name == "" {
+			name = rule.Metric
+		}
+		alert := api.Alert{
+			Rule:       name,
+			Metric:     rule.Metric,
+			Comparison: rule.Comparison,
+			Threshold:  rule.Threshold,
+		}
+		recent := metrics[len(metrics)-consecutive:]
+		for i, period := range recent {
+			if i > 0 && !rule.follows(recent[i-1], period) {
+				break
+			}
+			value, _ := output.AggregatedMetricValue(period, rule.Metric)
+			if !rule.crosses(value) {
+				break
+			}
+			alert.Periods = append(alert.Periods, period.Period)
+			alert.Values = append(alert.Values, value)
+		}
+		if len(alert.Periods) == consecutive {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Implemented by providers that can verify their credentials before the fetch begins
+type AccessChecker interface {
+	CheckAccess(owner, repo string) error
+}
+
+// Checks that the token is valid, is authorized for the organization's single sign-on, and can
+// read the repository and its pull requests, so a misconfigured token fails before the long
+// fetch with an actionable error instead of a generic 404 midway
+func (c *Client) CheckAccess(owner, repo string) error {
+	c.logger.Debug("Checking access to %s/%s", owner, repo)
+	if c.enterprise {
+		if version := c.ServerVersion(); version != "" {
+			c.logger.Info("Connected to GitHub Enterprise Server %s", version)
+		}
+	}
+
+	_, resp, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	if err != nil {
+		return accessError(err, resp, owner, repo, "the repository")
+	}
+
+	_, resp, err = c.client.PullRequests.List(c.ctx, owner, repo, &github.PullRequestListOptions{
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return accessError(err, resp, owner, repo, "pull requests")
+	}
+	return nil
+}
+
+// Explains a response refusing access to part of a repository, returning other failures as they are
+func accessError(err error, resp *github.Response, owner, repo, resource string) error {
+	if resp == nil || IsRateLimitError(err) {
+		return err
+	}
+
+	// Classic tokens list their scopes; fine-grained and app tokens send no header
+	scopes, classic := resp.Header["X-Oauth-Scopes"]
+	var grantedScopes []string
+	if classic && len(scopes) > 0 {
+		for _, scope := range strings.Split(scopes[0], ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				grantedScopes = append(grantedScopes, scope)
+			}
+		}
+	}
+
+	var message string
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		message = "the token is invalid, expired, or revoked; create a new one and pass it with --token"
+	case http.StatusForbidden:
+		if sso := resp.Header.Get("X-GitHub-SSO"); sso != "" {
+			message = "the token is not authorized for the SAML single sign-on of " + owner
+			if _, url, found := strings.Cut(sso, "url="); found {
+				message += "; authorize it at " + url
+			} else {
+				message += "; authorize it in the token settings under Configure SSO"
+			}
+		} else {
+			message = "the token cannot read " + resource + " of " + owner + "/" + repo + "; " + permissionHint(classic, grantedScopes)
+		}
+	case http.StatusNotFound:
+		message = owner + "/" + repo + " does not exist or the token cannot see it; " + permissionHint(classic, grantedScopes)
+	default:
+		return err
+	}
+	return &utils.APIError{StatusCode: resp.StatusCode, Message: message}
+}
+
+// Suggests the permission the token is missing
+func permissionHint(classic bool, scopes []string) string {
+	switch {
+	case !classic:
+		return "grant the fine-grained token access to the repository with read permission for metadata, contents, and pull requests"
+	case len(scopes) == 0:
+		return "the classic token has no scopes, but needs the repo scope to read private repositories"
+	case !slices.Contains(scopes, "repo"):
+		return "classic tokens need the repo scope to read private repositories (granted: " + strings.Join(scopes, ", ") + ")"
+	default:
+		return "check the repository name and that the token's owner has access to it"
+	}
+}
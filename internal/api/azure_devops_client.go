@@ -0,0 +1,767 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+const (
+	// Azure DevOps REST API version sent with every request
+	azureDevOpsAPIVersion = "7.1"
+	// Policy type ID of the "Minimum number of reviewers" branch policy
+	azureDevOpsMinimumReviewersPolicy = "fa4e907d-c16b-4a4c-9dfa-4906e5d171dd"
+)
+
+// Reads Azure DevOps Repos pull requests into go-github types, which azureDevOpsConverter
+// converts to the model.
+// The owner is "organization/project" and the repo is the Git repository name.
+type AzureDevOpsClient struct {
+	rest          *restClient
+	logger        *utils.Logger
+	repositoryIDs map[string]string
+}
+
+// Configures Azure DevOps API client with personal access token authentication
+func NewAzureDevOpsClient(apiURL, token string, logger *utils.Logger) (*AzureDevOpsClient, error) {
+	if _, err := url.Parse(apiURL); err != nil {
+		return nil, err
+	}
+	logger.Debug("Using Azure DevOps API URL: %s", apiURL)
+
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte(":"+token))
+
+	return &AzureDevOpsClient{
+		rest:          newRESTClient(apiURL, map[string]string{"Authorization": authorization}, logger),
+		logger:        logger,
+		repositoryIDs: make(map[string]string),
+	}, nil
+}
+
+type azureDevOpsIdentity struct {
+	UniqueName  string `json:"uniqueName"`
+	DisplayName string `json:"displayName"`
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestID         int                  `json:"pullRequestId"`
+	Title                 string               `json:"title"`
+	Status                string               `json:"status"`
+	CreatedBy             azureDevOpsIdentity  `json:"createdBy"`
+	ClosedBy              *azureDevOpsIdentity `json:"closedBy"`
+	CreationDate          time.Time            `json:"creationDate"`
+	ClosedDate            time.Time            `json:"closedDate"`
+	SourceRefName         string               `json:"sourceRefName"`
+	TargetRefName         string               `json:"targetRefName"`
+	LastMergeSourceCommit *struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Reviewers []struct {
+		azureDevOpsIdentity
+		IsContainer bool `json:"isContainer"` // A team or group rather than a person
+	} `json:"reviewers"`
+}
+
+type azureDevOpsGitUserDate struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+type azureDevOpsCommit struct {
+	CommitID  string                 `json:"commitId"`
+	Comment   string                 `json:"comment"`
+	Author    azureDevOpsGitUserDate `json:"author"`
+	Committer azureDevOpsGitUserDate `json:"committer"`
+}
+
+type azureDevOpsThread struct {
+	ID            int64     `json:"id"`
+	PublishedDate time.Time `json:"publishedDate"`
+	IsDeleted     bool      `json:"isDeleted"`
+	Status        string    `json:"status"`
+	ThreadContext *struct {
+		FilePath string `json:"filePath"`
+	} `json:"threadContext"`
+	Comments []struct {
+		ID            int64               `json:"id"`
+		Author        azureDevOpsIdentity `json:"author"`
+		Content       string              `json:"content"`
+		PublishedDate time.Time           `json:"publishedDate"`
+		CommentType   string              `json:"commentType"`
+		IsDeleted     bool                `json:"isDeleted"`
+	} `json:"comments"`
+	Properties map[string]struct {
+		Value any `json:"$value"`
+	} `json:"properties"`
+}
+
+type azureDevOpsIteration struct {
+	ID int `json:"id"`
+}
+
+type azureDevOpsChange struct {
+	ChangeType string `json:"changeType"`
+	Item       struct {
+		Path          string `json:"path"`
+		IsFolder      bool   `json:"isFolder"`
+		GitObjectType string `json:"gitObjectType"`
+	} `json:"item"`
+	OriginalPath string `json:"originalPath"`
+}
+
+type azureDevOpsPolicyConfiguration struct {
+	IsEnabled  bool `json:"isEnabled"`
+	IsBlocking bool `json:"isBlocking"`
+	Type       struct {
+		ID string `json:"id"`
+	} `json:"type"`
+	Settings struct {
+		MinimumApproverCount int `json:"minimumApproverCount"`
+	} `json:"settings"`
+}
+
+type azureDevOpsCommitStatus struct {
+	State        string    `json:"state"`
+	CreationDate time.Time `json:"creationDate"`
+	Context      struct {
+		Name  string `json:"name"`
+		Genre string `json:"genre"`
+	} `json:"context"`
+}
+
+// Builds the "/organization/project" path prefix, escaping project names with spaces
+func azureDevOpsProjectPath(owner string) string {
+	organization, project, _ := strings.Cut(owner, "/")
+	return "/" + url.PathEscape(organization) + "/" + url.PathEscape(project)
+}
+
+// Builds the Git repository path segment used by Azure DevOps
+func azureDevOpsRepoPath(owner, repo string) string {
+	return azureDevOpsProjectPath(owner) + "/_apis/git/repositories/" + url.PathEscape(repo)
+}
+
+// Performs a GET request with the API version query parameter added
+func (c *AzureDevOpsClient) get(path string, query url.Values, out any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsAPIVersion)
+	_, err := c.rest.getJSON(path, query, out)
+	return err
+}
+
+// Fetches all PRs created within date range using $top/$skip pagination
+func (c *AzureDevOpsClient) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	c.logger.Debug("Fetching pull requests for %s/%s from %s to %s", owner, repo, query.StartDate.Format("2006-01-02"), query.EndDate.Format("2006-01-02"))
+
+	// Azure DevOps pull requests carry no update timestamp to filter on
+	if !query.UpdatedSince.IsZero() {
+		c.logger.Warn("Updated-since filtering is not supported by the %s provider and will be ignored", ProviderAzureDevOps)
+	}
+
+	const pageSize = 100
+	params := url.Values{}
+	params.Set("searchCriteria.status", "all")
+	params.Set("searchCriteria.queryTimeRangeType", "created")
+	if query.DateField == DateFieldMerged || query.DateField == DateFieldClosed {
+		params.Set("searchCriteria.queryTimeRangeType", "closed")
+	}
+	params.Set("searchCriteria.minTime", query.StartDate.Format(time.RFC3339))
+	params.Set("searchCriteria.maxTime", query.EndDate.Format(time.RFC3339))
+	params.Set("$top", strconv.Itoa(pageSize))
+
+	var allPRs []*github.PullRequest
+	for skip := 0; ; skip += pageSize {
+		params.Set("$skip", strconv.Itoa(skip))
+
+		var page struct {
+			Value []azureDevOpsPullRequest `json:"value"`
+		}
+		if err := c.get(azureDevOpsRepoPath(owner, repo)+"/pullrequests", params, &page); err != nil {
+			return nil, err
+		}
+
+		for _, pr := range page.Value {
+			converted := pr.toPullRequest()
+			if query.Matches(converted) {
+				allPRs = append(allPRs, converted)
+			}
+		}
+		c.logger.Debug("Fetched page of pull requests (%d total so far)", len(allPRs))
+
+		if len(page.Value) < pageSize {
+			break
+		}
+	}
+
+	c.logger.Debug("Fetched %d pull requests in total", len(allPRs))
+	return allPRs, nil
+}
+
+// Fetches a PR with its changed files count; line counts are not exposed by Azure DevOps
+func (c *AzureDevOpsClient) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	c.logger.Debug("Fetching details for PR #%d", number)
+
+	var raw azureDevOpsPullRequest
+	if err := c.get(fmt.Sprintf("%s/pullrequests/%d", azureDevOpsRepoPath(owner, repo), number), nil, &raw); err != nil {
+		return nil, err
+	}
+	pr := raw.toPullRequest()
+
+	files, err := c.GetPRFiles(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	pr.ChangedFiles = github.Ptr(len(files))
+
+	return pr, nil
+}
+
+// Fetches all commits of a PR, oldest first to match GitHub ordering
+func (c *AzureDevOpsClient) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	c.logger.Debug("Fetching commits for PR #%d", number)
+
+	query := url.Values{}
+	query.Set("$top", "100")
+	path := fmt.Sprintf("%s/pullrequests/%d/commits", azureDevOpsRepoPath(owner, repo), number)
+
+	var allCommits []*github.RepositoryCommit
+	for {
+		var page struct {
+			Value []azureDevOpsCommit `json:"value"`
+		}
+		query.Set("api-version", azureDevOpsAPIVersion)
+		resp, err := c.rest.getJSON(path, query, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, commit := range page.Value {
+			allCommits = append(allCommits, &github.RepositoryCommit{
+				SHA: github.Ptr(commit.CommitID),
+				Commit: &github.Commit{
+					Message: github.Ptr(commit.Comment),
+					Author: &github.CommitAuthor{
+						Name:  github.Ptr(commit.Author.Name),
+						Email: github.Ptr(commit.Author.Email),
+						Date:  &github.Timestamp{Time: commit.Author.Date},
+					},
+					Committer: &github.CommitAuthor{
+						Name:  github.Ptr(commit.Committer.Name),
+						Email: github.Ptr(commit.Committer.Email),
+						Date:  &github.Timestamp{Time: commit.Committer.Date},
+					},
+				},
+			})
+		}
+
+		token := resp.Header.Get("x-ms-continuationtoken")
+		if token == "" {
+			break
+		}
+		query.Set("continuationToken", token)
+	}
+
+	// Azure DevOps lists PR commits newest first
+	slices.Reverse(allCommits)
+
+	c.logger.Debug("Fetched %d commits for PR #%d", len(allCommits), number)
+	return allCommits, nil
+}
+
+// Fetches all comment threads of a PR
+func (c *AzureDevOpsClient) getThreads(owner, repo string, number int) ([]azureDevOpsThread, error) {
+	var page struct {
+		Value []azureDevOpsThread `json:"value"`
+	}
+	if err := c.get(fmt.Sprintf("%s/pullrequests/%d/threads", azureDevOpsRepoPath(owner, repo), number), nil, &page); err != nil {
+		return nil, err
+	}
+
+	return page.Value, nil
+}
+
+// Fetches comments of file-anchored threads, which correspond to GitHub review comments
+func (c *AzureDevOpsClient) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	c.logger.Debug("Fetching comments for PR #%d", number)
+
+	threads, err := c.getThreads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allComments []*github.PullRequestComment
+	for _, thread := range threads {
+		if thread.IsDeleted || thread.ThreadContext == nil || thread.ThreadContext.FilePath == "" {
+			continue
+		}
+		for _, comment := range thread.Comments {
+			if comment.IsDeleted || comment.CommentType == "system" {
+				continue
+			}
+			allComments = append(allComments, &github.PullRequestComment{
+				ID:        github.Ptr(comment.ID),
+				Body:      github.Ptr(comment.Content),
+				Path:      github.Ptr(strings.TrimPrefix(thread.ThreadContext.FilePath, "/")),
+				User:      &github.User{Login: github.Ptr(comment.Author.UniqueName)},
+				CreatedAt: &github.Timestamp{Time: comment.PublishedDate},
+			})
+		}
+	}
+
+	slices.SortStableFunc(allComments, func(a, b *github.PullRequestComment) int {
+		return a.GetCreatedAt().Compare(b.GetCreatedAt().Time)
+	})
+
+	c.logger.Debug("Fetched %d comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches the comments of threads that are not attached to a file, skipping system comments
+func (c *AzureDevOpsClient) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	c.logger.Debug("Fetching conversation comments for PR #%d", number)
+
+	threads, err := c.getThreads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allComments []*github.IssueComment
+	for _, thread := range threads {
+		if thread.IsDeleted || (thread.ThreadContext != nil && thread.ThreadContext.FilePath != "") {
+			continue
+		}
+		for _, comment := range thread.Comments {
+			if comment.IsDeleted || comment.CommentType == "system" {
+				continue
+			}
+			allComments = append(allComments, &github.IssueComment{
+				ID:        github.Ptr(comment.ID),
+				Body:      github.Ptr(comment.Content),
+				User:      &github.User{Login: github.Ptr(comment.Author.UniqueName)},
+				CreatedAt: &github.Timestamp{Time: comment.PublishedDate},
+			})
+		}
+	}
+
+	slices.SortStableFunc(allComments, func(a, b *github.IssueComment) int {
+		return a.GetCreatedAt().Compare(b.GetCreatedAt().Time)
+	})
+
+	c.logger.Debug("Fetched %d conversation comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches the comment threads people started, which are resolved once their status leaves active or pending
+func (c *AzureDevOpsClient) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	c.logger.Debug("Fetching review threads for PR #%d", number)
+
+	threads, err := c.getThreads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allThreads []*ReviewThread
+	for _, thread := range threads {
+		// System threads, such as vote updates, have no known status
+		if thread.IsDeleted || thread.Status == "" || thread.Status == "unknown" || len(thread.Comments) == 0 || thread.Comments[0].CommentType == "system" {
+			continue
+		}
+		reviewThread := &ReviewThread{
+			ID:        strconv.FormatInt(thread.ID, 10),
+			Author:    thread.Comments[0].Author.UniqueName,
+			CreatedAt: thread.PublishedDate,
+			Resolved:  thread.Status != "active" && thread.Status != "pending",
+		}
+		if thread.ThreadContext != nil {
+			reviewThread.Path = strings.TrimPrefix(thread.ThreadContext.FilePath, "/")
+		}
+		allThreads = append(allThreads, reviewThread)
+	}
+
+	c.logger.Debug("Fetched %d review threads for PR #%d", len(allThreads), number)
+	return allThreads, nil
+}
+
+// Derives events from the status update threads written when a PR is abandoned or reactivated,
+// typed by the status so the Azure DevOps converter names them
+func (c *AzureDevOpsClient) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	c.logger.Debug("Fetching timeline events for PR #%d", number)
+
+	threads, err := c.getThreads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allEvents []*github.IssueEvent
+	for _, thread := range threads {
+		threadType, ok := thread.Properties["CodeReviewThreadType"]
+		if !ok || threadType.Value != "StatusUpdate" || len(thread.Comments) == 0 {
+			continue
+		}
+
+		allEvents = append(allEvents, &github.IssueEvent{
+			ID:        github.Ptr(thread.ID),
+			Actor:     &github.User{Login: github.Ptr(thread.Comments[0].Author.UniqueName)},
+			Event:     github.Ptr(fmt.Sprint(thread.Properties["CodeReviewStatus"].Value)),
+			CreatedAt: &github.Timestamp{Time: thread.PublishedDate},
+		})
+	}
+
+	slices.SortStableFunc(allEvents, func(a, b *github.IssueEvent) int {
+		return a.GetCreatedAt().Compare(b.GetCreatedAt().Time)
+	})
+
+	c.logger.Debug("Fetched %d timeline events for PR #%d", len(allEvents), number)
+	return allEvents, nil
+}
+
+// Derives reviews from vote update threads, with the vote as the state so the Azure DevOps
+// converter maps it
+func (c *AzureDevOpsClient) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	c.logger.Debug("Fetching votes for PR #%d", number)
+
+	threads, err := c.getThreads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allReviews []*github.PullRequestReview
+	for _, thread := range threads {
+		threadType, ok := thread.Properties["CodeReviewThreadType"]
+		if !ok || threadType.Value != "VoteUpdate" || len(thread.Comments) == 0 {
+			continue
+		}
+
+		allReviews = append(allReviews, &github.PullRequestReview{
+			ID:          github.Ptr(thread.ID),
+			User:        &github.User{Login: github.Ptr(thread.Comments[0].Author.UniqueName)},
+			State:       github.Ptr(fmt.Sprint(thread.Properties["CodeReviewVoteResult"].Value)),
+			SubmittedAt: &github.Timestamp{Time: thread.PublishedDate},
+		})
+	}
+
+	slices.SortStableFunc(allReviews, func(a, b *github.PullRequestReview) int {
+		return a.GetSubmittedAt().Compare(b.GetSubmittedAt().Time)
+	})
+
+	c.logger.Debug("Fetched %d votes for PR #%d", len(allReviews), number)
+	return allReviews, nil
+}
+
+// Fetches the files changed across all iterations of a PR
+func (c *AzureDevOpsClient) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	c.logger.Debug("Fetching files for PR #%d", number)
+
+	var iterations struct {
+		Value []azureDevOpsIteration `json:"value"`
+	}
+	if err := c.get(fmt.Sprintf("%s/pullrequests/%d/iterations", azureDevOpsRepoPath(owner, repo), number), nil, &iterations); err != nil {
+		return nil, err
+	}
+	if len(iterations.Value) == 0 {
+		return nil, nil
+	}
+	lastIteration := iterations.Value[len(iterations.Value)-1].ID
+
+	query := url.Values{}
+	query.Set("$compareTo", "0")
+	var changes struct {
+		ChangeEntries []azureDevOpsChange `json:"changeEntries"`
+	}
+	path := fmt.Sprintf("%s/pullrequests/%d/iterations/%d/changes", azureDevOpsRepoPath(owner, repo), number, lastIteration)
+	if err := c.get(path, query, &changes); err != nil {
+		return nil, err
+	}
+
+	var allFiles []*github.CommitFile
+	for _, change := range changes.ChangeEntries {
+		if change.Item.IsFolder || change.Item.GitObjectType == "tree" {
+			continue
+		}
+
+		status := "modified"
+		switch {
+		case strings.Contains(change.ChangeType, "add"):
+			status = "added"
+		case strings.Contains(change.ChangeType, "delete"):
+			status = "removed"
+		case strings.Contains(change.ChangeType, "rename"):
+			status = "renamed"
+		}
+
+		allFiles = append(allFiles, &github.CommitFile{
+			Filename:         github.Ptr(strings.TrimPrefix(change.Item.Path, "/")),
+			PreviousFilename: github.Ptr(strings.TrimPrefix(change.OriginalPath, "/")),
+			Status:           github.Ptr(status),
+		})
+	}
+
+	c.logger.Debug("Fetched %d files for PR #%d", len(allFiles), number)
+	return allFiles, nil
+}
+
+// Resolves and caches the repository GUID required by the policy API
+func (c *AzureDevOpsClient) getRepositoryID(owner, repo string) (string, error) {
+	key := owner + "/" + repo
+	if id, exists := c.repositoryIDs[key]; exists {
+		return id, nil
+	}
+
+	var repository struct {
+		ID string `json:"id"`
+	}
+	if err := c.get(azureDevOpsRepoPath(owner, repo), nil, &repository); err != nil {
+		return "", err
+	}
+
+	c.repositoryIDs[key] = repository.ID
+	return repository.ID, nil
+}
+
+// Returns no code owners, since Azure Repos assigns required reviewers through branch policies instead
+func (c *AzureDevOpsClient) GetCodeOwners(owner, repo, ref string) (string, error) {
+	return "", nil
+}
+
+// Fetches the root .gitattributes file of a branch, returning an empty string when the repository has none
+func (c *AzureDevOpsClient) GetGitAttributes(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching .gitattributes for %s", ref)
+
+	query := url.Values{}
+	query.Set("path", "/.gitattributes")
+	query.Set("versionDescriptor.version", ref)
+	query.Set("versionDescriptor.versionType", "branch")
+	query.Set("includeContent", "true")
+
+	var item struct {
+		Content string `json:"content"`
+	}
+	err := c.get(fmt.Sprintf("%s/items", azureDevOpsRepoPath(owner, repo)), query, &item)
+	if apiErr, ok := err.(*utils.APIError); ok && apiErr.StatusCode == 404 {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return item.Content, nil
+}
+
+// Fetches the repository, taking the visibility of its project. Azure Repos has no languages or topics.
+func (c *AzureDevOpsClient) GetRepository(owner, repo string) (*github.Repository, error) {
+	c.logger.Debug("Fetching repository %s/%s", owner, repo)
+
+	var repository struct {
+		Name          string `json:"name"`
+		DefaultBranch string `json:"defaultBranch"`
+		Project       struct {
+			Name       string `json:"name"`
+			Visibility string `json:"visibility"`
+		} `json:"project"`
+	}
+	if err := c.get(azureDevOpsRepoPath(owner, repo), nil, &repository); err != nil {
+		return nil, err
+	}
+
+	return &github.Repository{
+		FullName:      github.Ptr(repository.Project.Name + "/" + repository.Name),
+		Visibility:    github.Ptr(repository.Project.Visibility),
+		DefaultBranch: github.Ptr(strings.TrimPrefix(repository.DefaultBranch, "refs/heads/")),
+	}, nil
+}
+
+// Maps the blocking "Minimum number of reviewers" policy onto GitHub branch protection
+func (c *AzureDevOpsClient) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	c.logger.Debug("Fetching branch policies for %s", branch)
+
+	repositoryID, err := c.getRepositoryID(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("repositoryId", repositoryID)
+	query.Set("refName", "refs/heads/"+branch)
+	var policies struct {
+		Value []azureDevOpsPolicyConfiguration `json:"value"`
+	}
+	if err := c.get(azureDevOpsProjectPath(owner)+"/_apis/git/policy/configurations", query, &policies); err != nil {
+		return nil, err
+	}
+
+	var protection *github.Protection
+	for _, policy := range policies.Value {
+		if !policy.IsEnabled || !policy.IsBlocking || policy.Type.ID != azureDevOpsMinimumReviewersPolicy {
+			continue
+		}
+		if protection == nil {
+			protection = &github.Protection{RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{}}
+		}
+		reviews := protection.RequiredPullRequestReviews
+		reviews.RequiredApprovingReviewCount = max(reviews.RequiredApprovingReviewCount, policy.Settings.MinimumApproverCount)
+	}
+
+	return protection, nil
+}
+
+// Fetches statuses posted to a commit by pipelines and external services
+func (c *AzureDevOpsClient) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	c.logger.Debug("Fetching commit statuses for %s", ref)
+
+	var statuses struct {
+		Value []azureDevOpsCommitStatus `json:"value"`
+	}
+	if err := c.get(fmt.Sprintf("%s/commits/%s/statuses", azureDevOpsRepoPath(owner, repo), url.PathEscape(ref)), nil, &statuses); err != nil {
+		return nil, err
+	}
+
+	var allStatuses []*github.RepoStatus
+	for _, status := range statuses.Value {
+		state := "pending"
+		switch status.State {
+		case "succeeded":
+			state = "success"
+		case "failed", "error":
+			state = "failure"
+		}
+
+		context := status.Context.Name
+		if status.Context.Genre != "" {
+			context = status.Context.Genre + "/" + context
+		}
+
+		allStatuses = append(allStatuses, &github.RepoStatus{
+			Context:   github.Ptr(context),
+			State:     github.Ptr(state),
+			CreatedAt: &github.Timestamp{Time: status.CreationDate},
+		})
+	}
+
+	return allStatuses, nil
+}
+
+// Azure DevOps has no check runs; pipeline results are reported as commit statuses instead
+func (c *AzureDevOpsClient) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	return nil, nil
+}
+
+// Converts an Azure DevOps pull request into a GitHub pull request
+func (pr azureDevOpsPullRequest) toPullRequest() *github.PullRequest {
+	state := "closed"
+	if pr.Status == "active" {
+		state = "open"
+	}
+
+	converted := &github.PullRequest{
+		Number:    github.Ptr(pr.PullRequestID),
+		Title:     github.Ptr(pr.Title),
+		State:     github.Ptr(state),
+		User:      &github.User{Login: github.Ptr(pr.CreatedBy.UniqueName)},
+		CreatedAt: &github.Timestamp{Time: pr.CreationDate},
+		Base:      &github.PullRequestBranch{Ref: github.Ptr(strings.TrimPrefix(pr.TargetRefName, "refs/heads/"))},
+		Head:      &github.PullRequestBranch{Ref: github.Ptr(strings.TrimPrefix(pr.SourceRefName, "refs/heads/"))},
+	}
+
+	if pr.LastMergeSourceCommit != nil {
+		converted.Head.SHA = github.Ptr(pr.LastMergeSourceCommit.CommitID)
+	}
+	if !pr.ClosedDate.IsZero() {
+		converted.ClosedAt = &github.Timestamp{Time: pr.ClosedDate}
+	}
+	if pr.Status == "completed" {
+		converted.MergedAt = &github.Timestamp{Time: pr.ClosedDate}
+		if pr.ClosedBy != nil {
+			converted.MergedBy = &github.User{Login: github.Ptr(pr.ClosedBy.UniqueName)}
+		}
+	}
+	for _, label := range pr.Labels {
+		converted.Labels = append(converted.Labels, &github.Label{Name: github.Ptr(label.Name)})
+	}
+	// Reviewers stay listed after they vote, and people who vote unasked are added to the list
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.IsContainer {
+			converted.RequestedTeams = append(converted.RequestedTeams, &github.Team{Name: github.Ptr(reviewer.DisplayName)})
+		} else {
+			converted.RequestedReviewers = append(converted.RequestedReviewers, &github.User{Login: github.Ptr(reviewer.UniqueName)})
+		}
+	}
+
+	return converted
+}
+
+// Converts Azure DevOps responses, whose reviews are votes, whose close events are status
+// updates, and whose teams have no slugs, to the provider-neutral model
+type azureDevOpsConverter struct {
+	GitHubConverter
+}
+
+// Converts a PR, naming teams asked to review by their display names
+func (azureDevOpsConverter) PullRequest(pr *github.PullRequest) *model.PullRequest {
+	result := newPullRequest(pr)
+	for _, team := range pr.RequestedTeams {
+		if name := team.GetName(); name != "" {
+			result.RequestedTeams = append(result.RequestedTeams, name)
+		}
+	}
+	return result
+}
+
+// Converts a vote into a review: 10 and 5 approve, -5 and -10 request changes, and a reset vote
+// is no verdict
+func (azureDevOpsConverter) ReviewEvent(review *github.PullRequestReview) *model.ReviewEvent {
+	vote, _ := strconv.Atoi(review.GetState())
+	switch {
+	case vote > 0:
+		return newReviewEvent(review, model.ReviewStateApproved)
+	case vote < 0:
+		return newReviewEvent(review, model.ReviewStateChangesRequested)
+	default:
+		return recordedReviewEvent(review)
+	}
+}
+
+// Converts a status update: abandoning closes the PR and reactivating reopens it
+func (azureDevOpsConverter) TimelineEvent(event *github.IssueEvent) *model.TimelineEvent {
+	var eventType string
+	switch event.GetEvent() {
+	case "Abandoned":
+		eventType = model.TimelineEventClosed
+	case "Active":
+		eventType = model.TimelineEventReopened
+	default:
+		return recordedTimelineEvent(event)
+	}
+	return newTimelineEvent(event, eventType)
+}
+
+// Returns the API requests made so far
+func (c *AzureDevOpsClient) Usage() APIUsage {
+	return c.rest.tracker.usage()
+}
+
+// Limits the API requests made from now on
+func (c *AzureDevOpsClient) SetRequestBudget(budget RequestBudget) {
+	c.rest.tracker.setBudget(budget)
+}
+
+// Caches responses on disk, revalidating them with ETags once they are stale
+func (c *AzureDevOpsClient) EnableResponseCache(options CacheOptions) error {
+	return c.rest.tracker.enableCache(options)
+}
+
+// Routes requests through a proxy and applies custom TLS settings
+func (c *AzureDevOpsClient) ConfigureTransport(options TransportOptions) error {
+	return c.rest.tracker.configureTransport(options)
+}
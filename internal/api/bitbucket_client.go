@@ -0,0 +1,668 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Reads Bitbucket Cloud pull requests into go-github types, which bitbucketConverter converts to
+// the model
+type BitbucketClient struct {
+	rest          *restClient
+	logger        *utils.Logger
+	activityKey   string              // PR whose activity log is cached
+	activityCache []bitbucketActivity // Activity log of the PR last fetched
+}
+
+// Configures Bitbucket Cloud API client; tokens in 'username:app_password' form use basic auth
+func NewBitbucketClient(apiURL, token string, logger *utils.Logger) (*BitbucketClient, error) {
+	if _, err := url.Parse(apiURL); err != nil {
+		return nil, err
+	}
+	logger.Debug("Using Bitbucket API URL: %s", apiURL)
+
+	authorization := "Bearer " + token
+	if strings.Contains(token, ":") {
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(token))
+	}
+
+	return &BitbucketClient{
+		rest:   newRESTClient(apiURL, map[string]string{"Authorization": authorization}, logger),
+		logger: logger,
+	}, nil
+}
+
+type bitbucketUser struct {
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+type bitbucketPullRequest struct {
+	ID        int            `json:"id"`
+	Title     string         `json:"title"`
+	State     string         `json:"state"`
+	Author    bitbucketUser  `json:"author"`
+	ClosedBy  *bitbucketUser `json:"closed_by"`
+	CreatedOn time.Time      `json:"created_on"`
+	UpdatedOn time.Time      `json:"updated_on"`
+	Source    struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+		Commit struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
+	} `json:"source"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"destination"`
+}
+
+type bitbucketCommit struct {
+	Hash    string    `json:"hash"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+	Author  struct {
+		Raw  string         `json:"raw"`
+		User *bitbucketUser `json:"user"`
+	} `json:"author"`
+	Parents []struct {
+		Hash string `json:"hash"`
+	} `json:"parents"`
+}
+
+type bitbucketComment struct {
+	ID        int64         `json:"id"`
+	User      bitbucketUser `json:"user"`
+	CreatedOn time.Time     `json:"created_on"`
+	Deleted   bool          `json:"deleted"`
+	Content   struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+	Inline *struct {
+		Path string `json:"path"`
+	} `json:"inline"`
+	Parent *struct {
+		ID int64 `json:"id"`
+	} `json:"parent"`
+	Resolution *struct {
+		CreatedOn time.Time `json:"created_on"`
+	} `json:"resolution"`
+}
+
+// Activity log entries reviews are derived from
+const (
+	bitbucketApproval         = "approval"
+	bitbucketChangesRequested = "changes_requested"
+)
+
+type bitbucketActivity struct {
+	Approval *struct {
+		Date time.Time     `json:"date"`
+		User bitbucketUser `json:"user"`
+	} `json:"approval"`
+	ChangesRequested *struct {
+		Date time.Time     `json:"date"`
+		User bitbucketUser `json:"user"`
+	} `json:"changes_requested"`
+	Update *struct {
+		State  string        `json:"state"`
+		Date   time.Time     `json:"date"`
+		Author bitbucketUser `json:"author"`
+	} `json:"update"`
+}
+
+type bitbucketDiffStat struct {
+	Status       string `json:"status"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	Old          *struct {
+		Path string `json:"path"`
+	} `json:"old"`
+	New *struct {
+		Path string `json:"path"`
+	} `json:"new"`
+}
+
+type bitbucketBranchRestriction struct {
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern"`
+	Value   int    `json:"value"`
+}
+
+type bitbucketCommitStatus struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
+// Builds the repository path segment used by Bitbucket
+func bitbucketRepoPath(owner, repo string) string {
+	return "/repositories/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// Follows Bitbucket's "next" links, passing each page's values to handle
+func paginateBitbucket[T any](c *BitbucketClient, path string, query url.Values, handle func(values []T)) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("pagelen", "50")
+	requestURL := c.rest.baseURL + path + "?" + query.Encode()
+
+	for requestURL != "" {
+		var page struct {
+			Values []T    `json:"values"`
+			Next   string `json:"next"`
+		}
+		if _, err := c.rest.getJSONURL(requestURL, &page); err != nil {
+			return err
+		}
+		handle(page.Values)
+		requestURL = page.Next
+	}
+
+	return nil
+}
+
+// Fetches all pull requests matching the query using paginated API calls
+func (c *BitbucketClient) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	c.logger.Debug("Fetching pull requests for %s/%s from %s to %s", owner, repo, query.StartDate.Format("2006-01-02"), query.EndDate.Format("2006-01-02"))
+
+	// Bitbucket can only filter by creation or update time, so merged/closed ranges are
+	// narrowed to pull requests updated since the start and then filtered locally
+	filter := fmt.Sprintf("(created_on >= %s AND created_on <= %s)", query.StartDate.Format(time.RFC3339), query.EndDate.Format(time.RFC3339))
+	if query.DateField == DateFieldMerged || query.DateField == DateFieldClosed {
+		filter = fmt.Sprintf("(updated_on >= %s)", query.StartDate.Format(time.RFC3339))
+	}
+	if !query.UpdatedSince.IsZero() {
+		filter += fmt.Sprintf(" OR updated_on >= %s", query.UpdatedSince.Format(time.RFC3339))
+	}
+
+	params := url.Values{}
+	params["state"] = []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"}
+	params.Set("q", filter)
+
+	var candidates []bitbucketPullRequest
+	err := paginateBitbucket(c, bitbucketRepoPath(owner, repo)+"/pullrequests", params, func(values []bitbucketPullRequest) {
+		candidates = append(candidates, values...)
+		c.logger.Debug("Fetched page of pull requests (%d total so far)", len(candidates))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allPRs []*github.PullRequest
+	for _, raw := range candidates {
+		pr := raw.toPullRequest()
+		// The list endpoint has no close or merge times, so when the range applies to them they
+		// are read from the activity log of each PR that is no longer open
+		if (query.DateField == DateFieldMerged || query.DateField == DateFieldClosed) && raw.State != "OPEN" {
+			activities, err := c.getActivity(owner, repo, raw.ID)
+			if err != nil {
+				return nil, err
+			}
+			applyStateUpdate(pr, raw.State, activities)
+		}
+		if query.Matches(pr) {
+			allPRs = append(allPRs, pr)
+		}
+	}
+
+	c.logger.Debug("Fetched %d pull requests in total", len(allPRs))
+	return allPRs, nil
+}
+
+// Fetches a pull request with its close and merge times from the activity log and size from the
+// diffstat
+func (c *BitbucketClient) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	c.logger.Debug("Fetching details for PR #%d", number)
+
+	var raw bitbucketPullRequest
+	if _, err := c.rest.getJSON(fmt.Sprintf("%s/pullrequests/%d", bitbucketRepoPath(owner, repo), number), nil, &raw); err != nil {
+		return nil, err
+	}
+	pr := raw.toPullRequest()
+
+	// Bitbucket does not expose close or merge timestamps on the pull request itself
+	if raw.State != "OPEN" {
+		activities, err := c.getActivity(owner, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		applyStateUpdate(pr, raw.State, activities)
+	}
+
+	files, err := c.GetPRFiles(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	additions, deletions := 0, 0
+	for _, file := range files {
+		additions += file.GetAdditions()
+		deletions += file.GetDeletions()
+	}
+	pr.Additions = github.Ptr(additions)
+	pr.Deletions = github.Ptr(deletions)
+	pr.ChangedFiles = github.Ptr(len(files))
+
+	return pr, nil
+}
+
+// Fetches all commits of a pull request, oldest first to match GitHub ordering
+func (c *BitbucketClient) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	c.logger.Debug("Fetching commits for PR #%d", number)
+
+	var allCommits []*github.RepositoryCommit
+	err := paginateBitbucket(c, fmt.Sprintf("%s/pullrequests/%d/commits", bitbucketRepoPath(owner, repo), number), nil, func(values []bitbucketCommit) {
+		for _, commit := range values {
+			author := &github.CommitAuthor{
+				Name: github.Ptr(commit.Author.Raw),
+				Date: &github.Timestamp{Time: commit.Date},
+			}
+			repositoryCommit := &github.RepositoryCommit{
+				SHA: github.Ptr(commit.Hash),
+				Commit: &github.Commit{
+					Message:   github.Ptr(commit.Message),
+					Author:    author,
+					Committer: author,
+				},
+			}
+			if commit.Author.User != nil {
+				repositoryCommit.Author = &github.User{Login: github.Ptr(commit.Author.User.Nickname)}
+			}
+			for _, parent := range commit.Parents {
+				repositoryCommit.Parents = append(repositoryCommit.Parents, &github.Commit{SHA: github.Ptr(parent.Hash)})
+			}
+			allCommits = append(allCommits, repositoryCommit)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Bitbucket lists pull request commits newest first
+	slices.Reverse(allCommits)
+
+	c.logger.Debug("Fetched %d commits for PR #%d", len(allCommits), number)
+	return allCommits, nil
+}
+
+// Fetches inline comments, which correspond to GitHub review comments
+func (c *BitbucketClient) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	c.logger.Debug("Fetching comments for PR #%d", number)
+
+	var allComments []*github.PullRequestComment
+	err := paginateBitbucket(c, fmt.Sprintf("%s/pullrequests/%d/comments", bitbucketRepoPath(owner, repo), number), nil, func(values []bitbucketComment) {
+		for _, comment := range values {
+			if comment.Deleted || comment.Inline == nil {
+				continue
+			}
+			allComments = append(allComments, &github.PullRequestComment{
+				ID:        github.Ptr(comment.ID),
+				Body:      github.Ptr(comment.Content.Raw),
+				Path:      github.Ptr(comment.Inline.Path),
+				User:      &github.User{Login: github.Ptr(comment.User.Nickname)},
+				CreatedAt: &github.Timestamp{Time: comment.CreatedOn},
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches all PR comments that are not attached to a file
+func (c *BitbucketClient) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	c.logger.Debug("Fetching conversation comments for PR #%d", number)
+
+	var allComments []*github.IssueComment
+	err := paginateBitbucket(c, fmt.Sprintf("%s/pullrequests/%d/comments", bitbucketRepoPath(owner, repo), number), nil, func(values []bitbucketComment) {
+		for _, comment := range values {
+			if comment.Deleted || comment.Inline != nil {
+				continue
+			}
+			allComments = append(allComments, &github.IssueComment{
+				ID:        github.Ptr(comment.ID),
+				Body:      github.Ptr(comment.Content.Raw),
+				User:      &github.User{Login: github.Ptr(comment.User.Nickname)},
+				CreatedAt: &github.Timestamp{Time: comment.CreatedOn},
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d conversation comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches the activity log of a pull request, newest first. The details and the reviews of a PR
+// are both read from it, so the log of the PR last fetched is kept and downloaded once per PR.
+func (c *BitbucketClient) getActivity(owner, repo string, number int) ([]bitbucketActivity, error) {
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	if key == c.activityKey {
+		return c.activityCache, nil
+	}
+
+	var allActivities []bitbucketActivity
+	err := paginateBitbucket(c, fmt.Sprintf("%s/pullrequests/%d/activity", bitbucketRepoPath(owner, repo), number), nil, func(values []bitbucketActivity) {
+		allActivities = append(allActivities, values...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.activityKey, c.activityCache = key, allActivities
+	return allActivities, nil
+}
+
+// Sets the close time of a PR that is no longer open, and the merge time and merger of a merged
+// one, from the state update that moved it into its current state. The log is newest first, so
+// that is the last update in the state.
+func applyStateUpdate(pr *github.PullRequest, state string, activities []bitbucketActivity) {
+	var transition *bitbucketActivity
+	for i, activity := range activities {
+		if activity.Update != nil && activity.Update.State == state {
+			transition = &activities[i]
+		}
+	}
+	if transition == nil {
+		return
+	}
+
+	update := transition.Update
+	pr.ClosedAt = &github.Timestamp{Time: update.Date}
+	if state == "MERGED" {
+		pr.MergedAt = &github.Timestamp{Time: update.Date}
+		pr.MergedBy = &github.User{Login: github.Ptr(update.Author.Nickname)}
+	}
+}
+
+// Derives reviews from approval and change request entries in the activity log, named by the
+// entry so the Bitbucket converter maps them to review states
+func (c *BitbucketClient) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	c.logger.Debug("Fetching reviews for PR #%d", number)
+
+	activities, err := c.getActivity(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allReviews []*github.PullRequestReview
+	for _, activity := range activities {
+		switch {
+		case activity.Approval != nil:
+			allReviews = append(allReviews, &github.PullRequestReview{
+				User:        &github.User{Login: github.Ptr(activity.Approval.User.Nickname)},
+				State:       github.Ptr(bitbucketApproval),
+				SubmittedAt: &github.Timestamp{Time: activity.Approval.Date},
+			})
+		case activity.ChangesRequested != nil:
+			allReviews = append(allReviews, &github.PullRequestReview{
+				User:        &github.User{Login: github.Ptr(activity.ChangesRequested.User.Nickname)},
+				State:       github.Ptr(bitbucketChangesRequested),
+				SubmittedAt: &github.Timestamp{Time: activity.ChangesRequested.Date},
+			})
+		}
+	}
+
+	// The activity log is newest first
+	slices.Reverse(allReviews)
+
+	c.logger.Debug("Fetched %d reviews for PR #%d", len(allReviews), number)
+	return allReviews, nil
+}
+
+// Fetches the changed files of a pull request from its diffstat
+func (c *BitbucketClient) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	c.logger.Debug("Fetching files for PR #%d", number)
+
+	var allFiles []*github.CommitFile
+	err := paginateBitbucket(c, fmt.Sprintf("%s/pullrequests/%d/diffstat", bitbucketRepoPath(owner, repo), number), nil, func(values []bitbucketDiffStat) {
+		for _, stat := range values {
+			file := &github.CommitFile{
+				Status:    github.Ptr(stat.Status),
+				Additions: github.Ptr(stat.LinesAdded),
+				Deletions: github.Ptr(stat.LinesRemoved),
+				Changes:   github.Ptr(stat.LinesAdded + stat.LinesRemoved),
+			}
+			if stat.New != nil {
+				file.Filename = github.Ptr(stat.New.Path)
+			} else if stat.Old != nil {
+				file.Filename = github.Ptr(stat.Old.Path)
+			}
+			if stat.Old != nil {
+				file.PreviousFilename = github.Ptr(stat.Old.Path)
+			}
+			allFiles = append(allFiles, file)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d files for PR #%d", len(allFiles), number)
+	return allFiles, nil
+}
+
+// Returns no timeline events, since a declined Bitbucket PR cannot be reopened and reviewers
+// are listed on the PR itself
+func (c *BitbucketClient) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	return nil, nil
+}
+
+// Fetches the inline comments that start a thread, which carry its resolution
+func (c *BitbucketClient) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	c.logger.Debug("Fetching review threads for PR #%d", number)
+
+	var allThreads []*ReviewThread
+	err := paginateBitbucket(c, fmt.Sprintf("%s/pullrequests/%d/comments", bitbucketRepoPath(owner, repo), number), nil, func(values []bitbucketComment) {
+		for _, comment := range values {
+			if comment.Deleted || comment.Inline == nil || comment.Parent != nil {
+				continue
+			}
+			allThreads = append(allThreads, &ReviewThread{
+				ID:        strconv.FormatInt(comment.ID, 10),
+				Path:      comment.Inline.Path,
+				Author:    comment.User.Nickname,
+				CreatedAt: comment.CreatedOn,
+				Resolved:  comment.Resolution != nil,
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d review threads for PR #%d", len(allThreads), number)
+	return allThreads, nil
+}
+
+// Returns no code owners, since Bitbucket Cloud has no CODEOWNERS support
+func (c *BitbucketClient) GetCodeOwners(owner, repo, ref string) (string, error) {
+	return "", nil
+}
+
+// Returns no attributes, since Bitbucket Cloud serves file contents only as raw text
+func (c *BitbucketClient) GetGitAttributes(owner, repo, ref string) (string, error) {
+	return "", nil
+}
+
+// Fetches the repository. Bitbucket has no topics, and its language is set by hand.
+func (c *BitbucketClient) GetRepository(owner, repo string) (*github.Repository, error) {
+	c.logger.Debug("Fetching repository %s/%s", owner, repo)
+
+	var repository struct {
+		FullName   string `json:"full_name"`
+		Language   string `json:"language"`
+		IsPrivate  bool   `json:"is_private"`
+		MainBranch *struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if _, err := c.rest.getJSON(bitbucketRepoPath(owner, repo), nil, &repository); err != nil {
+		return nil, err
+	}
+
+	result := &github.Repository{
+		FullName:   github.Ptr(repository.FullName),
+		Language:   github.Ptr(repository.Language),
+		Visibility: github.Ptr(visibilityOf(repository.IsPrivate)),
+	}
+	if repository.MainBranch != nil {
+		result.DefaultBranch = github.Ptr(repository.MainBranch.Name)
+	}
+	return result, nil
+}
+
+// Maps the "require approvals to merge" branch restriction onto GitHub branch protection
+func (c *BitbucketClient) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	c.logger.Debug("Fetching branch restrictions for %s", branch)
+
+	query := url.Values{}
+	query.Set("kind", "require_approvals_to_merge")
+
+	var protection *github.Protection
+	err := paginateBitbucket(c, bitbucketRepoPath(owner, repo)+"/branch-restrictions", query, func(values []bitbucketBranchRestriction) {
+		for _, restriction := range values {
+			if matched, _ := path.Match(restriction.Pattern, branch); !matched {
+				continue
+			}
+			if protection == nil {
+				protection = &github.Protection{RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{}}
+			}
+			reviews := protection.RequiredPullRequestReviews
+			reviews.RequiredApprovingReviewCount = max(reviews.RequiredApprovingReviewCount, restriction.Value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return protection, nil
+}
+
+// Fetches build statuses for a commit
+func (c *BitbucketClient) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	c.logger.Debug("Fetching commit statuses for %s", ref)
+
+	var allStatuses []*github.RepoStatus
+	err := paginateBitbucket(c, fmt.Sprintf("%s/commit/%s/statuses", bitbucketRepoPath(owner, repo), url.PathEscape(ref)), nil, func(values []bitbucketCommitStatus) {
+		for _, status := range values {
+			state := "pending"
+			switch status.State {
+			case "SUCCESSFUL":
+				state = "success"
+			case "FAILED", "STOPPED":
+				state = "failure"
+			}
+			allStatuses = append(allStatuses, &github.RepoStatus{
+				Context:   github.Ptr(status.Name),
+				State:     github.Ptr(state),
+				CreatedAt: &github.Timestamp{Time: status.CreatedOn},
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allStatuses, nil
+}
+
+// Bitbucket has no check runs; build results are reported as commit statuses instead
+func (c *BitbucketClient) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	return nil, nil
+}
+
+// Converts a Bitbucket pull request into a GitHub pull request
+func (pr bitbucketPullRequest) toPullRequest() *github.PullRequest {
+	state := "closed"
+	if pr.State == "OPEN" {
+		state = "open"
+	}
+
+	converted := &github.PullRequest{
+		Number:    github.Ptr(pr.ID),
+		Title:     github.Ptr(pr.Title),
+		State:     github.Ptr(state),
+		User:      &github.User{Login: github.Ptr(pr.Author.Nickname)},
+		CreatedAt: &github.Timestamp{Time: pr.CreatedOn},
+		UpdatedAt: &github.Timestamp{Time: pr.UpdatedOn},
+		Base:      &github.PullRequestBranch{Ref: github.Ptr(pr.Destination.Branch.Name)},
+		Head:      &github.PullRequestBranch{Ref: github.Ptr(pr.Source.Branch.Name), SHA: github.Ptr(pr.Source.Commit.Hash)},
+	}
+
+	// Without the activity log only the last update is known, which stands in for the close and
+	// merge times until applyStateUpdate replaces them; listings filtered by those times and the
+	// PR details always do
+	if pr.State != "OPEN" {
+		converted.ClosedAt = &github.Timestamp{Time: pr.UpdatedOn}
+	}
+	if pr.State == "MERGED" {
+		converted.MergedAt = &github.Timestamp{Time: pr.UpdatedOn}
+	}
+
+	return converted
+}
+
+// Converts Bitbucket responses, whose reviews are named after activity log entries and whose
+// reviewers are only ever people, to the provider-neutral model
+type bitbucketConverter struct {
+	GitHubConverter
+}
+
+// Converts a pull request, which Bitbucket cannot ask a team to review
+func (bitbucketConverter) PullRequest(pr *github.PullRequest) *model.PullRequest {
+	return newPullRequest(pr)
+}
+
+// Converts an approval or change request entry into a review
+func (bitbucketConverter) ReviewEvent(review *github.PullRequestReview) *model.ReviewEvent {
+	switch review.GetState() {
+	case bitbucketApproval:
+		return newReviewEvent(review, model.ReviewStateApproved)
+	case bitbucketChangesRequested:
+		return newReviewEvent(review, model.ReviewStateChangesRequested)
+	default:
+		return recordedReviewEvent(review)
+	}
+}
+
+// Returns the API requests made so far
+func (c *BitbucketClient) Usage() APIUsage {
+	return c.rest.tracker.usage()
+}
+
+// Limits the API requests made from now on
+func (c *BitbucketClient) SetRequestBudget(budget RequestBudget) {
+	c.rest.tracker.setBudget(budget)
+}
+
+// Caches responses on disk, revalidating them with ETags once they are stale
+func (c *BitbucketClient) EnableResponseCache(options CacheOptions) error {
+	return c.rest.tracker.enableCache(options)
+}
+
+// Routes requests through a proxy and applies custom TLS settings
+func (c *BitbucketClient) ConfigureTransport(options TransportOptions) error {
+	return c.rest.tracker.configureTransport(options)
+}
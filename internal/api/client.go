@@ -2,6 +2,9 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -12,20 +15,41 @@ import (
 
 // Wraps GitHub API with authentication and enterprise server support
 type Client struct {
-	client *github.Client
-	ctx    context.Context
-	logger *utils.Logger
+	client         *github.Client
+	tracker        *requestTracker
+	ctx            context.Context
+	logger         *utils.Logger
+	enterprise     bool            // Talking to GitHub Enterprise Server rather than GitHub.com
+	serverVersion  string          // Version of GitHub Enterprise Server, once fetched
+	versionFetched bool            // Whether the server version was requested
+	unsupported    map[string]bool // Features the server turned down
 }
 
-// Configures GitHub API client with authentication and custom base URL support
-func NewClient(apiURL, token string, logger *utils.Logger) (*Client, error) {
+// Configures GitHub API client with authentication and custom base URL support.
+// Several tokens are rotated as their rate limits run low.
+func NewClient(apiURL string, tokens []string, logger *utils.Logger) (*Client, error) {
 	ctx := context.Background()
 
 	// Create a new client with auth token
-	client := github.NewClient(nil).WithAuthToken(token)
+	tracker := newRequestTracker(logger)
+	client := github.NewClient(&http.Client{Transport: tracker})
+	switch len(tokens) {
+	case 0:
+		return nil, errors.New("no token given")
+	case 1:
+		client = client.WithAuthToken(tokens[0])
+	default:
+		tracker.rotateTokens(tokens, logger)
+		logger.Debug("Rotating between %d tokens", len(tokens))
+		// go-github refuses requests once the last response reported an exhausted limit,
+		// which would stop the rotation to the next token
+		ctx = context.WithValue(ctx, github.BypassRateLimitCheck, true)
+	}
 
 	// Set custom API URL for GitHub Enterprise
-	if apiURL != "https://api.github.com" {
+	apiURL = NormalizeGitHubURL(apiURL)
+	enterprise := apiURL != "https://api.github.com"
+	if enterprise {
 		// Ensure the URL has a trailing slash
 		if !strings.HasSuffix(apiURL, "/") {
 			apiURL += "/"
@@ -40,23 +64,43 @@ func NewClient(apiURL, token string, logger *utils.Logger) (*Client, error) {
 	}
 
 	return &Client{
-		client: client,
-		ctx:    ctx,
-		logger: logger,
+		client:      client,
+		tracker:     tracker,
+		ctx:         ctx,
+		logger:      logger,
+		enterprise:  enterprise,
+		unsupported: make(map[string]bool),
 	}, nil
 }
 
-// Fetches all PRs created within date range using paginated API calls
-func (c *Client) GetPullRequests(owner, repo string, startDate, endDate time.Time) ([]*github.PullRequest, error) {
-	c.logger.Debug("Fetching pull requests for %s/%s from %s to %s", owner, repo, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+// Fetches all PRs matching the query using paginated API calls, newest first so paging
+// can stop as soon as the remaining PRs are older than the query range
+func (c *Client) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	c.logger.Debug("Fetching pull requests for %s/%s from %s to %s", owner, repo, query.StartDate.Format("2006-01-02"), query.EndDate.Format("2006-01-02"))
 
 	opts := &github.PullRequestListOptions{
-		State: "all",
+		State:     query.ListState(),
+		Sort:      "created",
+		Direction: "desc",
 		ListOptions: github.ListOptions{
 			PerPage: 100,
 		},
 	}
 
+	// When filtering on merge/close time or including updated PRs, sort by update time
+	// instead. PRs matching the range were updated after its start, so the earlier of the
+	// two bounds is the cutoff.
+	cutoff := query.StartDate
+	if query.DateField == DateFieldMerged || query.DateField == DateFieldClosed {
+		opts.Sort = "updated"
+	}
+	if !query.UpdatedSince.IsZero() {
+		opts.Sort = "updated"
+		if query.UpdatedSince.Before(cutoff) {
+			cutoff = query.UpdatedSince
+		}
+	}
+
 	var allPRs []*github.PullRequest
 
 	for {
@@ -66,19 +110,24 @@ func (c *Client) GetPullRequests(owner, repo string, startDate, endDate time.Tim
 		}
 
 		// Filter PRs by date
+		reachedCutoff := false
 		for _, pr := range prs {
-			if pr.CreatedAt != nil {
-				createdAt := pr.CreatedAt.Time
-				if (createdAt.After(startDate) || createdAt.Equal(startDate)) &&
-					(createdAt.Before(endDate) || createdAt.Equal(endDate)) {
-					allPRs = append(allPRs, pr)
-				}
+			if query.Matches(pr) {
+				allPRs = append(allPRs, pr)
+			}
+
+			sortedAt := pr.GetCreatedAt()
+			if opts.Sort == "updated" {
+				sortedAt = pr.GetUpdatedAt()
+			}
+			if sortedAt.Before(cutoff) {
+				reachedCutoff = true
 			}
 		}
 
 		c.logger.Debug("Fetched page %d of pull requests (%d total so far)", opts.Page, len(allPRs))
 
-		if resp.NextPage == 0 {
+		if resp.NextPage == 0 || reachedCutoff {
 			break
 		}
 		opts.Page = resp.NextPage
@@ -155,6 +204,67 @@ func (c *Client) GetPRComments(owner, repo string, number int) ([]*github.PullRe
 	return allComments, nil
 }
 
+// Fetches all conversation comments on a PR, which GitHub stores as issue comments
+func (c *Client) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	c.logger.Debug("Fetching conversation comments for PR #%d", number)
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var allComments []*github.IssueComment
+
+	for {
+		comments, resp, err := c.client.Issues.ListComments(c.ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		allComments = append(allComments, comments...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.logger.Debug("Fetched %d conversation comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches the close, reopen, and review request events of a PR using paginated requests
+func (c *Client) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	c.logger.Debug("Fetching timeline events for PR #%d", number)
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	var allEvents []*github.IssueEvent
+
+	for {
+		events, resp, err := c.client.Issues.ListIssueEvents(c.ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			switch event.GetEvent() {
+			case "closed", "reopened", "review_requested", "review_request_removed":
+				allEvents = append(allEvents, event)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.logger.Debug("Fetched %d timeline events for PR #%d", len(allEvents), number)
+	return allEvents, nil
+}
+
 // Fetches all code reviews for a PR using paginated requests
 func (c *Client) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
 	c.logger.Debug("Fetching reviews for PR #%d", number)
@@ -181,3 +291,311 @@ func (c *Client) GetPRReviews(owner, repo string, number int) ([]*github.PullReq
 	c.logger.Debug("Fetched %d reviews for PR #%d", len(allReviews), number)
 	return allReviews, nil
 }
+
+// Fetches all changed files for a PR using paginated requests
+func (c *Client) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	c.logger.Debug("Fetching files for PR #%d", number)
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	var allFiles []*github.CommitFile
+
+	for {
+		files, resp, err := c.client.PullRequests.ListFiles(c.ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		allFiles = append(allFiles, files...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.logger.Debug("Fetched %d files for PR #%d", len(allFiles), number)
+	return allFiles, nil
+}
+
+// Pages through the review threads of a PR, which only the GraphQL API exposes
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        nodes {
+          id
+          path
+          isResolved
+          comments(first: 1) {
+            nodes {
+              createdAt
+              author { login }
+            }
+          }
+        }
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
+      }
+    }
+  }
+}`
+
+// Response of the review threads query
+type reviewThreadsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						ID         string `json:"id"`
+						Path       string `json:"path"`
+						IsResolved bool   `json:"isResolved"`
+						Comments   struct {
+							Nodes []struct {
+								CreatedAt time.Time `json:"createdAt"`
+								Author    *struct {
+									Login string `json:"login"`
+								} `json:"author"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Fetches all review threads of a PR through the GraphQL API
+func (c *Client) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	c.logger.Debug("Fetching review threads for PR #%d", number)
+	if c.isUnsupported(featureReviewThreads) {
+		return nil, nil
+	}
+
+	var allThreads []*ReviewThread
+	var cursor *string
+	for {
+		body := map[string]any{
+			"query": reviewThreadsQuery,
+			"variables": map[string]any{
+				"owner":  owner,
+				"repo":   repo,
+				"number": number,
+				"cursor": cursor,
+			},
+		}
+		req, err := c.client.NewRequest(http.MethodPost, c.graphQLURL(), body)
+		if err != nil {
+			return nil, err
+		}
+
+		var response reviewThreadsResponse
+		if _, err := c.client.Do(c.ctx, req, &response); err != nil {
+			if c.markUnsupported(featureReviewThreads, err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		// GraphQL reports errors in the body of a successful response
+		if len(response.Errors) > 0 {
+			err := fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+			if c.markUnsupported(featureReviewThreads, err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		threads := response.Data.Repository.PullRequest.ReviewThreads
+		for _, node := range threads.Nodes {
+			thread := &ReviewThread{
+				ID:       node.ID,
+				Path:     node.Path,
+				Resolved: node.IsResolved,
+			}
+			if len(node.Comments.Nodes) > 0 {
+				first := node.Comments.Nodes[0]
+				thread.CreatedAt = first.CreatedAt
+				// Comments of deleted accounts have no author
+				if first.Author != nil {
+					thread.Author = first.Author.Login
+				}
+			}
+			allThreads = append(allThreads, thread)
+		}
+
+		if !threads.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &threads.PageInfo.EndCursor
+	}
+
+	c.logger.Debug("Fetched %d review threads for PR #%d", len(allThreads), number)
+	return allThreads, nil
+}
+
+// Returns the GraphQL endpoint, which GitHub Enterprise Server serves at /api/graphql beside the /api/v3 REST API
+func (c *Client) graphQLURL() string {
+	if strings.HasSuffix(c.client.BaseURL.Path, "/api/v3/") {
+		graphQLURL := *c.client.BaseURL
+		graphQLURL.Path = strings.TrimSuffix(graphQLURL.Path, "v3/") + "graphql"
+		return graphQLURL.String()
+	}
+	return "graphql"
+}
+
+// Fetches the repository, including its primary language, topics, visibility, and default branch
+func (c *Client) GetRepository(owner, repo string) (*github.Repository, error) {
+	c.logger.Debug("Fetching repository %s/%s", owner, repo)
+	repository, _, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+// Fetches protection rules for a branch, returning nil when the branch is not protected
+func (c *Client) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	c.logger.Debug("Fetching branch protection for %s", branch)
+	protection, _, err := c.client.Repositories.GetBranchProtection(c.ctx, owner, repo, branch)
+	if errors.Is(err, github.ErrBranchNotProtected) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return protection, nil
+}
+
+// Fetches the CODEOWNERS file of a ref, returning an empty string when the repository has none
+func (c *Client) GetCodeOwners(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching CODEOWNERS for %s", ref)
+	// GitHub uses the first file found in these locations
+	for _, path := range []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"} {
+		file, _, resp, err := c.client.Repositories.GetContents(c.ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if file == nil {
+			continue
+		}
+		return file.GetContent()
+	}
+
+	return "", nil
+}
+
+// Fetches the root .gitattributes file of a ref, returning an empty string when the repository has none
+func (c *Client) GetGitAttributes(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching .gitattributes for %s", ref)
+	file, _, resp, err := c.client.Repositories.GetContents(c.ctx, owner, repo, ".gitattributes", &github.RepositoryContentGetOptions{Ref: ref})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return "", nil
+	}
+	return file.GetContent()
+}
+
+// Fetches the latest commit statuses for a ref using paginated requests
+func (c *Client) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	c.logger.Debug("Fetching commit statuses for %s", ref)
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+
+	var allStatuses []*github.RepoStatus
+
+	for {
+		combined, resp, err := c.client.Repositories.GetCombinedStatus(c.ctx, owner, repo, ref, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		allStatuses = append(allStatuses, combined.Statuses...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.logger.Debug("Fetched %d commit statuses for %s", len(allStatuses), ref)
+	return allStatuses, nil
+}
+
+// Fetches all check runs for a ref, including the earlier attempts of re-run checks, using
+// paginated requests
+func (c *Client) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	c.logger.Debug("Fetching check runs for %s", ref)
+	if c.isUnsupported(featureCheckRuns) {
+		return nil, nil
+	}
+	opts := &github.ListCheckRunsOptions{
+		Filter: github.Ptr("all"),
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var allCheckRuns []*github.CheckRun
+
+	for {
+		result, resp, err := c.client.Checks.ListCheckRunsForRef(c.ctx, owner, repo, ref, opts)
+		if err != nil {
+			if c.markUnsupported(featureCheckRuns, err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		allCheckRuns = append(allCheckRuns, result.CheckRuns...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.logger.Debug("Fetched %d check runs for %s", len(allCheckRuns), ref)
+	return allCheckRuns, nil
+}
+
+// Returns the API requests made so far
+func (c *Client) Usage() APIUsage {
+	return c.tracker.usage()
+}
+
+// Limits the API requests made from now on
+func (c *Client) SetRequestBudget(budget RequestBudget) {
+	c.tracker.setBudget(budget)
+}
+
+// Caches responses on disk, revalidating them with ETags once they are stale
+func (c *Client) EnableResponseCache(options CacheOptions) error {
+	return c.tracker.enableCache(options)
+}
+
+// Routes requests through a proxy and applies custom TLS settings
+func (c *Client) ConfigureTransport(options TransportOptions) error {
+	return c.tracker.configureTransport(options)
+}
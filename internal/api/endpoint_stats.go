@@ -0,0 +1,103 @@
+package api
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Set by the response cache on responses it replays, so cache hits can be counted
+const cacheHitHeader = "X-From-Cache"
+
+var (
+	numericSegmentPattern = regexp.MustCompile(`^\d+$`)
+	commitSHAPattern      = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// API usage of one endpoint, with path parameters such as PR numbers collapsed
+type EndpointUsage struct {
+	Endpoint     string  `json:"endpoint"`
+	Requests     int     `json:"requests"`
+	CacheHits    int     `json:"cache_hits"`
+	Bytes        int64   `json:"bytes"`
+	LatencyP50Ms float64 `json:"latency_p50_ms"`
+	LatencyP90Ms float64 `json:"latency_p90_ms"`
+	LatencyP99Ms float64 `json:"latency_p99_ms"`
+}
+
+// Request counts, response sizes, and latencies collected for one endpoint
+type endpointStats struct {
+	requests  int
+	cacheHits int
+	bytes     atomic.Int64 // Added to while response bodies are read, after the request completes
+	latencies []time.Duration
+}
+
+// Names the endpoint of a request, replacing numbers and commit SHAs in the path
+// so requests for different PRs and commits are counted together
+func endpointName(req *http.Request) string {
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+	for i, segment := range segments {
+		switch {
+		case numericSegmentPattern.MatchString(segment):
+			segments[i] = "{number}"
+		case commitSHAPattern.MatchString(segment):
+			segments[i] = "{sha}"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
+
+// Counts the bytes of a response body as it is read
+type countingBody struct {
+	io.ReadCloser
+	stats *endpointStats
+}
+
+// Reads from the body and adds the bytes read to the endpoint
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.stats.bytes.Add(int64(n))
+	return n, err
+}
+
+// Summarizes the collected statistics, busiest endpoints first
+func summarizeEndpoints(endpoints map[string]*endpointStats) []EndpointUsage {
+	summaries := make([]EndpointUsage, 0, len(endpoints))
+	for name, stats := range endpoints {
+		latencies := slices.Clone(stats.latencies)
+		slices.Sort(latencies)
+		summaries = append(summaries, EndpointUsage{
+			Endpoint:     name,
+			Requests:     stats.requests,
+			CacheHits:    stats.cacheHits,
+			Bytes:        stats.bytes.Load(),
+			LatencyP50Ms: percentileMilliseconds(latencies, 50),
+			LatencyP90Ms: percentileMilliseconds(latencies, 90),
+			LatencyP99Ms: percentileMilliseconds(latencies, 99),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Requests != summaries[j].Requests {
+			return summaries[i].Requests > summaries[j].Requests
+		}
+		return summaries[i].Endpoint < summaries[j].Endpoint
+	})
+	return summaries
+}
+
+// Returns the nearest-rank percentile of sorted durations in milliseconds, rounded to 0.1 ms
+func percentileMilliseconds(sorted []time.Duration, percentile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	return math.Round(float64(sorted[max(rank, 1)-1])/float64(time.Millisecond)*10) / 10
+}
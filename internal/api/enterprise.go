@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// Endpoints older GitHub Enterprise Server versions may lack, skipped once the server turns them down
+const (
+	featureReviewThreads = "review threads"
+	featureCheckRuns     = "check runs"
+)
+
+// Completes a GitHub API URL given as a bare GitHub Enterprise Server host name, such as
+// github.example.com, with the scheme and the /api/v3 path of its REST API, and maps
+// github.com to the public API
+func NormalizeGitHubURL(apiURL string) string {
+	if !strings.Contains(apiURL, "://") {
+		apiURL = "https://" + apiURL
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		return apiURL
+	}
+
+	switch {
+	case parsed.Host == "github.com" || parsed.Host == "api.github.com":
+		return "https://api.github.com"
+	case parsed.Path == "" || parsed.Path == "/":
+		parsed.Path = "/api/v3/"
+	}
+	return parsed.String()
+}
+
+// Returns the version of the GitHub Enterprise Server, or an empty string on GitHub.com or if
+// the server does not tell. The version is fetched once, on first use.
+func (c *Client) ServerVersion() string {
+	if !c.enterprise || c.versionFetched {
+		return c.serverVersion
+	}
+	c.versionFetched = true
+
+	req, err := c.client.NewRequest(http.MethodGet, "meta", nil)
+	if err != nil {
+		return ""
+	}
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if _, err := c.client.Do(c.ctx, req, &meta); err != nil {
+		c.logger.Debug("Failed to get the GitHub Enterprise Server version: %v", err)
+		return ""
+	}
+	c.serverVersion = meta.InstalledVersion
+	return c.serverVersion
+}
+
+// Reports whether an earlier request found the feature missing from the server
+func (c *Client) isUnsupported(feature string) bool {
+	return c.unsupported[feature]
+}
+
+// Reports whether the error shows a GitHub Enterprise Server lacking the feature, remembering it
+// so the feature is not requested again. Results of the feature are left empty from then on.
+func (c *Client) markUnsupported(feature string, err error) bool {
+	if !c.enterprise {
+		return false
+	}
+
+	var errorResponse *github.ErrorResponse
+	notFound := errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound
+	unknownField := strings.Contains(err.Error(), "doesn't exist on type")
+	if !notFound && !unknownField {
+		return false
+	}
+
+	version := c.ServerVersion()
+	if version == "" {
+		version = "(unknown version)"
+	}
+	c.logger.Warn("GitHub Enterprise Server %s does not support %s; they are left out of the metrics", version, feature)
+	c.unsupported[feature] = true
+	return true
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Reports whether the error was caused by missing or invalid credentials
+func IsAuthError(err error) bool {
+	var errorResponse *github.ErrorResponse
+	if errors.As(err, &errorResponse) && errorResponse.Response != nil {
+		return isAuthStatus(errorResponse.Response.StatusCode)
+	}
+
+	var apiErr *utils.APIError
+	if errors.As(err, &apiErr) {
+		return isAuthStatus(apiErr.StatusCode)
+	}
+
+	return false
+}
+
+// Reports whether the error was caused by exceeding the API rate limit or the request budget
+func IsRateLimitError(err error) bool {
+	if errors.Is(err, ErrRequestBudgetExhausted) {
+		return true
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	var utilsRateLimitErr *utils.RateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) || errors.As(err, &utilsRateLimitErr) {
+		return true
+	}
+
+	var apiErr *utils.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// Reports whether the status code indicates rejected credentials
+func isAuthStatus(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Serves recorded GitHub API responses from disk so metrics can be computed offline.
+//
+// Fixtures are laid out per repository as:
+//
+//	<dir>/<owner>/<repo>/{pulls,repository}.json
+//	<dir>/<owner>/<repo>/pulls/<number>/{details,commits,comments,issue_comments,reviews,files}.json
+//	<dir>/<owner>/<repo>/branches/<branch>/{protection,codeowners}.json
+//	<dir>/<owner>/<repo>/commits/<sha>/{statuses,check_runs}.json
+type FixtureClient struct {
+	dir    string
+	logger *utils.Logger
+}
+
+// Initializes fixture client reading from the given directory
+func NewFixtureClient(dir string, logger *utils.Logger) (*FixtureClient, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path is not a directory: %s", dir)
+	}
+	logger.Debug("Replaying API responses from %s", dir)
+
+	return &FixtureClient{
+		dir:    dir,
+		logger: logger,
+	}, nil
+}
+
+// Returns the fixture path for a repository-relative file
+func fixturePath(dir, owner, repo string, elem ...string) string {
+	return filepath.Join(append([]string{dir, owner, repo}, elem...)...)
+}
+
+// Returns the fixture path for a file belonging to a PR
+func fixturePRPath(dir, owner, repo string, number int, name string) string {
+	return fixturePath(dir, owner, repo, "pulls", strconv.Itoa(number), name)
+}
+
+// Decodes a JSON fixture file into out
+func (c *FixtureClient) readFixture(path string, out any) error {
+	c.logger.Debug("Reading fixture %s", path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode fixture %s: %v", path, err)
+	}
+	return nil
+}
+
+// Reads recorded PRs and filters them like the live client
+func (c *FixtureClient) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	var prs []*github.PullRequest
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "pulls.json"), &prs); err != nil {
+		return nil, err
+	}
+
+	var allPRs []*github.PullRequest
+	for _, pr := range prs {
+		if query.Matches(pr) {
+			allPRs = append(allPRs, pr)
+		}
+	}
+
+	c.logger.Debug("Read %d pull requests in total", len(allPRs))
+	return allPRs, nil
+}
+
+// Reads the recorded PR details
+func (c *FixtureClient) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	var pr github.PullRequest
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "details.json"), &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// Reads the recorded PR commits
+func (c *FixtureClient) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	var commits []*github.RepositoryCommit
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "commits.json"), &commits); err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// Reads the recorded PR review comments
+func (c *FixtureClient) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	var comments []*github.PullRequestComment
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "comments.json"), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Reads the recorded PR conversation comments, treating a missing file from older recordings as none
+func (c *FixtureClient) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	var comments []*github.IssueComment
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "issue_comments.json"), &comments); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Reads the recorded PR reviews
+func (c *FixtureClient) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	var reviews []*github.PullRequestReview
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "reviews.json"), &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// Reads the recorded PR files
+func (c *FixtureClient) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	var files []*github.CommitFile
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "files.json"), &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Reads the recorded review threads, treating a missing fixture as no threads
+func (c *FixtureClient) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	var threads []*ReviewThread
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "review_threads.json"), &threads); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return threads, nil
+}
+
+// Reads the recorded timeline events, treating a missing fixture as no events
+func (c *FixtureClient) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	var events []*github.IssueEvent
+	if err := c.readFixture(fixturePRPath(c.dir, owner, repo, number, "timeline_events.json"), &events); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return events, nil
+}
+
+// Reads the recorded repository, returning nil for recordings made before it was fetched
+func (c *FixtureClient) GetRepository(owner, repo string) (*github.Repository, error) {
+	var repository *github.Repository
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "repository.json"), &repository); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return repository, nil
+}
+
+// Reads the recorded branch protection; a JSON null means the branch is not protected
+func (c *FixtureClient) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	var protection *github.Protection
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "branches", branch, "protection.json"), &protection); err != nil {
+		return nil, err
+	}
+	return protection, nil
+}
+
+// Reads the recorded CODEOWNERS file, treating a missing fixture as no code owners
+func (c *FixtureClient) GetCodeOwners(owner, repo, ref string) (string, error) {
+	var content string
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "branches", ref, "codeowners.json"), &content); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return content, nil
+}
+
+// Reads the recorded .gitattributes file, treating a missing fixture as no attributes
+func (c *FixtureClient) GetGitAttributes(owner, repo, ref string) (string, error) {
+	var content string
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "branches", ref, "gitattributes.json"), &content); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return content, nil
+}
+
+// Reads the recorded commit statuses
+func (c *FixtureClient) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	var statuses []*github.RepoStatus
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "commits", ref, "statuses.json"), &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// Reads the recorded check runs
+func (c *FixtureClient) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	var checkRuns []*github.CheckRun
+	if err := c.readFixture(fixturePath(c.dir, owner, repo, "commits", ref, "check_runs.json"), &checkRuns); err != nil {
+		return nil, err
+	}
+	return checkRuns, nil
+}
+
+// Reports no API usage since responses are read from disk
+func (c *FixtureClient) Usage() APIUsage {
+	return APIUsage{RateLimitRemaining: -1}
+}
+
+// Ignores the request budget since responses are read from disk
+func (c *FixtureClient) SetRequestBudget(budget RequestBudget) {}
+
+// Ignores the response cache since responses are read from disk
+func (c *FixtureClient) EnableResponseCache(options CacheOptions) error {
+	return nil
+}
+
+// Ignores network settings since responses are read from disk
+func (c *FixtureClient) ConfigureTransport(options TransportOptions) error {
+	return nil
+}
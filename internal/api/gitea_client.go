@@ -0,0 +1,417 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Reads pull requests from Gitea or Forgejo, whose REST shapes mostly match GitHub's
+type GiteaClient struct {
+	rest   *restClient
+	logger *utils.Logger
+}
+
+// Configures Gitea/Forgejo API client with token authentication
+func NewGiteaClient(apiURL, token string, logger *utils.Logger) (*GiteaClient, error) {
+	if _, err := url.Parse(apiURL); err != nil {
+		return nil, err
+	}
+	logger.Debug("Using Gitea API URL: %s", apiURL)
+
+	return &GiteaClient{
+		rest:   newRESTClient(apiURL, map[string]string{"Authorization": "token " + token}, logger),
+		logger: logger,
+	}, nil
+}
+
+type giteaBranchProtection struct {
+	RequiredApprovals   int      `json:"required_approvals"`
+	EnableStatusCheck   bool     `json:"enable_status_check"`
+	StatusCheckContexts []string `json:"status_check_contexts"`
+}
+
+type giteaCommitStatus struct {
+	Context   string    `json:"context"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Timeline entry types the close and reopen events come from
+const (
+	giteaTimelineClose  = "close"
+	giteaTimelineReopen = "reopen"
+)
+
+type giteaTimelineEntry struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// Builds the repository path segment used by Gitea
+func giteaRepoPath(owner, repo string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// Follows Gitea's page-numbered pagination until the Link header has no next page
+func paginateGitea[T any](c *GiteaClient, path string, query url.Values, handle func(values []T)) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", "50")
+
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		var values []T
+		resp, err := c.rest.getJSON(path, query, &values)
+		if err != nil {
+			return err
+		}
+		handle(values)
+
+		if len(values) == 0 || !strings.Contains(resp.Header.Get("Link"), `rel="next"`) {
+			return nil
+		}
+	}
+}
+
+// Fetches all PRs matching the query using paginated API calls
+func (c *GiteaClient) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	c.logger.Debug("Fetching pull requests for %s/%s from %s to %s", owner, repo, query.StartDate.Format("2006-01-02"), query.EndDate.Format("2006-01-02"))
+
+	params := url.Values{}
+	params.Set("state", "all")
+
+	var allPRs []*github.PullRequest
+	err := paginateGitea(c, giteaRepoPath(owner, repo)+"/pulls", params, func(prs []*github.PullRequest) {
+		// Filter PRs by date
+		for _, pr := range prs {
+			if query.Matches(pr) {
+				allPRs = append(allPRs, pr)
+			}
+		}
+		c.logger.Debug("Fetched page of pull requests (%d total so far)", len(allPRs))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d pull requests in total", len(allPRs))
+	return allPRs, nil
+}
+
+// Fetches additions, deletions, and changed files count for a specific PR
+func (c *GiteaClient) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	c.logger.Debug("Fetching details for PR #%d", number)
+
+	var pr github.PullRequest
+	if _, err := c.rest.getJSON(fmt.Sprintf("%s/pulls/%d", giteaRepoPath(owner, repo), number), nil, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// Fetches all commits of a PR, oldest first to match GitHub ordering
+func (c *GiteaClient) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	c.logger.Debug("Fetching commits for PR #%d", number)
+
+	var allCommits []*github.RepositoryCommit
+	err := paginateGitea(c, fmt.Sprintf("%s/pulls/%d/commits", giteaRepoPath(owner, repo), number), nil, func(commits []*github.RepositoryCommit) {
+		allCommits = append(allCommits, commits...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Gitea lists PR commits newest first
+	slices.Reverse(allCommits)
+
+	c.logger.Debug("Fetched %d commits for PR #%d", len(allCommits), number)
+	return allCommits, nil
+}
+
+// Fetches inline comments of every review, since Gitea has no PR-wide review comment endpoint
+func (c *GiteaClient) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	c.logger.Debug("Fetching comments for PR #%d", number)
+
+	reviews, err := c.GetPRReviews(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allComments []*github.PullRequestComment
+	for _, review := range reviews {
+		// Comments of a pending review are drafts nobody else sees yet
+		if review.GetState() == "PENDING" {
+			continue
+		}
+		var comments []*github.PullRequestComment
+		path := fmt.Sprintf("%s/pulls/%d/reviews/%d/comments", giteaRepoPath(owner, repo), number, review.GetID())
+		if _, err := c.rest.getJSON(path, nil, &comments); err != nil {
+			return nil, err
+		}
+		allComments = append(allComments, comments...)
+	}
+
+	slices.SortStableFunc(allComments, func(a, b *github.PullRequestComment) int {
+		return a.GetCreatedAt().Compare(b.GetCreatedAt().Time)
+	})
+
+	c.logger.Debug("Fetched %d comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches all conversation comments on a PR, which Gitea stores as issue comments
+func (c *GiteaClient) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	c.logger.Debug("Fetching conversation comments for PR #%d", number)
+
+	var allComments []*github.IssueComment
+	if _, err := c.rest.getJSON(fmt.Sprintf("%s/issues/%d/comments", giteaRepoPath(owner, repo), number), nil, &allComments); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d conversation comments for PR #%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches all reviews of a PR with their Gitea states, which the Gitea converter maps
+func (c *GiteaClient) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	c.logger.Debug("Fetching reviews for PR #%d", number)
+
+	var allReviews []*github.PullRequestReview
+	err := paginateGitea(c, fmt.Sprintf("%s/pulls/%d/reviews", giteaRepoPath(owner, repo), number), nil, func(reviews []*github.PullRequestReview) {
+		allReviews = append(allReviews, reviews...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d reviews for PR #%d", len(allReviews), number)
+	return allReviews, nil
+}
+
+// Fetches all changed files for a PR using paginated requests
+func (c *GiteaClient) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	c.logger.Debug("Fetching files for PR #%d", number)
+
+	var allFiles []*github.CommitFile
+	err := paginateGitea(c, fmt.Sprintf("%s/pulls/%d/files", giteaRepoPath(owner, repo), number), nil, func(files []*github.CommitFile) {
+		allFiles = append(allFiles, files...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d files for PR #%d", len(allFiles), number)
+	return allFiles, nil
+}
+
+// Returns no review threads, since Gitea does not expose review comments grouped into conversations
+func (c *GiteaClient) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	return nil, nil
+}
+
+// Fetches the close and reopen entries of the PR timeline with their Gitea types
+func (c *GiteaClient) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	c.logger.Debug("Fetching timeline events for PR #%d", number)
+
+	var allEvents []*github.IssueEvent
+	err := paginateGitea(c, fmt.Sprintf("%s/issues/%d/timeline", giteaRepoPath(owner, repo), number), nil, func(entries []giteaTimelineEntry) {
+		for _, entry := range entries {
+			if entry.Type != giteaTimelineClose && entry.Type != giteaTimelineReopen {
+				continue
+			}
+			allEvents = append(allEvents, &github.IssueEvent{
+				ID:        github.Ptr(entry.ID),
+				Actor:     &github.User{Login: github.Ptr(entry.User.Login)},
+				Event:     github.Ptr(entry.Type),
+				CreatedAt: &github.Timestamp{Time: entry.CreatedAt},
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d timeline events for PR #%d", len(allEvents), number)
+	return allEvents, nil
+}
+
+// Fetches the repository, whose fields Gitea names as GitHub does apart from the visibility
+func (c *GiteaClient) GetRepository(owner, repo string) (*github.Repository, error) {
+	c.logger.Debug("Fetching repository %s/%s", owner, repo)
+
+	var repository github.Repository
+	if _, err := c.rest.getJSON(giteaRepoPath(owner, repo), nil, &repository); err != nil {
+		return nil, err
+	}
+	if repository.Visibility == nil {
+		repository.Visibility = github.Ptr(visibilityOf(repository.GetPrivate()))
+	}
+
+	return &repository, nil
+}
+
+// Fetches the protection rule for a branch, returning nil when the branch is not protected
+func (c *GiteaClient) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	c.logger.Debug("Fetching branch protection for %s", branch)
+
+	var rule giteaBranchProtection
+	_, err := c.rest.getJSON(fmt.Sprintf("%s/branch_protections/%s", giteaRepoPath(owner, repo), url.PathEscape(branch)), nil, &rule)
+	if apiErr, ok := err.(*utils.APIError); ok && apiErr.StatusCode == 404 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	protection := &github.Protection{
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{
+			RequiredApprovingReviewCount: rule.RequiredApprovals,
+		},
+	}
+	if rule.EnableStatusCheck {
+		contexts := rule.StatusCheckContexts
+		protection.RequiredStatusChecks = &github.RequiredStatusChecks{Contexts: &contexts}
+	}
+
+	return protection, nil
+}
+
+// Fetches the CODEOWNERS file of a ref, returning an empty string when the repository has none
+func (c *GiteaClient) GetCodeOwners(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching CODEOWNERS for %s", ref)
+
+	query := url.Values{}
+	query.Set("ref", ref)
+
+	// Gitea uses the first file found in these locations
+	for _, path := range []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitea/CODEOWNERS"} {
+		var file struct {
+			Content string `json:"content"`
+		}
+		_, err := c.rest.getJSON(fmt.Sprintf("%s/contents/%s", giteaRepoPath(owner, repo), path), query, &file)
+		if apiErr, ok := err.(*utils.APIError); ok && apiErr.StatusCode == 404 {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return decodeBase64Content(file.Content)
+	}
+
+	return "", nil
+}
+
+// Fetches the root .gitattributes file of a ref, returning an empty string when the repository has none
+func (c *GiteaClient) GetGitAttributes(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching .gitattributes for %s", ref)
+
+	query := url.Values{}
+	query.Set("ref", ref)
+
+	var file struct {
+		Content string `json:"content"`
+	}
+	_, err := c.rest.getJSON(fmt.Sprintf("%s/contents/.gitattributes", giteaRepoPath(owner, repo)), query, &file)
+	if apiErr, ok := err.(*utils.APIError); ok && apiErr.StatusCode == 404 {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return decodeBase64Content(file.Content)
+}
+
+// Fetches commit statuses, which Gitea Actions and external CI both report through
+func (c *GiteaClient) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	c.logger.Debug("Fetching commit statuses for %s", ref)
+
+	var allStatuses []*github.RepoStatus
+	err := paginateGitea(c, fmt.Sprintf("%s/commits/%s/statuses", giteaRepoPath(owner, repo), url.PathEscape(ref)), nil, func(statuses []giteaCommitStatus) {
+		for _, status := range statuses {
+			allStatuses = append(allStatuses, &github.RepoStatus{
+				Context:   github.Ptr(status.Context),
+				State:     github.Ptr(status.Status),
+				CreatedAt: &github.Timestamp{Time: status.CreatedAt},
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allStatuses, nil
+}
+
+// Gitea has no check runs; CI results are reported as commit statuses instead
+func (c *GiteaClient) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	return nil, nil
+}
+
+// Converts Gitea responses, whose review states and timeline types are named differently from
+// GitHub's, to the provider-neutral model
+type giteaConverter struct {
+	GitHubConverter
+}
+
+// Converts a review, leaving out pending reviews and review requests, which are no verdicts
+func (giteaConverter) ReviewEvent(review *github.PullRequestReview) *model.ReviewEvent {
+	switch review.GetState() {
+	case "APPROVED":
+		return newReviewEvent(review, model.ReviewStateApproved)
+	case "REQUEST_CHANGES":
+		return newReviewEvent(review, model.ReviewStateChangesRequested)
+	case "COMMENT":
+		return newReviewEvent(review, model.ReviewStateCommented)
+	default:
+		return recordedReviewEvent(review)
+	}
+}
+
+// Converts a close or reopen entry of the timeline
+func (giteaConverter) TimelineEvent(event *github.IssueEvent) *model.TimelineEvent {
+	var eventType string
+	switch event.GetEvent() {
+	case giteaTimelineClose:
+		eventType = model.TimelineEventClosed
+	case giteaTimelineReopen:
+		eventType = model.TimelineEventReopened
+	default:
+		return recordedTimelineEvent(event)
+	}
+	return newTimelineEvent(event, eventType)
+}
+
+// Returns the API requests made so far
+func (c *GiteaClient) Usage() APIUsage {
+	return c.rest.tracker.usage()
+}
+
+// Limits the API requests made from now on
+func (c *GiteaClient) SetRequestBudget(budget RequestBudget) {
+	c.rest.tracker.setBudget(budget)
+}
+
+// Caches responses on disk, revalidating them with ETags once they are stale
+func (c *GiteaClient) EnableResponseCache(options CacheOptions) error {
+	return c.rest.tracker.enableCache(options)
+}
+
+// Routes requests through a proxy and applies custom TLS settings
+func (c *GiteaClient) ConfigureTransport(options TransportOptions) error {
+	return c.rest.tracker.configureTransport(options)
+}
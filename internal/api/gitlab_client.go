@@ -0,0 +1,694 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Reads GitLab merge requests into go-github types, which gitLabConverter converts to the model
+type GitLabClient struct {
+	rest     *restClient
+	logger   *utils.Logger
+	notesKey string       // MR whose notes are cached
+	notes    []gitLabNote // Notes of the MR last fetched
+}
+
+// Configures GitLab API client with private token authentication
+func NewGitLabClient(apiURL, token string, logger *utils.Logger) (*GitLabClient, error) {
+	if _, err := url.Parse(apiURL); err != nil {
+		return nil, err
+	}
+	logger.Debug("Using GitLab API URL: %s", apiURL)
+
+	return &GitLabClient{
+		rest:   newRESTClient(apiURL, map[string]string{"PRIVATE-TOKEN": token}, logger),
+		logger: logger,
+	}, nil
+}
+
+type gitLabUser struct {
+	Username string `json:"username"`
+}
+
+type gitLabMergeRequest struct {
+	IID          int         `json:"iid"`
+	Title        string      `json:"title"`
+	State        string      `json:"state"`
+	Author       gitLabUser  `json:"author"`
+	MergedBy     *gitLabUser `json:"merged_by"`
+	MergeUser    *gitLabUser `json:"merge_user"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	MergedAt     *time.Time  `json:"merged_at"`
+	ClosedAt     *time.Time  `json:"closed_at"`
+	SourceBranch string      `json:"source_branch"`
+	TargetBranch string      `json:"target_branch"`
+	SHA          string      `json:"sha"`
+	Milestone    *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Labels    []string     `json:"labels"`
+	Assignees []gitLabUser `json:"assignees"`
+	Reviewers []gitLabUser `json:"reviewers"`
+}
+
+type gitLabCommit struct {
+	ID            string    `json:"id"`
+	Message       string    `json:"message"`
+	AuthorName    string    `json:"author_name"`
+	AuthorEmail   string    `json:"author_email"`
+	AuthoredDate  time.Time `json:"authored_date"`
+	CommitterName string    `json:"committer_name"`
+	CommittedDate time.Time `json:"committed_date"`
+	ParentIDs     []string  `json:"parent_ids"`
+}
+
+type gitLabNote struct {
+	ID        int64      `json:"id"`
+	Type      string     `json:"type"`
+	Body      string     `json:"body"`
+	Author    gitLabUser `json:"author"`
+	CreatedAt time.Time  `json:"created_at"`
+	System    bool       `json:"system"`
+	Position  *struct {
+		NewPath string `json:"new_path"`
+		OldPath string `json:"old_path"`
+	} `json:"position"`
+}
+
+type gitLabDiscussion struct {
+	ID    string `json:"id"`
+	Notes []struct {
+		Type       string     `json:"type"`
+		Author     gitLabUser `json:"author"`
+		CreatedAt  time.Time  `json:"created_at"`
+		System     bool       `json:"system"`
+		Resolvable bool       `json:"resolvable"`
+		Resolved   bool       `json:"resolved"`
+		Position   *struct {
+			NewPath string `json:"new_path"`
+		} `json:"position"`
+	} `json:"notes"`
+}
+
+type gitLabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+type gitLabCommitStatus struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Builds the project path segment used by GitLab for namespaced projects
+func gitLabProjectPath(owner, repo string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+// Converts the parent IDs of a GitLab commit, whose count tells merge commits apart
+func gitLabParents(ids []string) []*github.Commit {
+	var parents []*github.Commit
+	for _, id := range ids {
+		parents = append(parents, &github.Commit{SHA: github.Ptr(id)})
+	}
+	return parents
+}
+
+// Follows GitLab's X-Next-Page pagination, passing each page to handle
+func (c *GitLabClient) paginate(path string, query url.Values, newPage func() any, handle func(page any)) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", "100")
+	query.Set("page", "1")
+
+	for {
+		page := newPage()
+		resp, err := c.rest.getJSON(path, query, page)
+		if err != nil {
+			return err
+		}
+		handle(page)
+
+		next := resp.Header.Get("X-Next-Page")
+		if next == "" {
+			return nil
+		}
+		query.Set("page", next)
+	}
+}
+
+// Fetches all merge requests matching the query using paginated API calls
+func (c *GitLabClient) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	c.logger.Debug("Fetching merge requests for %s/%s from %s to %s", owner, repo, query.StartDate.Format("2006-01-02"), query.EndDate.Format("2006-01-02"))
+
+	// GitLab can only filter by creation or update time, so merged/closed ranges are
+	// narrowed to merge requests updated since the start and then filtered locally
+	params := url.Values{}
+	params.Set("state", "all")
+	if query.DateField == DateFieldMerged || query.DateField == DateFieldClosed {
+		params.Set("updated_after", query.StartDate.Format(time.RFC3339))
+	} else {
+		params.Set("created_after", query.StartDate.Format(time.RFC3339))
+		params.Set("created_before", query.EndDate.Format(time.RFC3339))
+	}
+
+	var allPRs []*github.PullRequest
+	seen := make(map[int]bool)
+	collect := func(page any) {
+		for _, mr := range *page.(*[]gitLabMergeRequest) {
+			pr := mr.toPullRequest()
+			if !seen[mr.IID] && query.Matches(pr) {
+				seen[mr.IID] = true
+				allPRs = append(allPRs, pr)
+			}
+		}
+		c.logger.Debug("Fetched page of merge requests (%d total so far)", len(allPRs))
+	}
+
+	newPage := func() any { return &[]gitLabMergeRequest{} }
+	if err := c.paginate(gitLabProjectPath(owner, repo)+"/merge_requests", params, newPage, collect); err != nil {
+		return nil, err
+	}
+
+	// GitLab combines filters with AND, so updated merge requests need a second listing
+	if !query.UpdatedSince.IsZero() {
+		params = url.Values{}
+		params.Set("state", "all")
+		params.Set("updated_after", query.UpdatedSince.Format(time.RFC3339))
+		if err := c.paginate(gitLabProjectPath(owner, repo)+"/merge_requests", params, newPage, collect); err != nil {
+			return nil, err
+		}
+	}
+
+	c.logger.Debug("Fetched %d merge requests in total", len(allPRs))
+	return allPRs, nil
+}
+
+// Fetches a merge request along with additions, deletions, and changed files computed from its diffs
+func (c *GitLabClient) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	c.logger.Debug("Fetching details for MR !%d", number)
+
+	var mr gitLabMergeRequest
+	if _, err := c.rest.getJSON(fmt.Sprintf("%s/merge_requests/%d", gitLabProjectPath(owner, repo), number), nil, &mr); err != nil {
+		return nil, err
+	}
+	pr := mr.toPullRequest()
+
+	files, err := c.GetPRFiles(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	additions, deletions := 0, 0
+	for _, file := range files {
+		additions += file.GetAdditions()
+		deletions += file.GetDeletions()
+	}
+	pr.Additions = github.Ptr(additions)
+	pr.Deletions = github.Ptr(deletions)
+	pr.ChangedFiles = github.Ptr(len(files))
+
+	return pr, nil
+}
+
+// Fetches all commits of a merge request, oldest first to match GitHub ordering
+func (c *GitLabClient) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	c.logger.Debug("Fetching commits for MR !%d", number)
+
+	var allCommits []*github.RepositoryCommit
+	err := c.paginate(fmt.Sprintf("%s/merge_requests/%d/commits", gitLabProjectPath(owner, repo), number), nil,
+		func() any { return &[]gitLabCommit{} },
+		func(page any) {
+			for _, commit := range *page.(*[]gitLabCommit) {
+				allCommits = append(allCommits, &github.RepositoryCommit{
+					SHA: github.Ptr(commit.ID),
+					Commit: &github.Commit{
+						Message: github.Ptr(commit.Message),
+						Author: &github.CommitAuthor{
+							Name:  github.Ptr(commit.AuthorName),
+							Email: github.Ptr(commit.AuthorEmail),
+							Date:  &github.Timestamp{Time: commit.AuthoredDate},
+						},
+						Committer: &github.CommitAuthor{
+							Name: github.Ptr(commit.CommitterName),
+							Date: &github.Timestamp{Time: commit.CommittedDate},
+						},
+					},
+					Parents: gitLabParents(commit.ParentIDs),
+				})
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// GitLab lists merge request commits newest first
+	slices.Reverse(allCommits)
+
+	c.logger.Debug("Fetched %d commits for MR !%d", len(allCommits), number)
+	return allCommits, nil
+}
+
+// Fetches all notes of a merge request in creation order. Comments, conversation comments,
+// reviews, and timeline events are all derived from the notes, so those of the MR last fetched
+// are kept and each MR's notes are downloaded once while its metrics are calculated.
+func (c *GitLabClient) getNotes(owner, repo string, number int) ([]gitLabNote, error) {
+	key := fmt.Sprintf("%s/%s!%d", owner, repo, number)
+	if key == c.notesKey {
+		return c.notes, nil
+	}
+
+	query := url.Values{}
+	query.Set("sort", "asc")
+	query.Set("order_by", "created_at")
+
+	var allNotes []gitLabNote
+	err := c.paginate(fmt.Sprintf("%s/merge_requests/%d/notes", gitLabProjectPath(owner, repo), number), query,
+		func() any { return &[]gitLabNote{} },
+		func(page any) {
+			allNotes = append(allNotes, *page.(*[]gitLabNote)...)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	c.notesKey, c.notes = key, allNotes
+	return allNotes, nil
+}
+
+// Fetches inline diff notes, which correspond to GitHub review comments
+func (c *GitLabClient) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	c.logger.Debug("Fetching comments for MR !%d", number)
+
+	notes, err := c.getNotes(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allComments []*github.PullRequestComment
+	for _, note := range notes {
+		if note.System || note.Type != "DiffNote" {
+			continue
+		}
+
+		comment := &github.PullRequestComment{
+			ID:        github.Ptr(note.ID),
+			Body:      github.Ptr(note.Body),
+			User:      &github.User{Login: github.Ptr(note.Author.Username)},
+			CreatedAt: &github.Timestamp{Time: note.CreatedAt},
+		}
+		if note.Position != nil {
+			comment.Path = github.Ptr(note.Position.NewPath)
+		}
+		allComments = append(allComments, comment)
+	}
+
+	c.logger.Debug("Fetched %d comments for MR !%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches the MR notes that are not attached to the diff, skipping system notes
+func (c *GitLabClient) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	c.logger.Debug("Fetching conversation comments for MR !%d", number)
+
+	notes, err := c.getNotes(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allComments []*github.IssueComment
+	for _, note := range notes {
+		if note.System || note.Type == "DiffNote" {
+			continue
+		}
+
+		allComments = append(allComments, &github.IssueComment{
+			ID:        github.Ptr(note.ID),
+			Body:      github.Ptr(note.Body),
+			User:      &github.User{Login: github.Ptr(note.Author.Username)},
+			CreatedAt: &github.Timestamp{Time: note.CreatedAt},
+		})
+	}
+
+	c.logger.Debug("Fetched %d conversation comments for MR !%d", len(allComments), number)
+	return allComments, nil
+}
+
+// Fetches the resolvable discussions of an MR, which correspond to GitHub review threads
+func (c *GitLabClient) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	c.logger.Debug("Fetching discussions for MR !%d", number)
+
+	var allThreads []*ReviewThread
+	err := c.paginate(fmt.Sprintf("%s/merge_requests/%d/discussions", gitLabProjectPath(owner, repo), number), nil,
+		func() any { return &[]gitLabDiscussion{} },
+		func(page any) {
+			for _, discussion := range *page.(*[]gitLabDiscussion) {
+				if len(discussion.Notes) == 0 || discussion.Notes[0].System || !discussion.Notes[0].Resolvable {
+					continue
+				}
+				first := discussion.Notes[0]
+				thread := &ReviewThread{
+					ID:        discussion.ID,
+					Author:    first.Author.Username,
+					CreatedAt: first.CreatedAt,
+					Resolved:  first.Resolved,
+				}
+				if first.Position != nil {
+					thread.Path = first.Position.NewPath
+				}
+				allThreads = append(allThreads, thread)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d discussions for MR !%d", len(allThreads), number)
+	return allThreads, nil
+}
+
+// Derives closed and reopened events from the system notes of an MR
+func (c *GitLabClient) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	c.logger.Debug("Fetching timeline events for MR !%d", number)
+
+	notes, err := c.getNotes(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allEvents []*github.IssueEvent
+	for _, note := range notes {
+		if !note.System || (note.Body != "closed" && note.Body != "reopened") {
+			continue
+		}
+
+		allEvents = append(allEvents, &github.IssueEvent{
+			ID:        github.Ptr(note.ID),
+			Actor:     &github.User{Login: github.Ptr(note.Author.Username)},
+			Event:     github.Ptr(note.Body),
+			CreatedAt: &github.Timestamp{Time: note.CreatedAt},
+		})
+	}
+
+	c.logger.Debug("Fetched %d timeline events for MR !%d", len(allEvents), number)
+	return allEvents, nil
+}
+
+// Fetches the "approved this merge request" system notes, which the GitLab converter reads as
+// approvals
+func (c *GitLabClient) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	c.logger.Debug("Fetching approvals for MR !%d", number)
+
+	notes, err := c.getNotes(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var allReviews []*github.PullRequestReview
+	for _, note := range notes {
+		if !note.System || !strings.HasPrefix(note.Body, "approved this merge request") {
+			continue
+		}
+
+		allReviews = append(allReviews, &github.PullRequestReview{
+			ID:          github.Ptr(note.ID),
+			User:        &github.User{Login: github.Ptr(note.Author.Username)},
+			Body:        github.Ptr(note.Body),
+			SubmittedAt: &github.Timestamp{Time: note.CreatedAt},
+		})
+	}
+
+	c.logger.Debug("Fetched %d approvals for MR !%d", len(allReviews), number)
+	return allReviews, nil
+}
+
+// Fetches the changed files of a merge request with line counts parsed from the diffs
+func (c *GitLabClient) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	c.logger.Debug("Fetching files for MR !%d", number)
+
+	var allFiles []*github.CommitFile
+	err := c.paginate(fmt.Sprintf("%s/merge_requests/%d/diffs", gitLabProjectPath(owner, repo), number), nil,
+		func() any { return &[]gitLabDiff{} },
+		func(page any) {
+			for _, diff := range *page.(*[]gitLabDiff) {
+				additions, deletions := countDiffLines(diff.Diff)
+
+				status := "modified"
+				switch {
+				case diff.NewFile:
+					status = "added"
+				case diff.DeletedFile:
+					status = "removed"
+				case diff.RenamedFile:
+					status = "renamed"
+				}
+
+				allFiles = append(allFiles, &github.CommitFile{
+					Filename:         github.Ptr(diff.NewPath),
+					PreviousFilename: github.Ptr(diff.OldPath),
+					Status:           github.Ptr(status),
+					Additions:        github.Ptr(additions),
+					Deletions:        github.Ptr(deletions),
+					Changes:          github.Ptr(additions + deletions),
+				})
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Fetched %d files for MR !%d", len(allFiles), number)
+	return allFiles, nil
+}
+
+// Fetches the project, taking the language with the largest share as the primary one
+func (c *GitLabClient) GetRepository(owner, repo string) (*github.Repository, error) {
+	c.logger.Debug("Fetching project %s/%s", owner, repo)
+
+	var project struct {
+		PathWithNamespace string   `json:"path_with_namespace"`
+		DefaultBranch     string   `json:"default_branch"`
+		Visibility        string   `json:"visibility"`
+		Topics            []string `json:"topics"`
+	}
+	if _, err := c.rest.getJSON(gitLabProjectPath(owner, repo), nil, &project); err != nil {
+		return nil, err
+	}
+
+	var languages map[string]float64
+	if _, err := c.rest.getJSON(gitLabProjectPath(owner, repo)+"/languages", nil, &languages); err != nil {
+		return nil, err
+	}
+	primaryLanguage, largestShare := "", 0.0
+	for language, share := range languages {
+		if share > largestShare || (share == largestShare && language < primaryLanguage) {
+			primaryLanguage, largestShare = language, share
+		}
+	}
+
+	return &github.Repository{
+		FullName:      github.Ptr(project.PathWithNamespace),
+		Language:      github.Ptr(primaryLanguage),
+		Topics:        project.Topics,
+		Visibility:    github.Ptr(project.Visibility),
+		DefaultBranch: github.Ptr(project.DefaultBranch),
+	}, nil
+}
+
+// GitLab approval rules do not map onto GitHub branch protection, so compliance is reported as unknown
+func (c *GitLabClient) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	return nil, fmt.Errorf("branch protection is not supported by the %s provider", ProviderGitLab)
+}
+
+// Fetches the CODEOWNERS file of a ref, returning an empty string when the project has none
+func (c *GitLabClient) GetCodeOwners(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching CODEOWNERS for %s", ref)
+
+	query := url.Values{}
+	query.Set("ref", ref)
+
+	// GitLab uses the first file found in these locations
+	for _, path := range []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"} {
+		var file struct {
+			Content string `json:"content"`
+		}
+		_, err := c.rest.getJSON(fmt.Sprintf("%s/repository/files/%s", gitLabProjectPath(owner, repo), url.PathEscape(path)), query, &file)
+		if apiErr, ok := err.(*utils.APIError); ok && apiErr.StatusCode == 404 {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return decodeBase64Content(file.Content)
+	}
+
+	return "", nil
+}
+
+// Fetches the root .gitattributes file of a ref, returning an empty string when the project has none
+func (c *GitLabClient) GetGitAttributes(owner, repo, ref string) (string, error) {
+	c.logger.Debug("Fetching .gitattributes for %s", ref)
+
+	query := url.Values{}
+	query.Set("ref", ref)
+
+	var file struct {
+		Content string `json:"content"`
+	}
+	_, err := c.rest.getJSON(fmt.Sprintf("%s/repository/files/%s", gitLabProjectPath(owner, repo), url.PathEscape(".gitattributes")), query, &file)
+	if apiErr, ok := err.(*utils.APIError); ok && apiErr.StatusCode == 404 {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return decodeBase64Content(file.Content)
+}
+
+// Fetches pipeline job statuses for a commit
+func (c *GitLabClient) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	c.logger.Debug("Fetching commit statuses for %s", ref)
+
+	var allStatuses []*github.RepoStatus
+	err := c.paginate(fmt.Sprintf("%s/repository/commits/%s/statuses", gitLabProjectPath(owner, repo), url.PathEscape(ref)), nil,
+		func() any { return &[]gitLabCommitStatus{} },
+		func(page any) {
+			for _, status := range *page.(*[]gitLabCommitStatus) {
+				state := status.Status
+				if state == "canceled" {
+					state = "failure"
+				}
+				allStatuses = append(allStatuses, &github.RepoStatus{
+					Context:   github.Ptr(status.Name),
+					State:     github.Ptr(state),
+					CreatedAt: &github.Timestamp{Time: status.CreatedAt},
+				})
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return allStatuses, nil
+}
+
+// GitLab has no check runs; pipeline results are reported as commit statuses instead
+func (c *GitLabClient) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	return nil, nil
+}
+
+// Converts a GitLab merge request into a GitHub pull request
+func (mr gitLabMergeRequest) toPullRequest() *github.PullRequest {
+	state := "closed"
+	if mr.State == "opened" {
+		state = "open"
+	}
+
+	pr := &github.PullRequest{
+		Number:    github.Ptr(mr.IID),
+		Title:     github.Ptr(mr.Title),
+		State:     github.Ptr(state),
+		User:      &github.User{Login: github.Ptr(mr.Author.Username)},
+		CreatedAt: &github.Timestamp{Time: mr.CreatedAt},
+		UpdatedAt: &github.Timestamp{Time: mr.UpdatedAt},
+		Base:      &github.PullRequestBranch{Ref: github.Ptr(mr.TargetBranch)},
+		Head:      &github.PullRequestBranch{Ref: github.Ptr(mr.SourceBranch), SHA: github.Ptr(mr.SHA)},
+	}
+
+	if mr.MergedAt != nil {
+		pr.MergedAt = &github.Timestamp{Time: *mr.MergedAt}
+	}
+	if mr.ClosedAt != nil {
+		pr.ClosedAt = &github.Timestamp{Time: *mr.ClosedAt}
+	}
+	if mr.MergeUser != nil {
+		pr.MergedBy = &github.User{Login: github.Ptr(mr.MergeUser.Username)}
+	} else if mr.MergedBy != nil {
+		pr.MergedBy = &github.User{Login: github.Ptr(mr.MergedBy.Username)}
+	}
+	if mr.Milestone != nil {
+		pr.Milestone = &github.Milestone{Title: github.Ptr(mr.Milestone.Title)}
+	}
+	for _, label := range mr.Labels {
+		pr.Labels = append(pr.Labels, &github.Label{Name: github.Ptr(label)})
+	}
+	for _, assignee := range mr.Assignees {
+		pr.Assignees = append(pr.Assignees, &github.User{Login: github.Ptr(assignee.Username)})
+	}
+	// Unlike on GitHub, reviewers stay listed after they review
+	for _, reviewer := range mr.Reviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, &github.User{Login: github.Ptr(reviewer.Username)})
+	}
+
+	return pr
+}
+
+// Converts GitLab responses, whose reviews are approval notes and whose reviewers are only ever
+// people, to the provider-neutral model
+type gitLabConverter struct {
+	GitHubConverter
+}
+
+// Converts a merge request, which GitLab cannot ask a team to review
+func (gitLabConverter) PullRequest(pr *github.PullRequest) *model.PullRequest {
+	return newPullRequest(pr)
+}
+
+// Converts an approval note into an approving review, since GitLab has no other verdicts
+func (gitLabConverter) ReviewEvent(review *github.PullRequestReview) *model.ReviewEvent {
+	return newReviewEvent(review, model.ReviewStateApproved)
+}
+
+// Counts added and removed lines in a unified diff body. GitLab starts the body at the first
+// hunk, so file headers are only skipped before it and lines such as "++i" still count.
+func countDiffLines(diff string) (int, int) {
+	additions, deletions := 0, 0
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+			continue
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// Returns the API requests made so far
+func (c *GitLabClient) Usage() APIUsage {
+	return c.rest.tracker.usage()
+}
+
+// Limits the API requests made from now on
+func (c *GitLabClient) SetRequestBudget(budget RequestBudget) {
+	c.rest.tracker.setBudget(budget)
+}
+
+// Caches responses on disk, revalidating them with ETags once they are stale
+func (c *GitLabClient) EnableResponseCache(options CacheOptions) error {
+	return c.rest.tracker.enableCache(options)
+}
+
+// Routes requests through a proxy and applies custom TLS settings
+func (c *GitLabClient) ConfigureTransport(options TransportOptions) error {
+	return c.rest.tracker.configureTransport(options)
+}
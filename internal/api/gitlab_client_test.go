@@ -0,0 +1,51 @@
+package api
+
+import "testing"
+
+func TestCountDiffLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		diff      string
+		additions int
+		deletions int
+	}{
+		{
+			name:      "empty",
+			diff:      "",
+			additions: 0,
+			deletions: 0,
+		},
+		{
+			name:      "single hunk",
+			diff:      "@@ -1,2 +1,2 @@\n context\n-old\n+new\n",
+			additions: 1,
+			deletions: 1,
+		},
+		{
+			name:      "lines starting with the header prefixes",
+			diff:      "@@ -1,3 +1,3 @@\n for {\n+++i\n--- comment\n----\n }\n",
+			additions: 1,
+			deletions: 2,
+		},
+		{
+			name:      "file headers before the first hunk",
+			diff:      "--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-old\n+new\n",
+			additions: 1,
+			deletions: 1,
+		},
+		{
+			name:      "several hunks",
+			diff:      "@@ -1 +1,2 @@\n+a\n+b\n@@ -10,2 +11 @@\n-c\n-d\n",
+			additions: 2,
+			deletions: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			additions, deletions := countDiffLines(tt.diff)
+			if additions != tt.additions || deletions != tt.deletions {
+				t.Errorf("countDiffLines() = %d, %d; want %d, %d", additions, deletions, tt.additions, tt.deletions)
+			}
+		})
+	}
+}
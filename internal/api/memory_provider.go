@@ -0,0 +1,208 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v74/github"
+)
+
+// Everything the metrics of one PR are derived from, for callers that already mirror GitHub data,
+// for example from webhooks or a data lake. Nil slices count as no items.
+type PRData struct {
+	PullRequest    *github.PullRequest // As returned by the PR detail endpoint, with additions, deletions, and merged_by
+	Commits        []*github.RepositoryCommit
+	Comments       []*github.PullRequestComment // Inline comments on the diff
+	IssueComments  []*github.IssueComment       // General comments on the PR
+	Reviews        []*github.PullRequestReview
+	Files          []*github.CommitFile
+	ReviewThreads  []*ReviewThread
+	TimelineEvents []*github.IssueEvent
+	Statuses       []*github.RepoStatus // Commit statuses of the head commit
+	CheckRuns      []*github.CheckRun   // Check runs of the head commit
+}
+
+// Repository-wide data served alongside PRData, keyed by branch. Branches without an entry in
+// BranchProtections have their compliance reported as unknown.
+type RepositoryData struct {
+	Repository        *github.Repository
+	BranchProtections map[string]*github.Protection // A nil protection means the branch is not protected
+	CodeOwners        map[string]string             // Contents of the CODEOWNERS file
+	GitAttributes     map[string]string             // Contents of the .gitattributes file
+}
+
+// Serves PR data the caller already fetched, so metrics can be computed without an API client
+type MemoryProvider struct {
+	prs        []*PRData
+	byNumber   map[int]*PRData
+	byHeadSHA  map[string]*PRData
+	repository RepositoryData
+}
+
+// Initializes memory provider serving the given PRs and repository data
+func NewMemoryProvider(prs []*PRData, repository RepositoryData) *MemoryProvider {
+	p := &MemoryProvider{
+		prs:        prs,
+		byNumber:   make(map[int]*PRData, len(prs)),
+		byHeadSHA:  make(map[string]*PRData, len(prs)),
+		repository: repository,
+	}
+	for _, pr := range prs {
+		p.byNumber[pr.PullRequest.GetNumber()] = pr
+		if sha := pr.PullRequest.GetHead().GetSHA(); sha != "" {
+			p.byHeadSHA[sha] = pr
+		}
+	}
+	return p
+}
+
+// Returns the data of a PR, or an error if the caller did not provide it
+func (p *MemoryProvider) pr(number int) (*PRData, error) {
+	data, exists := p.byNumber[number]
+	if !exists {
+		return nil, fmt.Errorf("no data provided for PR #%d", number)
+	}
+	return data, nil
+}
+
+// Returns the provided PRs that match the query, in the order they were given
+func (p *MemoryProvider) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	var prs []*github.PullRequest
+	for _, data := range p.prs {
+		if query.Matches(data.PullRequest) {
+			prs = append(prs, data.PullRequest)
+		}
+	}
+	return prs, nil
+}
+
+// Returns the provided PR
+func (p *MemoryProvider) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.PullRequest, nil
+}
+
+// Returns the provided PR commits
+func (p *MemoryProvider) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.Commits, nil
+}
+
+// Returns the provided PR review comments
+func (p *MemoryProvider) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.Comments, nil
+}
+
+// Returns the provided PR conversation comments
+func (p *MemoryProvider) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.IssueComments, nil
+}
+
+// Returns the provided PR reviews
+func (p *MemoryProvider) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.Reviews, nil
+}
+
+// Returns the provided PR files
+func (p *MemoryProvider) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.Files, nil
+}
+
+// Returns the provided review threads
+func (p *MemoryProvider) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.ReviewThreads, nil
+}
+
+// Returns the provided timeline events
+func (p *MemoryProvider) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	data, err := p.pr(number)
+	if err != nil {
+		return nil, err
+	}
+	return data.TimelineEvents, nil
+}
+
+// Returns the provided repository, or nil if none was given
+func (p *MemoryProvider) GetRepository(owner, repo string) (*github.Repository, error) {
+	return p.repository.Repository, nil
+}
+
+// Returns the provided branch protection, or an error if none was given for the branch
+func (p *MemoryProvider) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	protection, exists := p.repository.BranchProtections[branch]
+	if !exists {
+		return nil, fmt.Errorf("no branch protection provided for %s", branch)
+	}
+	return protection, nil
+}
+
+// Returns the provided CODEOWNERS file, treating a missing one as no code owners
+func (p *MemoryProvider) GetCodeOwners(owner, repo, ref string) (string, error) {
+	return p.repository.CodeOwners[ref], nil
+}
+
+// Returns the provided .gitattributes file, treating a missing one as no attributes
+func (p *MemoryProvider) GetGitAttributes(owner, repo, ref string) (string, error) {
+	return p.repository.GitAttributes[ref], nil
+}
+
+// Returns the provided commit statuses of the PR whose head commit is ref
+func (p *MemoryProvider) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	data, exists := p.byHeadSHA[ref]
+	if !exists {
+		return nil, fmt.Errorf("no commit statuses provided for %s", ref)
+	}
+	return data.Statuses, nil
+}
+
+// Returns the provided check runs of the PR whose head commit is ref
+func (p *MemoryProvider) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	data, exists := p.byHeadSHA[ref]
+	if !exists {
+		return nil, fmt.Errorf("no check runs provided for %s", ref)
+	}
+	return data.CheckRuns, nil
+}
+
+// Reports no API usage since the data was provided by the caller
+func (p *MemoryProvider) Usage() APIUsage {
+	return APIUsage{RateLimitRemaining: -1}
+}
+
+// Ignores the request budget since the data was provided by the caller
+func (p *MemoryProvider) SetRequestBudget(budget RequestBudget) {}
+
+// Ignores the response cache since the data was provided by the caller
+func (p *MemoryProvider) EnableResponseCache(options CacheOptions) error {
+	return nil
+}
+
+// Ignores network settings since the data was provided by the caller
+func (p *MemoryProvider) ConfigureTransport(options TransportOptions) error {
+	return nil
+}
@@ -1,81 +1,504 @@
 package api
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// Contains comprehensive analytics data for a single pull request
+// Contains comprehensive analytics data for a single pull request. Fields tagged csv are the
+// columns of pr_metrics.csv, in field order.
 type PRMetrics struct {
-	Number                     int
-	Title                      string
-	Author                     string
-	Milestone                  string
-	CreatedAt                  time.Time
-	MergedAt                   time.Time
-	State                      string
-	CommitCount                int
-	FirstCommitAt              time.Time
-	LastCommitAt               time.Time
-	FirstCommitToCreateHours   float64
-	CreateToLastCommitHours    float64
-	CommitCountDuringPR        int
-	FirstCommitToMergeHours    float64
-	LastCommitToMergeHours     float64
-	CommentCount               int
-	FirstCommentAt             time.Time
-	CreatedToFirstCommentHours float64
-	ReviewCount                int
-	Additions                  int
-	Deletions                  int
-	ChangedFiles               int
-	ApprovalCount              int
-	TimeToApprovalHours        float64
-	TotalPRLifetimeHours       float64
-	MaxNoCommentPeriodHours    float64
-	MaxNoCommitPeriodHours     float64
-	MaxNoActivityPeriodHours   float64
-}
-
-// Contains statistical summaries of PR metrics over a time period
+	Number                     int              `csv:"PR Number"`
+	Title                      string           `csv:"Title"`
+	Author                     string           `csv:"Author"`
+	Milestone                  string           `csv:"Milestone"`
+	CreatedAt                  time.Time        `csv:"Created At"`
+	MergedAt                   time.Time        `csv:"Merged At"`
+	MergedBy                   string           `csv:"Merged By"`
+	State                      string           `csv:"State"`
+	CommitCount                int              `csv:"Commit Count"`
+	FirstCommitAt              time.Time        `csv:"First Commit At"`
+	LastCommitAt               time.Time        `csv:"Last Commit At"`
+	FirstCommitToCreateHours   float64          `csv:"First Commit to Create (Hours)"`
+	CreateToLastCommitHours    float64          `csv:"Create to Last Commit (Hours)"`
+	CommitCountDuringPR        int              `csv:"Commit Count During PR"`
+	FirstCommitToMergeHours    float64          `csv:"First Commit to Merge (Hours)"`
+	LastCommitToMergeHours     float64          `csv:"Last Commit to Merge (Hours)"`
+	ReviewCommentCount         int              `csv:"Review Comment Count"`       // Inline comments on the diff
+	ConversationCommentCount   int              `csv:"Conversation Comment Count"` // General comments on the PR
+	FirstCommentAt             time.Time        `csv:"First Comment At"`
+	FirstInlineCommentAt       time.Time        `csv:"First Inline Comment At"`
+	CreatedToFirstCommentHours float64          `csv:"Created to First Comment (Hours)"`
+	ReviewCount                int              `csv:"Review Count"`
+	ApprovalCount              int              `csv:"Approval Count"`
+	TimeToApprovalHours        float64          `csv:"Time to Approval (Hours)"`
+	TotalPRLifetimeHours       float64          `csv:"Total PR Lifetime (Hours)"`
+	MaxNoCommentPeriodHours    float64          `csv:"Max No Comment Period (Hours)"`
+	MaxNoCommitPeriodHours     float64          `csv:"Max No Commit Period (Hours)"`
+	MaxNoActivityPeriodHours   float64          `csv:"Max No Activity Period (Hours)"`
+	Additions                  int              `csv:"Additions"`
+	Deletions                  int              `csv:"Deletions"`
+	ChangedFiles               int              `csv:"Changed Files"`
+	ReviewCoveragePercent      float64          `csv:"Review Coverage (%)"` // -1 when the changed files could not be fetched
+	SelfMerged                 bool             `csv:"Self Merged"`
+	UnreviewedMerge            bool             `csv:"Unreviewed Merge"`
+	RequiredApprovals          int              `csv:"Required Approvals"`
+	ReviewRequirementMet       bool             `csv:"Review Requirement Met"`
+	StatusChecksMet            bool             `csv:"Status Checks Met"`
+	ComplianceStatus           string           `csv:"Compliance Status"` // compliant, non-compliant, or unknown, against the current protection rules
+	CommitDateSkew             bool             `csv:"Commit Date Skew"`  // Some commits were dated after the merge
+	AuthorResponseLatencyHours float64          `csv:"Author Response Latency (Hours)"`
+	CodingHours                float64          `csv:"Coding (Hours)"`
+	WaitingForReviewHours      float64          `csv:"Waiting for Review (Hours)"`
+	InReviewHours              float64          `csv:"In Review (Hours)"`
+	WaitingToMergeHours        float64          `csv:"Waiting to Merge (Hours)"`
+	ApproverCount              int              `csv:"Approver Count"`            // Distinct users who approved
+	CodeOwnerApprovalCount     int              `csv:"Code Owner Approval Count"` // Approvers listed in CODEOWNERS for a changed file
+	FirstToLastApprovalHours   float64          `csv:"First to Last Approval (Hours)"`
+	Labels                     []string         `csv:"Labels"`
+	TouchedFunctionCount       int              `csv:"Touched Function Count"` // Functions with changes, from --local-git
+	TestChangeRatio            float64          `csv:"Test Change Ratio"`      // Share of changed lines in test files, from 0 to 1
+	RenamedFileCount           int              `csv:"Renamed File Count"`     // Files renamed, from --local-git
+	ExcludedFileCount          int              `csv:"Excluded File Count"`    // Generated or vendored files left out of Additions, Deletions, and ChangedFiles
+	Languages                  []LanguageChange `csv:"Languages"`
+	ReviewThreadCount          int              `csv:"Review Thread Count"` // Threads opened on the diff
+	ResolvedThreadCount        int              `csv:"Resolved Thread Count"`
+	UnresolvedThreadCount      int              `csv:"Unresolved Thread Count"`   // Threads still open, as of the merge for merged PRs
+	ReReviewLatencyHours       float64          `csv:"Re-Review Latency (Hours)"` // Median hours from commits pushed after a review to the next review activity
+	ReReviewCount              int              `csv:"Re-Review Count"`           // Review iterations: reviews that followed commits pushed after an earlier review
+	Category                   string           `csv:"Category"`                  // Set by the category rules of the config file
+	IssueKeys                  []string         `csv:"Issue Keys"`                // Issue tracker keys found in the title and head branch
+	IssueCreatedAt             time.Time        `csv:"Issue Created At"`
+	IssueLeadTimeHours         float64          `csv:"Issue Lead Time (Hours)"` // Hours from the creation of the earliest referenced issue to the merge
+	DependencyUpdate           bool             `csv:"Dependency Update"`       // Opened by a dependency update bot such as Dependabot or Renovate
+	AutoMerged                 bool             `csv:"Auto Merged"`             // Merged by auto-merge or by a bot
+	ReopenCount                int              `csv:"Reopen Count"`            // Times the PR was reopened after being closed
+	Assignees                  []string         `csv:"Assignees"`
+	RequestedReviewers         []string         `csv:"Requested Reviewers"` // Users asked to review, including those who already did
+	RequestedTeams             []string         `csv:"Requested Teams"`
+	RequestedReviewerCount     int              `csv:"Requested Reviewer Count"`    // Requested users and teams
+	RequestedReviewerReviewed  bool             `csv:"Requested Reviewer Reviewed"` // Some requested user submitted a review
+	StaleApprovalCount         int              `csv:"Stale Approval Count"`        // Approvals followed by new commits
+	BaseSyncMergeCount         int              `csv:"Base Sync Merge Count"`       // Merges of the base branch into the PR branch
+	CommitsAfterApproval       int              `csv:"Commits After Approval"`      // Commits pushed after the final approval and before the merge
+	CodeOwners                 []string         `csv:"Code Owners"`                 // Users listed in CODEOWNERS for a changed file, other than the author
+	BaseBranch                 string           `csv:"Base Branch"`
+	HeadBranch                 string           `csv:"Head Branch"`                      // owner:branch for PRs from forks, which cannot be stacked on
+	ParentPR                   int              `csv:"Parent PR"`                        // PR whose head branch this PR is based on, 0 unless stacked
+	StackDepth                 int              `csv:"Stack Depth"`                      // PRs below this one in its stack
+	BlockedByParentHours       float64          `csv:"Blocked by Parent (Hours)"`        // From creation until the parent merged, if this PR was still open
+	CIQueueHours               float64          `csv:"CI Queue (Hours)"`                 // From the push of the head commit to its first check run starting, from --ci-metrics
+	FlakyCheckRetries          int              `csv:"Flaky Check Retries"`              // Failed check runs on the head commit that a re-run of the same check passed, from --ci-metrics
+	NonOwnerApprovalCount      int              `csv:"Non-Owner Approval Count"`         // Approvers not listed in CODEOWNERS for any changed file
+	OwnedFileCount             int              `csv:"Owned File Count"`                 // Changed files with a user listed in CODEOWNERS
+	CodeOwnerCoveragePercent   float64          `csv:"Code Owner Approval Coverage (%)"` // Share of owned files approved by one of their owners
+	Reviewers                  []ReviewerResponse
+	Files                      []PRFile
+	Events                     []PREvent
+}
+
+// How long a reviewer took to first review a PR
+type ReviewerResponse struct {
+	Reviewer           string
+	FirstReviewAt      time.Time
+	HoursToFirstReview float64 // From PR creation
+}
+
+// A file changed by a PR
+type PRFile struct {
+	Path               string
+	Additions          int
+	Deletions          int
+	ReviewCommentCount int
+}
+
+// A failure that occurred while calculating metrics for a PR
+type PRError struct {
+	PRNumber int
+	Stage    string
+	Message  string
+	Skipped  bool // The PR was left out of the outputs
+}
+
+// Summary of a run for automation
+type RunReport struct {
+	ReposProcessed     int             `json:"repos_processed"`
+	PRsFetched         int             `json:"prs_fetched"`
+	PRsFailed          int             `json:"prs_failed"`
+	APIRequests        int             `json:"api_requests"`
+	RateLimitRemaining *int            `json:"rate_limit_remaining"` // Null when the API reported no rate limit
+	APIEndpoints       []EndpointUsage `json:"api_endpoints"`
+	DurationSeconds    float64         `json:"duration_seconds"`
+	ExitCode           int             `json:"exit_code"`
+	Error              string          `json:"error,omitempty"`
+	Alerts             []Alert         `json:"alerts,omitempty"`   // Alert rules triggered by the run
+	Sampling           *Sampling       `json:"sampling,omitempty"` // Set when --sample or --max-prs narrowed down the PRs
+	Repositories       []Repository    `json:"repositories,omitempty"`
+	UploadLocation     string          `json:"upload_location,omitempty"` // Set when --upload stored the outputs
+}
+
+// Descriptive attributes of a processed repository, for slicing comparisons across repositories
+type Repository struct {
+	FullName        string   `json:"full_name"`
+	PrimaryLanguage string   `json:"primary_language,omitempty"`
+	Topics          []string `json:"topics,omitempty"`
+	Visibility      string   `json:"visibility,omitempty"` // public, private, or internal
+	DefaultBranch   string   `json:"default_branch,omitempty"`
+}
+
+// Methods of narrowing down the PRs of a run
+const (
+	SamplingMethodRandom     = "random"
+	SamplingMethodMostRecent = "most_recent"
+)
+
+// How the fetched PRs were narrowed down to the analyzed ones
+type Sampling struct {
+	Method      string  `json:"method"` // random for --sample, most_recent for --max-prs
+	PRsAnalyzed int     `json:"prs_analyzed"`
+	Fraction    float64 `json:"fraction"`       // Share of the fetched PRs that were analyzed
+	Seed        uint64  `json:"seed,omitempty"` // Draws the same random sample again with --sample-seed
+}
+
+// An alert rule whose threshold was crossed in its most recent periods
+type Alert struct {
+	Rule       string    `json:"rule"`
+	Metric     string    `json:"metric"`
+	Comparison string    `json:"comparison"`
+	Threshold  float64   `json:"threshold"`
+	Periods    []string  `json:"periods"` // Oldest first
+	Values     []float64 `json:"values"`  // Value of the metric in each period
+}
+
+// Describes the alert in one line
+func (a Alert) Message() string {
+	values := make([]string, len(a.Values))
+	for i, value := range a.Values {
+		values[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	message := fmt.Sprintf("%s %s %s in %s (%s)", a.Metric, a.Comparison,
+		strconv.FormatFloat(a.Threshold, 'f', -1, 64), strings.Join(a.Periods, ", "), strings.Join(values, ", "))
+	if a.Rule != a.Metric {
+		message = a.Rule + ": " + message
+	}
+	return message
+}
+
+// Volume and outcome of the dependency update PRs created during one period
+type DependencyUpdateMetrics struct {
+	Granularity            string // week or month
+	Period                 string // YYYY-WW for week, YYYY-MM for month
+	StartDate              time.Time
+	EndDate                time.Time
+	PRCount                int
+	MergedCount            int
+	AutoMergedCount        int
+	ClosedUnmergedCount    int
+	OpenCount              int
+	AutoMergePercent       float64 // Share of merged PRs that were auto-merged
+	FailurePercent         float64 // Share of closed PRs that were closed without merging
+	AvgTimeToMergeHours    float64
+	MedianTimeToMergeHours float64
+}
+
+// Aggregated metrics of the PRs of one category during one period
+type CategoryAggregatedMetrics struct {
+	Granularity string // week or month
+	Category    string
+	Metrics     *AggregatedMetrics
+}
+
+// Lines a PR changed in files of one language
+type LanguageChange struct {
+	Language  string
+	Additions int
+	Deletions int
+}
+
+// Lines merged PRs changed in files of one language during one period
+type LanguageMix struct {
+	Granularity  string // week or month
+	Period       string // YYYY-WW for week, YYYY-MM for month
+	StartDate    time.Time
+	EndDate      time.Time
+	Language     string
+	PRCount      int // Merged PRs changing files of the language
+	Additions    int
+	Deletions    int
+	SharePercent float64 // Share of the period's changed lines
+}
+
+// Inline review comments merged PRs received on one type of file during one period
+type FileTypeComments struct {
+	Granularity         string // week or month
+	Period              string // YYYY-WW for week, YYYY-MM for month
+	StartDate           time.Time
+	EndDate             time.Time
+	FileType            string // source, test, config, docs, or other
+	PRCount             int    // Merged PRs changing files of the type
+	ChangedFiles        int
+	ChangedLines        int // Additions plus deletions
+	ReviewCommentCount  int
+	SharePercent        float64 // Share of the period's review comments
+	CommentsPer100Lines float64
+}
+
+// People who reviewed changes to one component during one period
+type BusFactor struct {
+	Granularity    string // week or month
+	Period         string // YYYY-WW for week, YYYY-MM for month
+	StartDate      time.Time
+	EndDate        time.Time
+	Component      string   // Path prefix, or "." for files in the repository root
+	PRCount        int      // Merged PRs changing files of the component
+	ReviewerCount  int      // The review bus factor: distinct people who reviewed those PRs
+	Reviewers      []string // Sorted logins
+	SingleReviewer bool     // Only one person reviewed the component, so it has no backup
+}
+
+// How well review requests of merged PRs reached the people who reviewed during one period.
+// Requests and reviewers are counted once per PR.
+type ReviewRouting struct {
+	Granularity              string // week or month
+	Period                   string // YYYY-WW for week, YYYY-MM for month
+	StartDate                time.Time
+	EndDate                  time.Time
+	PRCount                  int     // Merged PRs with requested users or reviewers
+	RequestCount             int     // Users requested to review
+	RequestReviewedPercent   float64 // Share of requested users who reviewed
+	ReviewerCount            int     // Users who reviewed
+	OutsiderReviewPercent    float64 // Share of reviewers who were not requested
+	CodeOwnerRequestPercent  float64 // Share of requests on PRs with code owners that went to one
+	CodeOwnerReviewerPercent float64 // Share of reviewers on PRs with code owners who were one
+}
+
+// What became of the PRs opened during one ISO week
+type Cohort struct {
+	Period           string // YYYY-WW
+	StartDate        time.Time
+	EndDate          time.Time
+	OpenedCount      int
+	MergedCount      []int // Merged within each of metrics.CohortMergeDays of creation, cumulatively
+	MergedLaterCount int   // Merged after the last of metrics.CohortMergeDays
+	ClosedCount      int   // Closed without merging
+	OpenCount        int
+}
+
+// How often one author opened PRs during the run
+type AuthorCadence struct {
+	Author               string
+	PRCount              int // PRs the author created
+	FirstCreatedAt       time.Time
+	LastCreatedAt        time.Time
+	MedianDaysBetweenPRs float64 // Median days between consecutive creations, zero with a single PR
+}
+
+// How often the team's authors opened PRs during one period
+type TeamCadence struct {
+	Granularity          string // week or month
+	Period               string // YYYY-WW for week, YYYY-MM for month
+	StartDate            time.Time
+	EndDate              time.Time
+	PRCount              int // PRs created in the period
+	AuthorCount          int // Distinct authors of those PRs
+	GapCount             int // PRs created in the period that followed an earlier PR of the same author
+	MedianDaysBetweenPRs float64
+}
+
+// When one kind of PR activity happened during one period
+type Heatmap struct {
+	Granularity string // week or month
+	Period      string // YYYY-WW for week, YYYY-MM for month
+	StartDate   time.Time
+	EndDate     time.Time
+	Activity    string     // created, review, or merge
+	Counts      [7][24]int // By day of week from Monday, then by hour of day
+}
+
+// How long PRs waited for their first review by when they were opened
+type CreationTimeReviewLatency struct {
+	MedianHours [8][25]float64 // By day of week from Monday, then by hour of day; the last row and column cover all days and hours
+	PRCounts    [8][25]int     // Reviewed PRs behind each median
+}
+
+// Share of PRs with a label that met a service level objective during one period
+type SLOAttainment struct {
+	Granularity       string // week or month
+	Period            string // YYYY-WW for week, YYYY-MM for month
+	StartDate         time.Time
+	EndDate           time.Time
+	SLO               string // Such as "bug: merge within 48h"
+	Label             string
+	Target            string
+	WithinHours       float64
+	PRCount           int
+	MetCount          int
+	MissedCount       int
+	AttainmentPercent float64
+}
+
+// An impossible metric value detected by the data quality check
+type DataQualityIssue struct {
+	PRNumber int
+	Field    string
+	Value    float64
+	Problem  string
+	Action   string // Policy applied: report, clamp, or exclude
+}
+
+// Event types in the normalized PR event stream
+const (
+	EventTypeCreated  = "created"
+	EventTypeCommit   = "commit"
+	EventTypeComment  = "comment"
+	EventTypeReview   = "review"
+	EventTypeApproval = "approval"
+	EventTypeMerge    = "merge"
+	EventTypeClose    = "close"
+	EventTypeReopen   = "reopen"
+)
+
+// Discussion thread on the diff of a PR, which reviewers can mark resolved, as providers return
+// it and recordings store it
+type ReviewThread struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path,omitempty"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	Resolved  bool      `json:"resolved"`
+}
+
+// A single timestamped activity on a pull request
+type PREvent struct {
+	PRNumber  int       `json:"pr_number"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Detail    string    `json:"detail,omitempty"`
+	CodeOwner bool      `json:"code_owner,omitempty"` // Set on approvals by a CODEOWNERS owner of a changed file
+}
+
+// Working agreements and the share of merged PRs that kept each one
+type AgreementScorecard struct {
+	Agreements []string         `json:"agreements"`
+	PRCount    int              `json:"pr_count"`             // Merged PRs scored
+	Overall    []float64        `json:"overall_pass_percent"` // One per agreement, over all merged PRs
+	Weeks      []AgreementScore `json:"weeks"`
+	Months     []AgreementScore `json:"months"`
+}
+
+// Share of the PRs merged during one period that kept each working agreement
+type AgreementScore struct {
+	Period      string    `json:"period"`
+	StartDate   time.Time `json:"start_date"`
+	PRCount     int       `json:"pr_count"`
+	PassPercent []float64 `json:"pass_percent"` // One per agreement, in the order of the scorecard
+}
+
+// Contains statistical summaries of PR metrics over a time period. Fields tagged csv are the
+// columns of the weekly and monthly CSVs, in field order.
 type AggregatedMetrics struct {
-	Period                           string // YYYY-WW for week, YYYY-MM for month
-	StartDate                        time.Time
-	EndDate                          time.Time
-	PRCount                          int
-	AvgCommitCount                   float64
-	AvgCommentCount                  float64
-	AvgReviewCount                   float64
-	AvgApprovalCount                 float64
-	AvgAdditions                     float64
-	AvgDeletions                     float64
-	AvgChangedFiles                  float64
-	AvgFirstCommitToCreateHours      float64
-	AvgCreateToLastCommitHours       float64
-	AvgCommitCountDuringPR           float64
-	AvgFirstCommitToMergeHours       float64
-	AvgLastCommitToMergeHours        float64
-	AvgCreatedToFirstCommentHours    float64
-	AvgTimeToApprovalHours           float64
-	AvgTotalPRLifetimeHours          float64
-	AvgMaxNoCommentPeriodHours       float64
-	AvgMaxNoCommitPeriodHours        float64
-	AvgMaxNoActivityPeriodHours      float64
-	MedianCommitCount                float64
-	MedianCommentCount               float64
-	MedianReviewCount                float64
-	MedianApprovalCount              float64
-	MedianAdditions                  float64
-	MedianDeletions                  float64
-	MedianChangedFiles               float64
-	MedianFirstCommitToCreateHours   float64
-	MedianCreateToLastCommitHours    float64
-	MedianCommitCountDuringPR        float64
-	MedianFirstCommitToMergeHours    float64
-	MedianLastCommitToMergeHours     float64
-	MedianCreatedToFirstCommentHours float64
-	MedianTimeToApprovalHours        float64
-	MedianTotalPRLifetimeHours       float64
-	MedianMaxNoCommentPeriodHours    float64
-	MedianMaxNoCommitPeriodHours     float64
-	MedianMaxNoActivityPeriodHours   float64
+	Period                           string    `csv:"Period"` // YYYY-WW for week, YYYY-MM for month
+	StartDate                        time.Time `csv:"Start Date"`
+	EndDate                          time.Time `csv:"End Date"`
+	PRCount                          int       `csv:"PR Count"`
+	SelfMergedCount                  int       `csv:"Self Merged Count"`
+	SelfMergedPercent                float64   `csv:"Self Merged (%)"`
+	UnreviewedMergeCount             int       `csv:"Unreviewed Merge Count"`
+	UnreviewedMergePercent           float64   `csv:"Unreviewed Merge (%)"`
+	CompliantCount                   int       `csv:"Compliant Count"`
+	NonCompliantCount                int       `csv:"Non-Compliant Count"`
+	CompliantPercent                 float64   `csv:"Compliant (%)"` // Share of PRs with a known compliance status
+	AvgCommitCount                   float64   `csv:"Avg Commit Count"`
+	MedianCommitCount                float64   `csv:"Median Commit Count"`
+	AvgReviewCommentCount            float64   `csv:"Avg Review Comment Count"`
+	MedianReviewCommentCount         float64   `csv:"Median Review Comment Count"`
+	AvgConversationCommentCount      float64   `csv:"Avg Conversation Comment Count"`
+	MedianConversationCommentCount   float64   `csv:"Median Conversation Comment Count"`
+	AvgReviewCount                   float64   `csv:"Avg Review Count"`
+	MedianReviewCount                float64   `csv:"Median Review Count"`
+	AvgApprovalCount                 float64   `csv:"Avg Approval Count"`
+	MedianApprovalCount              float64   `csv:"Median Approval Count"`
+	AvgAdditions                     float64   `csv:"Avg Additions"`
+	MedianAdditions                  float64   `csv:"Median Additions"`
+	AvgDeletions                     float64   `csv:"Avg Deletions"`
+	MedianDeletions                  float64   `csv:"Median Deletions"`
+	AvgChangedFiles                  float64   `csv:"Avg Changed Files"`
+	MedianChangedFiles               float64   `csv:"Median Changed Files"`
+	AvgFirstCommitToCreateHours      float64   `csv:"Avg First Commit to Create (Hours)"`
+	MedianFirstCommitToCreateHours   float64   `csv:"Median First Commit to Create (Hours)"`
+	AvgCreateToLastCommitHours       float64   `csv:"Avg Create to Last Commit (Hours)"`
+	MedianCreateToLastCommitHours    float64   `csv:"Median Create to Last Commit (Hours)"`
+	AvgCommitCountDuringPR           float64   `csv:"Avg Commit Count During PR"`
+	MedianCommitCountDuringPR        float64   `csv:"Median Commit Count During PR"`
+	AvgFirstCommitToMergeHours       float64   `csv:"Avg First Commit to Merge (Hours)"`
+	MedianFirstCommitToMergeHours    float64   `csv:"Median First Commit to Merge (Hours)"`
+	AvgLastCommitToMergeHours        float64   `csv:"Avg Last Commit to Merge (Hours)"`
+	MedianLastCommitToMergeHours     float64   `csv:"Median Last Commit to Merge (Hours)"`
+	AvgCreatedToFirstCommentHours    float64   `csv:"Avg Created to First Comment (Hours)"`
+	MedianCreatedToFirstCommentHours float64   `csv:"Median Created to First Comment (Hours)"`
+	AvgTimeToApprovalHours           float64   `csv:"Avg Time to Approval (Hours)"`
+	MedianTimeToApprovalHours        float64   `csv:"Median Time to Approval (Hours)"`
+	AvgTotalPRLifetimeHours          float64   `csv:"Avg Total PR Lifetime (Hours)"`
+	MedianTotalPRLifetimeHours       float64   `csv:"Median Total PR Lifetime (Hours)"`
+	AvgMaxNoCommentPeriodHours       float64   `csv:"Avg Max No Comment Period (Hours)"`
+	MedianMaxNoCommentPeriodHours    float64   `csv:"Median Max No Comment Period (Hours)"`
+	AvgMaxNoCommitPeriodHours        float64   `csv:"Avg Max No Commit Period (Hours)"`
+	MedianMaxNoCommitPeriodHours     float64   `csv:"Median Max No Commit Period (Hours)"`
+	AvgMaxNoActivityPeriodHours      float64   `csv:"Avg Max No Activity Period (Hours)"`
+	MedianMaxNoActivityPeriodHours   float64   `csv:"Median Max No Activity Period (Hours)"`
+	AvgReviewCoveragePercent         float64   `csv:"Avg Review Coverage (%)"`
+	MedianReviewCoveragePercent      float64   `csv:"Median Review Coverage (%)"`
+	AvgAuthorResponseLatencyHours    float64   `csv:"Avg Author Response Latency (Hours)"`
+	MedianAuthorResponseLatencyHours float64   `csv:"Median Author Response Latency (Hours)"`
+	AvgCodingHours                   float64   `csv:"Avg Coding (Hours)"`
+	MedianCodingHours                float64   `csv:"Median Coding (Hours)"`
+	AvgWaitingForReviewHours         float64   `csv:"Avg Waiting for Review (Hours)"`
+	MedianWaitingForReviewHours      float64   `csv:"Median Waiting for Review (Hours)"`
+	AvgInReviewHours                 float64   `csv:"Avg In Review (Hours)"`
+	MedianInReviewHours              float64   `csv:"Median In Review (Hours)"`
+	AvgWaitingToMergeHours           float64   `csv:"Avg Waiting to Merge (Hours)"`
+	MedianWaitingToMergeHours        float64   `csv:"Median Waiting to Merge (Hours)"`
+	AvgApproverCount                 float64   `csv:"Avg Approver Count"`
+	MedianApproverCount              float64   `csv:"Median Approver Count"`
+	AvgCodeOwnerApprovalCount        float64   `csv:"Avg Code Owner Approval Count"`
+	MedianCodeOwnerApprovalCount     float64   `csv:"Median Code Owner Approval Count"`
+	AvgFirstToLastApprovalHours      float64   `csv:"Avg First to Last Approval (Hours)"`
+	MedianFirstToLastApprovalHours   float64   `csv:"Median First to Last Approval (Hours)"`
+	AvgTestChangeRatio               float64   `csv:"Avg Test Change Ratio"`
+	MedianTestChangeRatio            float64   `csv:"Median Test Change Ratio"`
+	AvgReReviewLatencyHours          float64   `csv:"Avg Re-Review Latency (Hours)"`
+	MedianReReviewLatencyHours       float64   `csv:"Median Re-Review Latency (Hours)"`
+	AvgIssueLeadTimeHours            float64   `csv:"Avg Issue Lead Time (Hours)"`
+	MedianIssueLeadTimeHours         float64   `csv:"Median Issue Lead Time (Hours)"`
+	ReopenedCount                    int       `csv:"Reopened Count"` // PRs reopened at least once
+	ReopenedPercent                  float64   `csv:"Reopened (%)"`
+	RequestedReviewerReviewedPercent float64   `csv:"Requested Reviewer Reviewed (%)"` // Share of PRs with requested users in which one of them reviewed
+	StaleApprovalPRCount             int       `csv:"Stale Approval PR Count"`         // Approved PRs that received commits after an approval
+	StaleApprovalPercent             float64   `csv:"Stale Approval (%)"`              // Share of approved PRs that received commits after an approval
+	AvgBaseSyncMergeCount            float64   `csv:"Avg Base Sync Merge Count"`
+	BaseSyncMergedPercent            float64   `csv:"Base Sync Merged (%)"`            // Share of PRs that merged the base branch at least once
+	CommitsAfterApprovalPRCount      int       `csv:"Commits After Approval PR Count"` // Approved PRs merged with commits pushed after the final approval
+	CommitsAfterApprovalPercent      float64   `csv:"Commits After Approval (%)"`      // Share of approved PRs merged with commits pushed after the final approval
+	ActiveAuthorCount                int       `csv:"Active Author Count"`             // Distinct authors of the merged PRs
+	MergedPRsPerAuthor               float64   `csv:"Merged PRs per Author"`           // PR Count divided by Active Author Count
+	LinesMergedPerAuthor             float64   `csv:"Lines Merged per Author"`         // Additions plus deletions of the merged PRs divided by Active Author Count
+
+	// Averages weighted by the additions plus deletions of each PR, so large PRs count in proportion to their size
+	SizeWeightedAvgFirstCommitToMergeHours float64 `csv:"Size-Weighted Avg First Commit to Merge (Hours)"`
+	SizeWeightedAvgTotalPRLifetimeHours    float64 `csv:"Size-Weighted Avg Total PR Lifetime (Hours)"`
+
+	// Runner queue latency of the PRs whose head commit ran checks, and re-runs of flaky checks, from --ci-metrics
+	AvgCIQueueHours    float64 `csv:"Avg CI Queue (Hours)"`
+	MedianCIQueueHours float64 `csv:"Median CI Queue (Hours)"`
+	FlakyPRCount       int     `csv:"Flaky PR Count"`      // PRs with at least one flaky check retry
+	FlakyCheckRetries  int     `csv:"Flaky Check Retries"` // Flaky check retries of all the PRs
+
+	// Approvals of the PRs changing files with a user listed in CODEOWNERS
+	OwnedPRCount                int     `csv:"Owned PR Count"`
+	CodeOwnerApproverPercent    float64 `csv:"Code Owner Approver (%)"` // Share of the approvers of owned PRs who owned a changed file
+	AvgCodeOwnerCoveragePercent float64 `csv:"Avg Code Owner Approval Coverage (%)"`
+	FullyOwnerApprovedPercent   float64 `csv:"Fully Owner-Approved (%)"` // Share of owned PRs with every owned file approved by an owner
 }
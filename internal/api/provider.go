@@ -0,0 +1,195 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Supported source code hosting providers
+const (
+	ProviderGitHub      = "github"
+	ProviderGitLab      = "gitlab"
+	ProviderBitbucket   = "bitbucket"
+	ProviderGitea       = "gitea"
+	ProviderAzureDevOps = "azure-devops"
+)
+
+// Abstracts a source code hosting service so the metrics pipeline can run against any of them.
+// Implementations return go-github types, the format responses are recorded and replayed in,
+// filled with what their own API reports; the Converter of each provider maps them to the model.
+type Provider interface {
+	GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error)
+	GetPRDetails(owner, repo string, number int) (*github.PullRequest, error)
+	GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error)
+	GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error)
+	GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error)
+	GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error)
+	GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error)
+	GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error)
+	GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error)
+	GetRepository(owner, repo string) (*github.Repository, error)
+	GetBranchProtection(owner, repo, branch string) (*github.Protection, error)
+	GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error)
+	GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error)
+	GetCodeOwners(owner, repo, ref string) (string, error)
+	GetGitAttributes(owner, repo, ref string) (string, error)
+	Usage() APIUsage
+	SetRequestBudget(budget RequestBudget)
+	EnableResponseCache(options CacheOptions) error
+	ConfigureTransport(options TransportOptions) error
+}
+
+// Timestamps the date range of a PullRequestQuery can apply to
+const (
+	DateFieldCreated = "created"
+	DateFieldMerged  = "merged"
+	DateFieldClosed  = "closed"
+)
+
+// States a PullRequestQuery can narrow the PRs down to
+const (
+	StateAll    = "all"
+	StateOpen   = "open"
+	StateMerged = "merged"
+	StateClosed = "closed" // Closed without merging
+)
+
+// Selects which pull requests are fetched
+type PullRequestQuery struct {
+	StartDate    time.Time // Inclusive lower bound of the date field
+	EndDate      time.Time // Inclusive upper bound of the date field
+	DateField    string    // Timestamp the range applies to; defaults to the creation date
+	UpdatedSince time.Time // Also include PRs updated at or after this time, regardless of the date range
+	State        string    // Current state of the PRs to include; defaults to all
+}
+
+// Returns the PR timestamp the date range applies to
+func (q PullRequestQuery) FilteredTime(pr *github.PullRequest) *github.Timestamp {
+	switch q.DateField {
+	case DateFieldMerged:
+		return pr.MergedAt
+	case DateFieldClosed:
+		return pr.ClosedAt
+	default:
+		return pr.CreatedAt
+	}
+}
+
+// Reports whether a PR is in the state asked for
+func (q PullRequestQuery) MatchesState(pr *github.PullRequest) bool {
+	switch q.State {
+	case StateOpen:
+		return pr.GetState() == "open"
+	case StateMerged:
+		return pr.MergedAt != nil
+	case StateClosed:
+		return pr.GetState() == "closed" && pr.MergedAt == nil
+	default:
+		return true
+	}
+}
+
+// Returns the state to list PRs in on GitHub, which counts merged PRs as closed
+func (q PullRequestQuery) ListState() string {
+	switch q.State {
+	case StateOpen:
+		return "open"
+	case StateMerged, StateClosed:
+		return "closed"
+	default:
+		return "all"
+	}
+}
+
+// Reports whether a PR is in the state asked for and falls within the date range or was
+// updated since the cutoff
+func (q PullRequestQuery) Matches(pr *github.PullRequest) bool {
+	if !q.MatchesState(pr) {
+		return false
+	}
+
+	if filteredAt := q.FilteredTime(pr); filteredAt != nil {
+		t := filteredAt.Time
+		if (t.After(q.StartDate) || t.Equal(q.StartDate)) &&
+			(t.Before(q.EndDate) || t.Equal(q.EndDate)) {
+			return true
+		}
+	}
+
+	if !q.UpdatedSince.IsZero() && pr.UpdatedAt != nil {
+		return !pr.UpdatedAt.Before(q.UpdatedSince)
+	}
+
+	return false
+}
+
+// Returns the API URL used when none is specified for the provider, or an empty
+// string for self-hosted providers that have no public instance
+func DefaultAPIURL(provider string) string {
+	switch provider {
+	case ProviderGitea:
+		return ""
+	case ProviderGitLab:
+		return "https://gitlab.com/api/v4"
+	case ProviderBitbucket:
+		return "https://api.bitbucket.org/2.0"
+	case ProviderAzureDevOps:
+		return "https://dev.azure.com"
+	default:
+		return "https://api.github.com"
+	}
+}
+
+// Creates the provider implementation matching the given name. Only GitHub accepts
+// more than one token.
+func NewProvider(provider, apiURL string, tokens []string, logger *utils.Logger) (Provider, error) {
+	if provider != ProviderGitHub && len(tokens) > 1 {
+		return nil, fmt.Errorf("token rotation is not supported by the %s provider", provider)
+	}
+	var token string
+	if len(tokens) > 0 {
+		token = tokens[0]
+	}
+
+	switch provider {
+	case ProviderGitHub:
+		return NewClient(apiURL, tokens, logger)
+	case ProviderGitLab:
+		return NewGitLabClient(apiURL, token, logger)
+	case ProviderBitbucket:
+		return NewBitbucketClient(apiURL, token, logger)
+	case ProviderGitea:
+		return NewGiteaClient(apiURL, token, logger)
+	case ProviderAzureDevOps:
+		return NewAzureDevOpsClient(apiURL, token, logger)
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", provider)
+	}
+}
+
+// Returns the GitHub visibility of a repository on providers that only tell whether it is private
+func visibilityOf(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
+
+// Extracts the attributes of a repository fetched from a provider
+func NewRepository(repository *github.Repository) Repository {
+	result := Repository{
+		FullName:        repository.GetFullName(),
+		PrimaryLanguage: repository.GetLanguage(),
+		Topics:          repository.Topics,
+		Visibility:      repository.GetVisibility(),
+		DefaultBranch:   repository.GetDefaultBranch(),
+	}
+	// Older GitHub Enterprise Server versions only report whether the repository is private
+	if result.Visibility == "" && repository.Private != nil {
+		result.Visibility = visibilityOf(repository.GetPrivate())
+	}
+	return result
+}
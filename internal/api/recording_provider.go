@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Wraps a provider and saves every response as raw JSON in the layout read by FixtureClient
+type RecordingProvider struct {
+	provider Provider
+	dir      string
+	logger   *utils.Logger
+}
+
+// Initializes recording provider writing responses under the given directory
+func NewRecordingProvider(provider Provider, dir string, logger *utils.Logger) *RecordingProvider {
+	logger.Debug("Recording API responses to %s", dir)
+
+	return &RecordingProvider{
+		provider: provider,
+		dir:      dir,
+		logger:   logger,
+	}
+}
+
+// Writes a value as indented JSON, logging instead of failing so recording never breaks a run
+func (p *RecordingProvider) record(path string, value any) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		p.logger.Warn("Failed to create directory for %s: %v", path, err)
+		return
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		p.logger.Warn("Failed to encode %s: %v", path, err)
+		return
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		p.logger.Warn("Failed to write %s: %v", path, err)
+	}
+}
+
+// Fetches and records PRs matching the query
+func (p *RecordingProvider) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	prs, err := p.provider.GetPullRequests(owner, repo, query)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "pulls.json"), prs)
+	}
+	return prs, err
+}
+
+// Fetches and records PR details
+func (p *RecordingProvider) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	pr, err := p.provider.GetPRDetails(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "details.json"), pr)
+	}
+	return pr, err
+}
+
+// Fetches and records PR commits
+func (p *RecordingProvider) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	commits, err := p.provider.GetPRCommits(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "commits.json"), commits)
+	}
+	return commits, err
+}
+
+// Fetches and records PR review comments
+func (p *RecordingProvider) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	comments, err := p.provider.GetPRComments(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "comments.json"), comments)
+	}
+	return comments, err
+}
+
+// Fetches and records PR conversation comments
+func (p *RecordingProvider) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	comments, err := p.provider.GetPRIssueComments(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "issue_comments.json"), comments)
+	}
+	return comments, err
+}
+
+// Fetches and records PR reviews
+func (p *RecordingProvider) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	reviews, err := p.provider.GetPRReviews(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "reviews.json"), reviews)
+	}
+	return reviews, err
+}
+
+// Fetches and records PR files
+func (p *RecordingProvider) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	files, err := p.provider.GetPRFiles(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "files.json"), files)
+	}
+	return files, err
+}
+
+// Fetches and records PR review threads
+func (p *RecordingProvider) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	threads, err := p.provider.GetPRReviewThreads(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "review_threads.json"), threads)
+	}
+	return threads, err
+}
+
+// Fetches and records PR timeline events
+func (p *RecordingProvider) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	events, err := p.provider.GetPRTimelineEvents(owner, repo, number)
+	if err == nil {
+		p.record(fixturePRPath(p.dir, owner, repo, number, "timeline_events.json"), events)
+	}
+	return events, err
+}
+
+// Fetches and records the repository
+func (p *RecordingProvider) GetRepository(owner, repo string) (*github.Repository, error) {
+	repository, err := p.provider.GetRepository(owner, repo)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "repository.json"), repository)
+	}
+	return repository, err
+}
+
+// Fetches and records branch protection, recording null for unprotected branches
+func (p *RecordingProvider) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	protection, err := p.provider.GetBranchProtection(owner, repo, branch)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "branches", branch, "protection.json"), protection)
+	}
+	return protection, err
+}
+
+// Fetches and records the CODEOWNERS file
+func (p *RecordingProvider) GetCodeOwners(owner, repo, ref string) (string, error) {
+	content, err := p.provider.GetCodeOwners(owner, repo, ref)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "branches", ref, "codeowners.json"), content)
+	}
+	return content, err
+}
+
+// Fetches and records the .gitattributes file
+func (p *RecordingProvider) GetGitAttributes(owner, repo, ref string) (string, error) {
+	content, err := p.provider.GetGitAttributes(owner, repo, ref)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "branches", ref, "gitattributes.json"), content)
+	}
+	return content, err
+}
+
+// Fetches and records commit statuses
+func (p *RecordingProvider) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	statuses, err := p.provider.GetCommitStatuses(owner, repo, ref)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "commits", ref, "statuses.json"), statuses)
+	}
+	return statuses, err
+}
+
+// Fetches and records check runs
+func (p *RecordingProvider) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	checkRuns, err := p.provider.GetCheckRuns(owner, repo, ref)
+	if err == nil {
+		p.record(fixturePath(p.dir, owner, repo, "commits", ref, "check_runs.json"), checkRuns)
+	}
+	return checkRuns, err
+}
+
+// Returns the API usage of the wrapped provider
+func (p *RecordingProvider) Usage() APIUsage {
+	return p.provider.Usage()
+}
+
+// Limits the API requests of the wrapped provider
+func (p *RecordingProvider) SetRequestBudget(budget RequestBudget) {
+	p.provider.SetRequestBudget(budget)
+}
+
+// Enables the response cache of the wrapped provider
+func (p *RecordingProvider) EnableResponseCache(options CacheOptions) error {
+	return p.provider.EnableResponseCache(options)
+}
+
+// Configures the network settings of the wrapped provider
+func (p *RecordingProvider) ConfigureTransport(options TransportOptions) error {
+	return p.provider.ConfigureTransport(options)
+}
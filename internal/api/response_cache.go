@@ -0,0 +1,267 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Values of the cache hit header telling how a cached response was served
+const (
+	cacheHitFresh       = "fresh"       // Served from disk without contacting the API
+	cacheHitRevalidated = "revalidated" // The API confirmed the cached response is unchanged
+)
+
+// Where and for how long API responses are cached
+type CacheOptions struct {
+	Dir string        // Directory holding the cached responses
+	TTL time.Duration // Serve responses younger than this without revalidating; 0 uses the Cache-Control max-age of the response
+}
+
+// Response stored on disk together with the validators it was served with
+type cachedResponse struct {
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"last_modified,omitempty"`
+	MaxAge       int         `json:"max_age,omitempty"` // Seconds the response stays fresh per Cache-Control
+	StoredAt     time.Time   `json:"stored_at"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// Persists cached responses by key, so other stores can replace the on-disk one
+type responseStore interface {
+	load(key string) *cachedResponse
+	store(key string, cached *cachedResponse)
+}
+
+// Caches GET responses following their Cache-Control and validator headers: fresh
+// responses are served without a request, and stale ones are revalidated with
+// If-None-Match or If-Modified-Since, so unchanged resources, such as the pages of
+// a PR list, cost no rate limit on re-runs
+type responseCache struct {
+	base   http.RoundTripper
+	store  responseStore
+	ttl    time.Duration
+	logger *utils.Logger
+}
+
+// Initializes response cache storing entries under the configured directory
+func newResponseCache(base http.RoundTripper, options CacheOptions, logger *utils.Logger) (*responseCache, error) {
+	store, err := newDiskResponseStore(options.Dir, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responseCache{
+		base:   base,
+		store:  store,
+		ttl:    options.TTL,
+		logger: logger,
+	}, nil
+}
+
+// Returns the cache key of a request URL
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns the cached response if it is still fresh, or nil if the request must be sent
+func (c *responseCache) fresh(req *http.Request) *http.Response {
+	if req.Method != http.MethodGet {
+		return nil
+	}
+
+	cached := c.store.load(cacheKey(req))
+	if cached == nil || cached.StoredAt.IsZero() {
+		return nil
+	}
+
+	lifetime := c.ttl
+	if lifetime == 0 {
+		lifetime = time.Duration(cached.MaxAge) * time.Second
+	}
+	if time.Since(cached.StoredAt) >= lifetime {
+		return nil
+	}
+
+	c.logger.Debug("Serving %s from cache without revalidating", req.URL)
+	header := cached.Header.Clone()
+	header.Set(cacheHitHeader, cacheHitFresh)
+	return &http.Response{
+		Status:        strconv.Itoa(http.StatusOK) + " " + http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// Sends the request with the validators of a cached response, replaying it on 304
+func (c *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return c.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := c.store.load(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.logger.Debug("Serving %s from cache", req.URL)
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body: %v", err)
+		}
+
+		// Keep the cached headers, such as Link, but take fresh rate limit headers from the 304
+		header := cached.Header.Clone()
+		for key, values := range resp.Header {
+			header[key] = values
+		}
+
+		// The revalidated response is fresh again
+		cached.StoredAt = time.Now()
+		cached.MaxAge = maxAge(resp.Header)
+		c.store.store(key, cached)
+
+		header.Set(cacheHitHeader, cacheHitRevalidated)
+		resp.StatusCode = http.StatusOK
+		resp.Status = http.StatusText(http.StatusOK)
+		resp.Header = header
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || hasCacheDirective(resp.Header, "no-store") {
+		return resp, nil
+	}
+	if etag == "" && lastModified == "" && maxAge(resp.Header) == 0 && c.ttl == 0 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		c.logger.Warn("Failed to close response body: %v", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.store.store(key, &cachedResponse{
+		ETag:         etag,
+		LastModified: lastModified,
+		MaxAge:       maxAge(resp.Header),
+		StoredAt:     time.Now(),
+		Header:       resp.Header,
+		Body:         body,
+	})
+
+	return resp, nil
+}
+
+// Returns the seconds a response may be served without revalidation per its Cache-Control header
+func maxAge(header http.Header) int {
+	if hasCacheDirective(header, "no-cache") {
+		return 0
+	}
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds > 0 {
+			return seconds
+		}
+	}
+	return 0
+}
+
+// Reports whether the Cache-Control header contains a directive
+func hasCacheDirective(header http.Header, directive string) bool {
+	for _, value := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(value), "=")
+		if strings.EqualFold(name, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stores each cached response as a JSON file named after its key
+type diskResponseStore struct {
+	dir    string
+	logger *utils.Logger
+}
+
+// Initializes disk store, creating the directory if needed
+func newDiskResponseStore(dir string, logger *utils.Logger) (*diskResponseStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &diskResponseStore{
+		dir:    dir,
+		logger: logger,
+	}, nil
+}
+
+// Reads a cached response, returning nil when there is none
+func (s *diskResponseStore) load(key string) *cachedResponse {
+	path := filepath.Join(s.dir, key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		s.logger.Warn("Ignoring unreadable cache entry %s: %v", path, err)
+		return nil
+	}
+	return &cached
+}
+
+// Writes a cached response, logging instead of failing so caching never breaks a run
+func (s *diskResponseStore) store(key string, cached *cachedResponse) {
+	path := filepath.Join(s.dir, key+".json")
+	data, err := json.Marshal(cached)
+	if err != nil {
+		s.logger.Warn("Failed to encode cache entry %s: %v", path, err)
+		return
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		s.logger.Warn("Failed to write cache entry %s: %v", path, err)
+	}
+}
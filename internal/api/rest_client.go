@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Minimal JSON REST client shared by the non-GitHub providers
+type restClient struct {
+	httpClient *http.Client
+	tracker    *requestTracker
+	baseURL    string
+	headers    map[string]string
+	ctx        context.Context
+	logger     *utils.Logger
+}
+
+// Initializes REST client with base URL and headers sent on every request
+func newRESTClient(baseURL string, headers map[string]string, logger *utils.Logger) *restClient {
+	tracker := newRequestTracker(logger)
+	return &restClient{
+		httpClient: &http.Client{Transport: tracker},
+		tracker:    tracker,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		headers:    headers,
+		ctx:        context.Background(),
+		logger:     logger,
+	}
+}
+
+// Performs a GET request and decodes the JSON response body into out
+func (c *restClient) getJSON(path string, query url.Values, out any) (*http.Response, error) {
+	requestURL := c.baseURL + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	return c.getJSONURL(requestURL, out)
+}
+
+// Performs a GET request against an absolute URL and decodes the JSON response body into out
+func (c *restClient) getJSONURL(requestURL string, out any) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp, &utils.APIError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("failed to decode response from %s: %v", requestURL, err)
+	}
+
+	return resp, nil
+}
+
+// Decodes base64 file content as returned by the repository file APIs, which may wrap lines
+func decodeBase64Content(content string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content, "\n", ""))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode file content: %v", err)
+	}
+	return string(data), nil
+}
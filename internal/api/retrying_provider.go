@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"github.com/google/go-github/v74/github"
+)
+
+// Attempts made for each request before its error is returned
+const retryAttempts = 3
+
+// Pause before the first retry, doubled before each further one
+var retryDelay = 2 * time.Second
+
+// Wraps a provider and retries failed requests with growing pauses, returning the last error
+// once the attempts run out
+type RetryingProvider struct {
+	provider Provider
+	logger   *utils.Logger
+}
+
+// Initializes retrying provider around the given provider
+func NewRetryingProvider(provider Provider, logger *utils.Logger) *RetryingProvider {
+	return &RetryingProvider{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Calls the fetch until it succeeds or the attempts run out. Errors another attempt cannot fix,
+// such as rejected credentials, a missing resource, or an exhausted budget, are returned at once.
+func retry[T any](p *RetryingProvider, description string, fetch func() (T, error)) (T, error) {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		result, err := fetch()
+		if err == nil || attempt == retryAttempts || isPermanentError(err) {
+			return result, err
+		}
+		p.logger.Warn("Failed to fetch %s (attempt %d of %d), retrying in %v: %v", description, attempt, retryAttempts, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// Reports whether the error would come back however often the request was retried
+func isPermanentError(err error) bool {
+	if errors.Is(err, ErrRequestBudgetExhausted) || IsAuthError(err) {
+		return true
+	}
+
+	statusCode := 0
+	var errorResponse *github.ErrorResponse
+	var apiErr *utils.APIError
+	switch {
+	case errors.As(err, &errorResponse) && errorResponse.Response != nil:
+		statusCode = errorResponse.Response.StatusCode
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.StatusCode
+	}
+	return statusCode == http.StatusNotFound || statusCode == http.StatusUnprocessableEntity
+}
+
+// Fetches PRs matching the query, retrying on failure
+func (p *RetryingProvider) GetPullRequests(owner, repo string, query PullRequestQuery) ([]*github.PullRequest, error) {
+	return retry(p, "pull requests", func() ([]*github.PullRequest, error) {
+		return p.provider.GetPullRequests(owner, repo, query)
+	})
+}
+
+// Fetches PR details, retrying on failure
+func (p *RetryingProvider) GetPRDetails(owner, repo string, number int) (*github.PullRequest, error) {
+	return retry(p, fmt.Sprintf("details of PR #%d", number), func() (*github.PullRequest, error) {
+		return p.provider.GetPRDetails(owner, repo, number)
+	})
+}
+
+// Fetches PR commits, retrying on failure
+func (p *RetryingProvider) GetPRCommits(owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	return retry(p, fmt.Sprintf("commits of PR #%d", number), func() ([]*github.RepositoryCommit, error) {
+		return p.provider.GetPRCommits(owner, repo, number)
+	})
+}
+
+// Fetches PR review comments, retrying on failure
+func (p *RetryingProvider) GetPRComments(owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	return retry(p, fmt.Sprintf("comments of PR #%d", number), func() ([]*github.PullRequestComment, error) {
+		return p.provider.GetPRComments(owner, repo, number)
+	})
+}
+
+// Fetches PR conversation comments, retrying on failure
+func (p *RetryingProvider) GetPRIssueComments(owner, repo string, number int) ([]*github.IssueComment, error) {
+	return retry(p, fmt.Sprintf("conversation comments of PR #%d", number), func() ([]*github.IssueComment, error) {
+		return p.provider.GetPRIssueComments(owner, repo, number)
+	})
+}
+
+// Fetches PR reviews, retrying on failure
+func (p *RetryingProvider) GetPRReviews(owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	return retry(p, fmt.Sprintf("reviews of PR #%d", number), func() ([]*github.PullRequestReview, error) {
+		return p.provider.GetPRReviews(owner, repo, number)
+	})
+}
+
+// Fetches PR files, retrying on failure
+func (p *RetryingProvider) GetPRFiles(owner, repo string, number int) ([]*github.CommitFile, error) {
+	return retry(p, fmt.Sprintf("files of PR #%d", number), func() ([]*github.CommitFile, error) {
+		return p.provider.GetPRFiles(owner, repo, number)
+	})
+}
+
+// Fetches PR review threads, retrying on failure
+func (p *RetryingProvider) GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error) {
+	return retry(p, fmt.Sprintf("review threads of PR #%d", number), func() ([]*ReviewThread, error) {
+		return p.provider.GetPRReviewThreads(owner, repo, number)
+	})
+}
+
+// Fetches PR timeline events, retrying on failure
+func (p *RetryingProvider) GetPRTimelineEvents(owner, repo string, number int) ([]*github.IssueEvent, error) {
+	return retry(p, fmt.Sprintf("timeline events of PR #%d", number), func() ([]*github.IssueEvent, error) {
+		return p.provider.GetPRTimelineEvents(owner, repo, number)
+	})
+}
+
+// Fetches repository metadata, retrying on failure
+func (p *RetryingProvider) GetRepository(owner, repo string) (*github.Repository, error) {
+	return retry(p, "repository metadata", func() (*github.Repository, error) {
+		return p.provider.GetRepository(owner, repo)
+	})
+}
+
+// Fetches branch protection, retrying on failure
+func (p *RetryingProvider) GetBranchProtection(owner, repo, branch string) (*github.Protection, error) {
+	return retry(p, "branch protection for "+branch, func() (*github.Protection, error) {
+		return p.provider.GetBranchProtection(owner, repo, branch)
+	})
+}
+
+// Fetches commit statuses, retrying on failure
+func (p *RetryingProvider) GetCommitStatuses(owner, repo, ref string) ([]*github.RepoStatus, error) {
+	return retry(p, "commit statuses of "+ref, func() ([]*github.RepoStatus, error) {
+		return p.provider.GetCommitStatuses(owner, repo, ref)
+	})
+}
+
+// Fetches check runs, retrying on failure
+func (p *RetryingProvider) GetCheckRuns(owner, repo, ref string) ([]*github.CheckRun, error) {
+	return retry(p, "check runs of "+ref, func() ([]*github.CheckRun, error) {
+		return p.provider.GetCheckRuns(owner, repo, ref)
+	})
+}
+
+// Fetches the CODEOWNERS file, retrying on failure
+func (p *RetryingProvider) GetCodeOwners(owner, repo, ref string) (string, error) {
+	return retry(p, "CODEOWNERS on "+ref, func() (string, error) {
+		return p.provider.GetCodeOwners(owner, repo, ref)
+	})
+}
+
+// Fetches the .gitattributes file, retrying on failure
+func (p *RetryingProvider) GetGitAttributes(owner, repo, ref string) (string, error) {
+	return retry(p, ".gitattributes on "+ref, func() (string, error) {
+		return p.provider.GetGitAttributes(owner, repo, ref)
+	})
+}
+
+// Returns the API usage of the wrapped provider
+func (p *RetryingProvider) Usage() APIUsage {
+	return p.provider.Usage()
+}
+
+// Limits the API requests of the wrapped provider
+func (p *RetryingProvider) SetRequestBudget(budget RequestBudget) {
+	p.provider.SetRequestBudget(budget)
+}
+
+// Enables the response cache of the wrapped provider
+func (p *RetryingProvider) EnableResponseCache(options CacheOptions) error {
+	return p.provider.EnableResponseCache(options)
+}
+
+// Configures the network settings of the wrapped provider
+func (p *RetryingProvider) ConfigureTransport(options TransportOptions) error {
+	return p.provider.ConfigureTransport(options)
+}
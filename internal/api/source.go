@@ -0,0 +1,357 @@
+package api
+
+import (
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/google/go-github/v74/github"
+)
+
+// Converts the responses of a provider to the provider-neutral model. Providers return go-github
+// types, the format responses are recorded and replayed in, but each fills them with what its own
+// API reports, such as its own review verdicts and event names, so each has its own converter.
+// Conversions return nil for items that have no place in the model.
+type Converter interface {
+	PullRequest(pr *github.PullRequest) *model.PullRequest
+	CommitEvent(commit *github.RepositoryCommit) *model.CommitEvent
+	CommentEvent(comment *github.PullRequestComment) *model.CommentEvent
+	IssueCommentEvent(comment *github.IssueComment) *model.CommentEvent
+	ReviewEvent(review *github.PullRequestReview) *model.ReviewEvent
+	FileChange(file *github.CommitFile) *model.FileChange
+	TimelineEvent(event *github.IssueEvent) *model.TimelineEvent
+	BranchProtection(protection *github.Protection) *model.BranchProtection
+	CommitStatus(status *github.RepoStatus) *model.CommitStatus
+	CheckRun(checkRun *github.CheckRun) *model.CheckRun
+}
+
+// Returns the converter for the responses of the named provider, including recordings of it
+func NewConverter(provider string) Converter {
+	switch provider {
+	case ProviderGitLab:
+		return gitLabConverter{}
+	case ProviderBitbucket:
+		return bitbucketConverter{}
+	case ProviderGitea:
+		return giteaConverter{}
+	case ProviderAzureDevOps:
+		return azureDevOpsConverter{}
+	default:
+		return GitHubConverter{}
+	}
+}
+
+// Serves the data of a provider in the provider-neutral model, converting each response with
+// the provider's converter
+type providerSource struct {
+	provider  Provider
+	converter Converter
+}
+
+// Wraps a provider so its responses are converted to the provider-neutral model
+func NewSource(provider Provider, converter Converter) model.Source {
+	return &providerSource{
+		provider:  provider,
+		converter: converter,
+	}
+}
+
+// Fetches the PR details, including its size and merger
+func (s *providerSource) GetPRDetails(owner, repo string, number int) (*model.PullRequest, error) {
+	pr, err := s.provider.GetPRDetails(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return s.converter.PullRequest(pr), nil
+}
+
+// Fetches the commits of a PR
+func (s *providerSource) GetPRCommits(owner, repo string, number int) ([]*model.CommitEvent, error) {
+	commits, err := s.provider.GetPRCommits(owner, repo, number)
+	return convertAll(commits, s.converter.CommitEvent), err
+}
+
+// Fetches the inline review comments of a PR
+func (s *providerSource) GetPRComments(owner, repo string, number int) ([]*model.CommentEvent, error) {
+	comments, err := s.provider.GetPRComments(owner, repo, number)
+	return convertAll(comments, s.converter.CommentEvent), err
+}
+
+// Fetches the conversation comments of a PR
+func (s *providerSource) GetPRIssueComments(owner, repo string, number int) ([]*model.CommentEvent, error) {
+	comments, err := s.provider.GetPRIssueComments(owner, repo, number)
+	return convertAll(comments, s.converter.IssueCommentEvent), err
+}
+
+// Fetches the reviews of a PR
+func (s *providerSource) GetPRReviews(owner, repo string, number int) ([]*model.ReviewEvent, error) {
+	reviews, err := s.provider.GetPRReviews(owner, repo, number)
+	return convertAll(reviews, s.converter.ReviewEvent), err
+}
+
+// Fetches the changed files of a PR
+func (s *providerSource) GetPRFiles(owner, repo string, number int) ([]*model.FileChange, error) {
+	files, err := s.provider.GetPRFiles(owner, repo, number)
+	return convertAll(files, s.converter.FileChange), err
+}
+
+// Fetches the review threads of a PR
+func (s *providerSource) GetPRReviewThreads(owner, repo string, number int) ([]*model.ReviewThread, error) {
+	threads, err := s.provider.GetPRReviewThreads(owner, repo, number)
+	return convertAll(threads, newReviewThread), err
+}
+
+// Fetches the timeline events of a PR
+func (s *providerSource) GetPRTimelineEvents(owner, repo string, number int) ([]*model.TimelineEvent, error) {
+	events, err := s.provider.GetPRTimelineEvents(owner, repo, number)
+	return convertAll(events, s.converter.TimelineEvent), err
+}
+
+// Fetches the protection of a branch, which is nil when the branch is unprotected
+func (s *providerSource) GetBranchProtection(owner, repo, branch string) (*model.BranchProtection, error) {
+	protection, err := s.provider.GetBranchProtection(owner, repo, branch)
+	if err != nil || protection == nil {
+		return nil, err
+	}
+	return s.converter.BranchProtection(protection), nil
+}
+
+// Fetches the commit statuses of a ref
+func (s *providerSource) GetCommitStatuses(owner, repo, ref string) ([]*model.CommitStatus, error) {
+	statuses, err := s.provider.GetCommitStatuses(owner, repo, ref)
+	return convertAll(statuses, s.converter.CommitStatus), err
+}
+
+// Fetches the check runs of a ref
+func (s *providerSource) GetCheckRuns(owner, repo, ref string) ([]*model.CheckRun, error) {
+	checkRuns, err := s.provider.GetCheckRuns(owner, repo, ref)
+	return convertAll(checkRuns, s.converter.CheckRun), err
+}
+
+// Fetches the CODEOWNERS file of a ref
+func (s *providerSource) GetCodeOwners(owner, repo, ref string) (string, error) {
+	return s.provider.GetCodeOwners(owner, repo, ref)
+}
+
+// Fetches the .gitattributes file of a ref
+func (s *providerSource) GetGitAttributes(owner, repo, ref string) (string, error) {
+	return s.provider.GetGitAttributes(owner, repo, ref)
+}
+
+// Reports whether the request budget of the provider is used up
+func (s *providerSource) BudgetExhausted() bool {
+	return s.provider.Usage().BudgetExhausted
+}
+
+// Converts the PRs listed by a provider to the provider-neutral model
+func NewPullRequests(converter Converter, prs []*github.PullRequest) []*model.PullRequest {
+	return convertAll(prs, converter.PullRequest)
+}
+
+// Converts GitHub responses, and those of fixtures and in-memory data, which come as GitHub
+// returns them
+type GitHubConverter struct{}
+
+// Converts a PR, with its size and merger when the details were fetched
+func (GitHubConverter) PullRequest(pr *github.PullRequest) *model.PullRequest {
+	result := newPullRequest(pr)
+	for _, team := range pr.RequestedTeams {
+		if slug := team.GetSlug(); slug != "" {
+			result.RequestedTeams = append(result.RequestedTeams, slug)
+		}
+	}
+	return result
+}
+
+// Converts a commit, keeping the author and committer dates so either can be chosen
+func (GitHubConverter) CommitEvent(commit *github.RepositoryCommit) *model.CommitEvent {
+	return &model.CommitEvent{
+		SHA:         commit.GetSHA(),
+		Message:     commit.GetCommit().GetMessage(),
+		AuthorLogin: commit.GetAuthor().GetLogin(),
+		AuthorName:  commit.GetCommit().GetAuthor().GetName(),
+		AuthoredAt:  commit.GetCommit().GetAuthor().GetDate().Time,
+		CommittedAt: commit.GetCommit().GetCommitter().GetDate().Time,
+		ParentCount: len(commit.Parents),
+	}
+}
+
+// Converts an inline review comment
+func (GitHubConverter) CommentEvent(comment *github.PullRequestComment) *model.CommentEvent {
+	return &model.CommentEvent{
+		Author:    comment.GetUser().GetLogin(),
+		Path:      comment.GetPath(),
+		CreatedAt: comment.GetCreatedAt().Time,
+	}
+}
+
+// Converts a conversation comment
+func (GitHubConverter) IssueCommentEvent(comment *github.IssueComment) *model.CommentEvent {
+	return &model.CommentEvent{
+		Author:    comment.GetUser().GetLogin(),
+		CreatedAt: comment.GetCreatedAt().Time,
+	}
+}
+
+// Converts a review, whose state GitHub names as the model does
+func (GitHubConverter) ReviewEvent(review *github.PullRequestReview) *model.ReviewEvent {
+	return newReviewEvent(review, review.GetState())
+}
+
+// Converts a changed file
+func (GitHubConverter) FileChange(file *github.CommitFile) *model.FileChange {
+	return &model.FileChange{
+		Path:      file.GetFilename(),
+		Additions: file.GetAdditions(),
+		Deletions: file.GetDeletions(),
+	}
+}
+
+// Converts a timeline event, whose type GitHub names as the model does
+func (GitHubConverter) TimelineEvent(event *github.IssueEvent) *model.TimelineEvent {
+	return &model.TimelineEvent{
+		Type:              event.GetEvent(),
+		Actor:             event.GetActor().GetLogin(),
+		CreatedAt:         event.GetCreatedAt().Time,
+		RequestedReviewer: event.GetRequestedReviewer().GetLogin(),
+		RequestedTeam:     event.GetRequestedTeam().GetSlug(),
+	}
+}
+
+// Converts branch protection, whose required checks newer servers list as checks and older ones
+// as contexts
+func (GitHubConverter) BranchProtection(protection *github.Protection) *model.BranchProtection {
+	result := &model.BranchProtection{}
+	if reviews := protection.RequiredPullRequestReviews; reviews != nil {
+		result.RequiredApprovals = reviews.RequiredApprovingReviewCount
+	}
+	if checks := protection.RequiredStatusChecks; checks != nil {
+		if checks.Checks != nil {
+			for _, check := range *checks.Checks {
+				result.RequiredChecks = append(result.RequiredChecks, check.Context)
+			}
+		} else if checks.Contexts != nil {
+			result.RequiredChecks = *checks.Contexts
+		}
+	}
+	return result
+}
+
+// Converts a commit status
+func (GitHubConverter) CommitStatus(status *github.RepoStatus) *model.CommitStatus {
+	return &model.CommitStatus{
+		Context: status.GetContext(),
+		State:   status.GetState(),
+	}
+}
+
+// Converts a check run
+func (GitHubConverter) CheckRun(checkRun *github.CheckRun) *model.CheckRun {
+	return &model.CheckRun{
+		ID:         checkRun.GetID(),
+		Name:       checkRun.GetName(),
+		Conclusion: checkRun.GetConclusion(),
+		StartedAt:  checkRun.GetStartedAt().Time,
+	}
+}
+
+// Converts the fields of a PR every provider fills the same way, leaving out team review
+// requests, which providers name differently
+func newPullRequest(pr *github.PullRequest) *model.PullRequest {
+	result := &model.PullRequest{
+		Number:       pr.GetNumber(),
+		Title:        pr.GetTitle(),
+		Author:       pr.GetUser().GetLogin(),
+		State:        pr.GetState(),
+		CreatedAt:    pr.GetCreatedAt().Time,
+		MergedAt:     pr.GetMergedAt().Time,
+		MergedBy:     pr.GetMergedBy().GetLogin(),
+		Milestone:    pr.GetMilestone().GetTitle(),
+		AutoMerge:    pr.AutoMerge != nil,
+		BaseBranch:   pr.GetBase().GetRef(),
+		BaseSHA:      pr.GetBase().GetSHA(),
+		BaseRepo:     pr.GetBase().GetRepo().GetFullName(),
+		HeadBranch:   pr.GetHead().GetRef(),
+		HeadLabel:    pr.GetHead().GetLabel(),
+		HeadSHA:      pr.GetHead().GetSHA(),
+		HeadRepo:     pr.GetHead().GetRepo().GetFullName(),
+		Additions:    pr.GetAdditions(),
+		Deletions:    pr.GetDeletions(),
+		ChangedFiles: pr.GetChangedFiles(),
+	}
+	for _, label := range pr.Labels {
+		result.Labels = append(result.Labels, label.GetName())
+	}
+	for _, assignee := range pr.Assignees {
+		result.Assignees = append(result.Assignees, assignee.GetLogin())
+	}
+	for _, reviewer := range pr.RequestedReviewers {
+		if login := reviewer.GetLogin(); login != "" {
+			result.RequestedReviewers = append(result.RequestedReviewers, login)
+		}
+	}
+	return result
+}
+
+// Converts a review with the state its provider's verdict maps to
+func newReviewEvent(review *github.PullRequestReview, state string) *model.ReviewEvent {
+	return &model.ReviewEvent{
+		Reviewer:    review.GetUser().GetLogin(),
+		State:       state,
+		SubmittedAt: review.GetSubmittedAt().Time,
+	}
+}
+
+// Converts a review recorded before providers had converters of their own, when its state was
+// already mapped to the model, and drops anything else
+func recordedReviewEvent(review *github.PullRequestReview) *model.ReviewEvent {
+	switch state := review.GetState(); state {
+	case model.ReviewStateApproved, model.ReviewStateChangesRequested, model.ReviewStateCommented:
+		return newReviewEvent(review, state)
+	default:
+		return nil
+	}
+}
+
+// Converts a close or reopen event recorded before providers had converters of their own, when
+// its type was already mapped to the model, and drops anything else
+func recordedTimelineEvent(event *github.IssueEvent) *model.TimelineEvent {
+	switch eventType := event.GetEvent(); eventType {
+	case model.TimelineEventClosed, model.TimelineEventReopened:
+		return newTimelineEvent(event, eventType)
+	default:
+		return nil
+	}
+}
+
+// Converts a close or reopen event with the type its provider's status maps to
+func newTimelineEvent(event *github.IssueEvent, eventType string) *model.TimelineEvent {
+	return &model.TimelineEvent{
+		Type:      eventType,
+		Actor:     event.GetActor().GetLogin(),
+		CreatedAt: event.GetCreatedAt().Time,
+	}
+}
+
+// Converts a review thread, which providers report in the same form
+func newReviewThread(thread *ReviewThread) *model.ReviewThread {
+	return &model.ReviewThread{
+		ID:        thread.ID,
+		Path:      thread.Path,
+		Author:    thread.Author,
+		CreatedAt: thread.CreatedAt,
+		Resolved:  thread.Resolved,
+	}
+}
+
+// Converts each item of a provider response, keeping whatever was fetched before an error and
+// dropping the items the conversion has no place for
+func convertAll[T, U any](items []*T, convert func(*T) *U) []*U {
+	if items == nil {
+		return nil
+	}
+	converted := make([]*U, 0, len(items))
+	for _, item := range items {
+		if result := convert(item); result != nil {
+			converted = append(converted, result)
+		}
+	}
+	return converted
+}
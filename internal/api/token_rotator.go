@@ -0,0 +1,99 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Rate limit left on a token at which the next request switches to a fresher token,
+// unless the request budget reserve is larger
+const tokenRotationReserve = 100
+
+// Rate limit state of one of several tokens
+type rotatedToken struct {
+	token     string
+	remaining int // -1 until the API reports a rate limit for this token
+	reset     time.Time
+}
+
+// Returns the requests left on the token, treating an unreported or reset limit as unlimited
+func (t *rotatedToken) available() int {
+	if t.remaining < 0 || (!t.reset.IsZero() && time.Now().After(t.reset)) {
+		return math.MaxInt
+	}
+	return t.remaining
+}
+
+// Spreads requests over several tokens, staying on one token until its rate limit
+// runs low and then switching to the token with the most requests left
+type tokenRotator struct {
+	tokens  []*rotatedToken
+	current int
+	logger  *utils.Logger
+}
+
+// Initializes token rotator starting with the first token
+func newTokenRotator(tokens []string, logger *utils.Logger) *tokenRotator {
+	rotator := &tokenRotator{logger: logger}
+	for _, token := range tokens {
+		rotator.tokens = append(rotator.tokens, &rotatedToken{
+			token:     token,
+			remaining: -1,
+		})
+	}
+	return rotator
+}
+
+// Returns the token for the next request, switching tokens when the current one
+// is within the reserve of its rate limit
+func (r *tokenRotator) next(reserve int) *rotatedToken {
+	current := r.tokens[r.current]
+	if current.available() > max(reserve, tokenRotationReserve) {
+		return current
+	}
+
+	best := r.current
+	for i, token := range r.tokens {
+		if token.available() > r.tokens[best].available() {
+			best = i
+		}
+	}
+	if best != r.current {
+		// Tokens are identified by position so they never appear in logs
+		r.logger.Info("Switching to token %d of %d (%d requests left on token %d)", best+1, len(r.tokens), current.remaining, r.current+1)
+		r.current = best
+	}
+	return r.tokens[r.current]
+}
+
+// Reports whether another token has requests left, so a request refused by the rate limit can be retried
+func (r *tokenRotator) canRotate() bool {
+	for i, token := range r.tokens {
+		if i != r.current && token.available() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the requests left across all tokens that have reported a rate limit, or -1 if none has
+func (r *tokenRotator) remaining() int {
+	total := -1
+	for _, token := range r.tokens {
+		if token.remaining < 0 {
+			continue
+		}
+		total = max(total, 0) + token.remaining
+	}
+	return total
+}
+
+// Returns a copy of the request authenticated with the token
+func authorize(req *http.Request, token *rotatedToken) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.token)
+	return req
+}
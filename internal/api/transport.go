@@ -0,0 +1,106 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Network settings for reaching servers behind corporate proxies or with private CAs
+type TransportOptions struct {
+	ProxyURL           string // Proxy for all requests; defaults to the HTTPS_PROXY and HTTP_PROXY environment variables
+	CACertFile         string // PEM file of CA certificates to trust in addition to the system ones
+	InsecureSkipVerify bool   // Accept any server certificate
+	Headers            HeaderOptions
+}
+
+// Headers added to every API request, for proxies and audit policies that require them
+type HeaderOptions struct {
+	UserAgent string            `json:"user_agent"` // Replaces the default User-Agent
+	Headers   map[string]string `json:"headers"`    // Extra headers by name
+}
+
+// Headers set by the tool itself, which extra headers must not replace
+var reservedHeaders = []string{"Authorization", "Host", "User-Agent"}
+
+// Checks that the headers are well formed and leave the tool's own headers alone
+func (o HeaderOptions) Validate() error {
+	if strings.ContainsAny(o.UserAgent, "\r\n") {
+		return fmt.Errorf("http.user_agent must be a single line")
+	}
+	for name, value := range o.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q in http.headers", name)
+		}
+		for _, reserved := range reservedHeaders {
+			if http.CanonicalHeaderKey(name) == reserved {
+				return fmt.Errorf("http.headers cannot set %s; use http.user_agent or the token flags instead", reserved)
+			}
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %s in http.headers must be a single line", name)
+		}
+	}
+	return nil
+}
+
+// Reports whether any header is configured
+func (o HeaderOptions) IsEmpty() bool {
+	return o.UserAgent == "" && len(o.Headers) == 0
+}
+
+// Adds the configured headers to each request before passing it on
+type headerTransport struct {
+	base    http.RoundTripper
+	options HeaderOptions
+}
+
+// Sends a copy of the request with the configured headers set
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.options.UserAgent != "" {
+		req.Header.Set("User-Agent", t.options.UserAgent)
+	}
+	for name, value := range t.options.Headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Builds an HTTP transport from the default one with the proxy and TLS settings applied
+func (o TransportOptions) newTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if o.ProxyURL != "" {
+		proxyURL, err := url.Parse(o.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", o.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if o.CACertFile != "" || o.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}
+		if o.CACertFile != "" {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			data, err := os.ReadFile(o.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", o.CACertFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Returned for every request once the request budget has been used up
+var ErrRequestBudgetExhausted = errors.New("API request budget exhausted")
+
+// Snapshot of the API requests made by a provider
+type APIUsage struct {
+	Requests           int
+	RateLimitRemaining int  // -1 when the API has not reported a rate limit
+	BudgetExhausted    bool // Requests were refused because the budget was reached
+	Endpoints          []EndpointUsage
+}
+
+// Limits on the API requests a provider may make
+type RequestBudget struct {
+	MaxRequests  int // Maximum number of requests for the run; 0 means unlimited
+	MinRemaining int // Rate limit reserve to leave untouched; 0 means none
+}
+
+// Counts requests per endpoint, remembers the most recently reported rate limit, and enforces the request budget
+type requestTracker struct {
+	base               http.RoundTripper
+	transport          http.RoundTripper // Transport the response cache sends requests through
+	mu                 sync.Mutex
+	budget             RequestBudget
+	requests           int
+	rateLimitRemaining int
+	rateLimitReset     time.Time
+	exhausted          bool
+	rotator            *tokenRotator // Set when authenticating with several tokens
+	endpoints          map[string]*endpointStats
+	logger             *utils.Logger
+}
+
+// Initializes request tracker wrapping the default transport
+func newRequestTracker(logger *utils.Logger) *requestTracker {
+	return &requestTracker{
+		base:               http.DefaultTransport,
+		transport:          http.DefaultTransport,
+		rateLimitRemaining: -1,
+		endpoints:          make(map[string]*endpointStats),
+		logger:             logger,
+	}
+}
+
+// Sends the request within the budget and records the rate limit headers of the response
+func (t *requestTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Fresh cached responses cost no request, so they bypass the budget and pacing
+	if cache, ok := t.base.(*responseCache); ok {
+		if resp := cache.fresh(req); resp != nil {
+			t.endpoint(req).cacheHits++
+			return resp, nil
+		}
+	}
+
+	if t.rotator != nil {
+		return t.roundTripRotated(req)
+	}
+
+	if t.budgetReached() {
+		t.exhausted = true
+		return nil, ErrRequestBudgetExhausted
+	}
+	t.pace()
+
+	return t.send(req)
+}
+
+// Sends the request with the rotated token, retrying with another token when
+// the rate limit of the current one refuses a GET request
+func (t *requestTracker) roundTripRotated(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		// Budget checks and pacing apply to the token about to be used
+		token := t.rotator.next(t.budget.MinRemaining)
+		t.rateLimitRemaining, t.rateLimitReset = token.remaining, token.reset
+		if token.available() == math.MaxInt {
+			t.rateLimitRemaining, t.rateLimitReset = -1, time.Time{}
+		}
+
+		if t.budgetReached() {
+			t.exhausted = true
+			return nil, ErrRequestBudgetExhausted
+		}
+		t.pace()
+
+		resp, err := t.send(authorize(req, token))
+		token.remaining, token.reset = t.rateLimitRemaining, t.rateLimitReset
+
+		if err != nil || !isRateLimited(resp) || req.Method != http.MethodGet ||
+			attempt >= len(t.rotator.tokens) || !t.rotator.canRotate() {
+			return resp, err
+		}
+		if err := resp.Body.Close(); err != nil {
+			t.logger.Warn("Failed to close response body: %v", err)
+		}
+	}
+}
+
+// Sends the request, recording its endpoint, latency, response size, and the reported rate limit
+func (t *requestTracker) send(req *http.Request) (*http.Response, error) {
+	name := endpointName(req)
+	stats := t.endpoint(req)
+
+	startedAt := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(startedAt)
+
+	t.requests++
+	stats.requests++
+	stats.latencies = append(stats.latencies, latency)
+	if err != nil {
+		t.logger.Debug("%s failed after %dms: %v", name, latency.Milliseconds(), err)
+		return resp, err
+	}
+
+	t.recordRateLimit(resp)
+	if resp.Header.Get(cacheHitHeader) != "" {
+		stats.cacheHits++
+	} else {
+		resp.Body = &countingBody{ReadCloser: resp.Body, stats: stats}
+	}
+	if t.rateLimitRemaining >= 0 {
+		t.logger.Debug("%s returned %d in %dms (rate limit remaining: %d)", name, resp.StatusCode, latency.Milliseconds(), t.rateLimitRemaining)
+	} else {
+		t.logger.Debug("%s returned %d in %dms", name, resp.StatusCode, latency.Milliseconds())
+	}
+
+	return resp, nil
+}
+
+// Returns the statistics of the request's endpoint, creating them on first use
+func (t *requestTracker) endpoint(req *http.Request) *endpointStats {
+	name := endpointName(req)
+	stats, exists := t.endpoints[name]
+	if !exists {
+		stats = &endpointStats{}
+		t.endpoints[name] = stats
+	}
+	return stats
+}
+
+// Remembers the rate limit reported by a response
+func (t *requestTracker) recordRateLimit(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	// GitHub limits GraphQL separately, so its remaining requests say nothing about the REST limit
+	if resource := resp.Header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+
+	// GitHub and Gitea use the X- prefixed headers, GitLab the unprefixed ones
+	for _, prefix := range []string{"X-", ""} {
+		remaining, err := strconv.Atoi(resp.Header.Get(prefix + "RateLimit-Remaining"))
+		if err != nil {
+			continue
+		}
+		t.rateLimitRemaining = remaining
+		if reset, err := strconv.ParseInt(resp.Header.Get(prefix+"RateLimit-Reset"), 10, 64); err == nil {
+			t.rateLimitReset = time.Unix(reset, 0)
+		}
+		return
+	}
+}
+
+// Reports whether the response was refused because the primary rate limit is used up
+func isRateLimited(resp *http.Response) bool {
+	return (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// Reports whether another request would exceed the maximum or eat into the reserve
+func (t *requestTracker) budgetReached() bool {
+	if t.budget.MaxRequests > 0 && t.requests >= t.budget.MaxRequests {
+		return true
+	}
+	return t.budget.MinRemaining > 0 && t.rateLimitRemaining >= 0 && t.rateLimitRemaining <= t.budget.MinRemaining
+}
+
+// Spreads the requests left above the reserve evenly until the rate limit resets
+func (t *requestTracker) pace() {
+	if t.budget.MinRemaining <= 0 || t.rateLimitRemaining < 0 || t.rateLimitReset.IsZero() {
+		return
+	}
+
+	untilReset := time.Until(t.rateLimitReset)
+	available := t.rateLimitRemaining - t.budget.MinRemaining
+	if untilReset <= 0 || available <= 0 {
+		return
+	}
+
+	time.Sleep(untilReset / time.Duration(available))
+}
+
+// Replaces the request budget
+func (t *requestTracker) setBudget(budget RequestBudget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.budget = budget
+}
+
+// Returns the requests made so far
+func (t *requestTracker) usage() APIUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := APIUsage{
+		Requests:           t.requests,
+		RateLimitRemaining: t.rateLimitRemaining,
+		BudgetExhausted:    t.exhausted,
+		Endpoints:          summarizeEndpoints(t.endpoints),
+	}
+	if t.rotator != nil {
+		usage.RateLimitRemaining = t.rotator.remaining()
+	}
+	return usage
+}
+
+// Authenticates requests with several tokens, switching between them as their rate limits run low
+func (t *requestTracker) rotateTokens(tokens []string, logger *utils.Logger) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rotator = newTokenRotator(tokens, logger)
+}
+
+// Caches responses in the configured directory, serving fresh ones without a request
+// and revalidating stale ones
+func (t *requestTracker) enableCache(options CacheOptions) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cache, err := newResponseCache(t.transport, options, t.logger)
+	if err != nil {
+		return err
+	}
+
+	t.base = cache
+	return nil
+}
+
+// Sends requests through a transport with the given proxy, TLS, and header settings
+func (t *requestTracker) configureTransport(options TransportOptions) error {
+	baseTransport, err := options.newTransport()
+	if err != nil {
+		return err
+	}
+	var transport http.RoundTripper = baseTransport
+	if !options.Headers.IsEmpty() {
+		transport = &headerTransport{base: baseTransport, options: options.Headers}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.transport = transport
+	if cache, ok := t.base.(*responseCache); ok {
+		cache.base = transport
+	} else {
+		t.base = transport
+	}
+	return nil
+}
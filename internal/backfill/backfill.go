@@ -0,0 +1,113 @@
+package backfill
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// A calendar month of a backfill's date range
+type Chunk struct {
+	Name  string    // YYYY-MM
+	Start time.Time // Inclusive
+	End   time.Time // Inclusive
+}
+
+// Splits an inclusive date range into calendar months, clipping the first and last month to the range
+func MonthlyChunks(start, end time.Time) []Chunk {
+	var chunks []Chunk
+	for monthStart := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location()); !monthStart.After(end); monthStart = monthStart.AddDate(0, 1, 0) {
+		chunkStart := monthStart
+		if chunkStart.Before(start) {
+			chunkStart = start
+		}
+		chunkEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		chunks = append(chunks, Chunk{
+			Name:  monthStart.Format("2006-01"),
+			Start: chunkStart,
+			End:   chunkEnd,
+		})
+	}
+	return chunks
+}
+
+// Reports whether a time falls within the chunk
+func (c Chunk) Contains(t time.Time) bool {
+	return !t.Before(c.Start) && !t.After(c.End)
+}
+
+// Progress of a backfill, saved after every chunk so an interrupted run can resume
+type Checkpoint struct {
+	Repo            string    `json:"repo"`
+	StartDate       string    `json:"start_date"`
+	EndDate         string    `json:"end_date"`
+	DateField       string    `json:"date_field"`
+	CompletedChunks []string  `json:"completed_chunks"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Initializes an empty checkpoint for a backfill
+func NewCheckpoint(repo string, start, end time.Time, dateField string) *Checkpoint {
+	return &Checkpoint{
+		Repo:            repo,
+		StartDate:       start.Format("2006-01-02"),
+		EndDate:         end.Format("2006-01-02"),
+		DateField:       dateField,
+		CompletedChunks: []string{},
+	}
+}
+
+// Reads a checkpoint saved by an earlier run
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Reports whether the checkpoint was saved by a backfill of the same repository, range, and date field
+func (c *Checkpoint) Matches(other *Checkpoint) bool {
+	return c.Repo == other.Repo && c.StartDate == other.StartDate && c.EndDate == other.EndDate && c.DateField == other.DateField
+}
+
+// Reports whether the chunk was finished by an earlier run
+func (c *Checkpoint) IsCompleted(chunk Chunk) bool {
+	return slices.Contains(c.CompletedChunks, chunk.Name)
+}
+
+// Marks the chunk as finished
+func (c *Checkpoint) Complete(chunk Chunk) {
+	if !c.IsCompleted(chunk) {
+		c.CompletedChunks = append(c.CompletedChunks, chunk.Name)
+	}
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// Writes the checkpoint through a temporary file, so an interrupted write never leaves a corrupt checkpoint
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	temporaryPath := path + ".tmp"
+	if err := os.WriteFile(temporaryPath, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temporaryPath, path)
+}
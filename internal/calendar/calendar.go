@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Working days and hours of a team, used to measure durations in business hours
+type Calendar struct {
+	location     *time.Location
+	workdayStart time.Duration // Offset from midnight
+	workdayEnd   time.Duration
+	holidays     map[string]bool
+	country      string
+	countryYears map[int]map[string]bool
+}
+
+// Initializes a Monday-to-Friday calendar with the given working hours ("09:00-18:00")
+// in the given time zone
+func New(location *time.Location, workday string) (*Calendar, error) {
+	start, end, found := strings.Cut(workday, "-")
+	if !found {
+		return nil, fmt.Errorf("working hours must be in format 'HH:MM-HH:MM': %s", workday)
+	}
+	workdayStart, err := parseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	workdayEnd, err := parseClock(end)
+	if err != nil {
+		return nil, err
+	}
+	if workdayEnd <= workdayStart {
+		return nil, fmt.Errorf("working hours must end after they start: %s", workday)
+	}
+
+	return &Calendar{
+		location:     location,
+		workdayStart: workdayStart,
+		workdayEnd:   workdayEnd,
+		holidays:     make(map[string]bool),
+		countryYears: make(map[int]map[string]bool),
+	}, nil
+}
+
+// Adds the public holidays of a country by ISO 3166 code
+func (c *Calendar) SetCountry(country string) error {
+	country = strings.ToUpper(country)
+	if _, exists := countryRules[country]; !exists {
+		return fmt.Errorf("unsupported holiday country: %s (supported: %s)", country, strings.Join(SupportedCountries(), ", "))
+	}
+	c.country = country
+	return nil
+}
+
+// Adds the holidays listed in a file, one YYYY-MM-DD date per line optionally followed by a name;
+// blank lines and lines starting with # are ignored
+func (c *Calendar) LoadHolidays(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})
+		if len(fields) == 0 {
+			return fmt.Errorf("%s:%d: missing holiday date", path, line)
+		}
+		date, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			return fmt.Errorf("%s:%d: invalid holiday date: %s", path, line, fields[0])
+		}
+		c.holidays[dateKey(date)] = true
+	}
+
+	return scanner.Err()
+}
+
+// Reports whether the date is a weekday that is not a holiday
+func (c *Calendar) IsWorkday(date time.Time) bool {
+	date = date.In(c.location)
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+
+	key := dateKey(date)
+	if c.holidays[key] {
+		return false
+	}
+	if c.country != "" {
+		// Holidays observed on the previous day can fall in the preceding year
+		return !c.countryHolidays(date.Year())[key] && !c.countryHolidays(date.Year() + 1)[key]
+	}
+	return true
+}
+
+// Returns the working hours between two times, negative if to is before from
+func (c *Calendar) BusinessHours(from, to time.Time) float64 {
+	if to.Before(from) {
+		return -c.BusinessHours(to, from)
+	}
+
+	from = from.In(c.location)
+	to = to.In(c.location)
+
+	var total time.Duration
+	year, month, day := from.Date()
+	for date := time.Date(year, month, day, 0, 0, 0, 0, c.location); !date.After(to); date = date.AddDate(0, 0, 1) {
+		if !c.IsWorkday(date) {
+			continue
+		}
+
+		// time.Date normalizes the clock across daylight saving transitions
+		opensAt := time.Date(date.Year(), date.Month(), date.Day(), 0, int(c.workdayStart.Minutes()), 0, 0, c.location)
+		closesAt := time.Date(date.Year(), date.Month(), date.Day(), 0, int(c.workdayEnd.Minutes()), 0, 0, c.location)
+		start := maxTime(opensAt, from)
+		end := minTime(closesAt, to)
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+
+	return total.Hours()
+}
+
+// Generates and caches the country's holidays for a year
+func (c *Calendar) countryHolidays(year int) map[string]bool {
+	holidays, exists := c.countryYears[year]
+	if !exists {
+		holidays = make(map[string]bool)
+		for _, date := range countryRules[c.country](year) {
+			holidays[dateKey(date)] = true
+		}
+		c.countryYears[year] = holidays
+	}
+	return holidays
+}
+
+// Parses a clock time of day such as 09:30 into an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day: %s", value)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Identifies a calendar date independent of time zone
+func dateKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
+
+// Returns the later of two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// Returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,133 @@
+package calendar
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Generates the public holidays of a country for a year, including substitute days off
+var countryRules = map[string]func(year int) []time.Time{
+	"JP": japaneseHolidays,
+	"US": usFederalHolidays,
+}
+
+// Returns the country codes with built-in holiday rules
+func SupportedCountries() []string {
+	countries := make([]string, 0, len(countryRules))
+	for country := range countryRules {
+		countries = append(countries, country)
+	}
+	slices.Sort(countries)
+	return countries
+}
+
+// Japanese national holidays under the current Act on National Holidays
+func japaneseHolidays(year int) []time.Time {
+	holidays := []time.Time{
+		date(year, time.January, 1),                          // New Year's Day
+		nthWeekday(year, time.January, time.Monday, 2),       // Coming of Age Day
+		date(year, time.February, 11),                        // National Foundation Day
+		date(year, time.February, 23),                        // Emperor's Birthday
+		date(year, time.March, vernalEquinoxDay(year)),       // Vernal Equinox Day
+		date(year, time.April, 29),                           // Showa Day
+		date(year, time.May, 3),                              // Constitution Memorial Day
+		date(year, time.May, 4),                              // Greenery Day
+		date(year, time.May, 5),                              // Children's Day
+		nthWeekday(year, time.July, time.Monday, 3),          // Marine Day
+		date(year, time.August, 11),                          // Mountain Day
+		nthWeekday(year, time.September, time.Monday, 3),     // Respect for the Aged Day
+		date(year, time.September, autumnalEquinoxDay(year)), // Autumnal Equinox Day
+		nthWeekday(year, time.October, time.Monday, 2),       // Sports Day
+		date(year, time.November, 3),                         // Culture Day
+		date(year, time.November, 23),                        // Labor Thanksgiving Day
+	}
+
+	isHoliday := func(day time.Time) bool {
+		return slices.ContainsFunc(holidays, day.Equal)
+	}
+
+	// A holiday on a Sunday moves to the next day that is not already a holiday
+	var substitutes []time.Time
+	for _, holiday := range holidays {
+		if holiday.Weekday() != time.Sunday {
+			continue
+		}
+		substitute := holiday.AddDate(0, 0, 1)
+		for isHoliday(substitute) {
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+		substitutes = append(substitutes, substitute)
+	}
+
+	// A day sandwiched between two holidays is a citizens' holiday
+	var bridges []time.Time
+	for _, holiday := range holidays {
+		bridge := holiday.AddDate(0, 0, 1)
+		if !isHoliday(bridge) && bridge.Weekday() != time.Sunday && isHoliday(bridge.AddDate(0, 0, 1)) {
+			bridges = append(bridges, bridge)
+		}
+	}
+
+	return append(append(holidays, substitutes...), bridges...)
+}
+
+// US federal holidays, observed on the Friday before or Monday after when they fall on a weekend
+func usFederalHolidays(year int) []time.Time {
+	fixed := []time.Time{
+		date(year, time.January, 1),   // New Year's Day
+		date(year, time.June, 19),     // Juneteenth
+		date(year, time.July, 4),      // Independence Day
+		date(year, time.November, 11), // Veterans Day
+		date(year, time.December, 25), // Christmas Day
+	}
+
+	var holidays []time.Time
+	for _, holiday := range fixed {
+		switch holiday.Weekday() {
+		case time.Saturday:
+			holiday = holiday.AddDate(0, 0, -1)
+		case time.Sunday:
+			holiday = holiday.AddDate(0, 0, 1)
+		}
+		holidays = append(holidays, holiday)
+	}
+
+	return append(holidays,
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Washington's Birthday
+		lastWeekday(year, time.May, time.Monday),          // Memorial Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.October, time.Monday, 2),    // Columbus Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving Day
+	)
+}
+
+// Approximates the vernal equinox day in Japan, valid from 1980 to 2099
+func vernalEquinoxDay(year int) int {
+	return int(math.Floor(20.8431 + 0.242194*float64(year-1980) - math.Floor(float64(year-1980)/4)))
+}
+
+// Approximates the autumnal equinox day in Japan, valid from 1980 to 2099
+func autumnalEquinoxDay(year int) int {
+	return int(math.Floor(23.2488 + 0.242194*float64(year-1980) - math.Floor(float64(year-1980)/4)))
+}
+
+// Returns midnight UTC of a date
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Returns the nth occurrence of a weekday in a month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// Returns the last occurrence of a weekday in a month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/agreement"
+	"github.com/fukuchancat/github-pr-metrics/internal/alert"
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/notify"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+)
+
+// Settings read from the JSON file given with --config
+type Config struct {
+	CSV               output.CSVOptions               `json:"csv"`
+	Leaderboard       output.LeaderboardOptions       `json:"leaderboard"`
+	Teams             output.Teams                    `json:"teams"`
+	Notify            notify.Options                  `json:"notify"`
+	Alerts            []alert.Rule                    `json:"alerts"`
+	SLOs              []metrics.SLO                   `json:"slos"`
+	WorkingAgreements []agreement.Agreement           `json:"working_agreements"`
+	TestFiles         metrics.TestFileOptions         `json:"test_files"`
+	SizeExclusions    metrics.SizeExclusionOptions    `json:"size_exclusions"`
+	Categories        []metrics.CategoryRule          `json:"categories"`
+	IssueKeys         metrics.IssueKeyOptions         `json:"issue_keys"`
+	DependencyUpdates metrics.DependencyUpdateOptions `json:"dependency_updates"`
+	HTTP              api.HeaderOptions               `json:"http"`
+}
+
+// Reads and validates a JSON config file, rejecting unknown fields to catch typos
+func Load(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var config Config
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+
+	if err := config.CSV.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.Leaderboard.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.Teams.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.Notify.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	for i := range config.Alerts {
+		if err := config.Alerts[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %v", path, err)
+		}
+	}
+	if err := config.TestFiles.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.SizeExclusions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.DependencyUpdates.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.IssueKeys.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	if err := config.HTTP.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	for _, rule := range config.Categories {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %v", path, err)
+		}
+	}
+	for _, slo := range config.SLOs {
+		if err := slo.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %v", path, err)
+		}
+	}
+	for i := range config.WorkingAgreements {
+		if err := config.WorkingAgreements[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %v", path, err)
+		}
+	}
+
+	return &config, nil
+}
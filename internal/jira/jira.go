@@ -0,0 +1,124 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+const (
+	defaultTokenEnv = "JIRA_TOKEN"
+	requestTimeout  = 30 * time.Second
+	timeLayout      = "2006-01-02T15:04:05.000-0700" // Layout of the timestamps in Jira responses
+)
+
+// Jira instance the creation times of issues are read from
+type Options struct {
+	BaseURL  string `json:"base_url"`  // For example https://example.atlassian.net
+	Username string `json:"username"`  // Account email for Jira Cloud; leave empty to send the token as a bearer token to Jira Server or Data Center
+	TokenEnv string `json:"token_env"` // Environment variable holding the API token; defaults to JIRA_TOKEN
+}
+
+// Validates the base URL
+func (o *Options) Validate() error {
+	if parsed, err := url.Parse(o.BaseURL); err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid Jira base URL %q", o.BaseURL)
+	}
+	return nil
+}
+
+// Reads issues from the Jira REST API, requesting each issue at most once
+type Client struct {
+	baseURL    string
+	username   string
+	token      string
+	httpClient *http.Client
+	createdAt  map[string]time.Time
+	logger     *utils.Logger
+}
+
+// Initializes client with the token read from the configured environment variable
+func NewClient(options Options, logger *utils.Logger) (*Client, error) {
+	tokenEnv := options.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = defaultTokenEnv
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", tokenEnv)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(options.BaseURL, "/"),
+		username:   options.Username,
+		token:      token,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		createdAt:  make(map[string]time.Time),
+		logger:     logger,
+	}, nil
+}
+
+// Returns when the issue was created, or the zero time if no issue has the key,
+// since keys are extracted with a pattern that can match other text
+func (c *Client) GetIssueCreatedAt(key string) (time.Time, error) {
+	if createdAt, exists := c.createdAt[key]; exists {
+		return createdAt, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/rest/api/2/issue/"+url.PathEscape(key)+"?fields=created", nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	c.logger.Debug("Fetching Jira issue %s", key)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.createdAt[key] = time.Time{}
+		return time.Time{}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return time.Time{}, &utils.APIError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+
+	var issue struct {
+		Fields struct {
+			Created string `json:"created"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode Jira issue %s: %v", key, err)
+	}
+	createdAt, err := time.Parse(timeLayout, issue.Fields.Created)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid creation time of Jira issue %s: %v", key, err)
+	}
+
+	c.createdAt[key] = createdAt.UTC()
+	return createdAt.UTC(), nil
+}
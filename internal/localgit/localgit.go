@@ -0,0 +1,190 @@
+package localgit
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Local clone used for commit-level analysis that the API cannot provide cheaply
+type Repository struct {
+	dir    string
+	logger *utils.Logger
+}
+
+// Changes of one file between two commits
+type FileChange struct {
+	Path      string
+	Additions int
+	Deletions int
+	Renamed   bool
+}
+
+// Changes of a PR computed from its commits
+type Analysis struct {
+	Files            []FileChange
+	TouchedFunctions int // Distinct enclosing functions named in hunk headers
+}
+
+// Opens the clone at dir, cloning cloneURL into it first if the directory does not exist,
+// and fetches the remote so recent PRs can be analyzed
+func Open(dir, cloneURL string, logger *utils.Logger) (*Repository, error) {
+	repository := &Repository{
+		dir:    dir,
+		logger: logger,
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if cloneURL == "" {
+			return nil, fmt.Errorf("%s does not exist and no clone URL is known", dir)
+		}
+		logger.Info("Cloning %s into %s", cloneURL, dir)
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return nil, err
+		}
+		if _, err := runGit("", "clone", "--quiet", "--no-checkout", cloneURL, dir); err != nil {
+			return nil, err
+		}
+		return repository, nil
+	}
+
+	if _, err := repository.git("rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%s is not a git repository: %v", dir, err)
+	}
+	logger.Info("Fetching into local clone %s", dir)
+	if _, err := repository.git("fetch", "--quiet", "origin"); err != nil {
+		return nil, err
+	}
+	return repository, nil
+}
+
+// Makes sure a commit is present, fetching it by SHA or through the given ref if needed
+func (r *Repository) Ensure(sha string, refs ...string) error {
+	if r.hasCommit(sha) {
+		return nil
+	}
+
+	r.logger.Debug("Fetching commit %s", sha)
+	if _, err := r.git("fetch", "--quiet", "origin", sha); err == nil && r.hasCommit(sha) {
+		return nil
+	}
+	for _, ref := range refs {
+		if _, err := r.git("fetch", "--quiet", "origin", ref); err == nil && r.hasCommit(sha) {
+			return nil
+		}
+	}
+	return fmt.Errorf("commit %s is not in the local clone and could not be fetched", sha)
+}
+
+// Reports whether the commit is present in the clone
+func (r *Repository) hasCommit(sha string) bool {
+	_, err := r.git("cat-file", "-e", sha+"^{commit}")
+	return err == nil
+}
+
+// Compares the head with its merge base with the base commit, detecting renames
+func (r *Repository) Analyze(base, head string) (*Analysis, error) {
+	revisions := base + "..." + head
+
+	numstat, err := r.git("diff", "--numstat", "-z", "-M", revisions)
+	if err != nil {
+		return nil, err
+	}
+	files, err := parseNumstat(numstat)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := r.git("diff", "-M", "-U0", "--no-color", revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Analysis{
+		Files:            files,
+		TouchedFunctions: countTouchedFunctions(patch),
+	}, nil
+}
+
+// Parses NUL-separated numstat output; renames list the old and new path as separate fields
+func parseNumstat(output []byte) ([]FileChange, error) {
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	var files []FileChange
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "" {
+			continue
+		}
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected numstat line: %q", fields[i])
+		}
+
+		file := FileChange{Path: parts[2]}
+		// Binary files report "-" for both counts
+		file.Additions, _ = strconv.Atoi(parts[0])
+		file.Deletions, _ = strconv.Atoi(parts[1])
+		if file.Path == "" {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("truncated numstat rename: %q", fields[i])
+			}
+			file.Path = fields[i+2]
+			file.Renamed = true
+			i += 2
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Counts the distinct functions git names in the hunk headers of a patch, per file
+func countTouchedFunctions(patch []byte) int {
+	touched := make(map[string]bool)
+	file := ""
+	scanner := bufio.NewScanner(bytes.NewReader(patch))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(line, "+++ ")
+		case strings.HasPrefix(line, "@@ "):
+			// @@ -start,count +start,count @@ enclosing function
+			end := strings.Index(line[3:], " @@")
+			if end < 0 {
+				continue
+			}
+			function := strings.TrimSpace(line[3+end+3:])
+			if function != "" {
+				touched[file+"\x00"+function] = true
+			}
+		}
+	}
+	return len(touched)
+}
+
+// Runs git in the clone and returns its standard output
+func (r *Repository) git(args ...string) ([]byte, error) {
+	return runGit(r.dir, args...)
+}
+
+// Runs git in a directory, or the current one if empty, and returns its standard output
+func runGit(dir string, args ...string) ([]byte, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"sort"
+)
+
+// Values a statAccumulator keeps for an exact median before switching to an estimate. Periods
+// rarely have this many merged PRs, so medians of ordinary runs stay exact.
+const exactMedianLimit = 4096
+
+// Running count, sum, and median of one metric. The median is exact up to exactMedianLimit
+// values and estimated with the P² algorithm beyond, so memory stays bounded however many PRs
+// are aggregated.
+type statAccumulator struct {
+	count  int
+	sum    float64
+	values []float64    // Kept until exactMedianLimit is exceeded
+	median *p2Estimator // Replaces values once exactMedianLimit is exceeded
+}
+
+// Adds a value
+func (a *statAccumulator) add(value float64) {
+	a.count++
+	a.sum += value
+
+	if a.median != nil {
+		a.median.add(value)
+		return
+	}
+	a.values = append(a.values, value)
+	if len(a.values) > exactMedianLimit {
+		a.median = newP2Estimator(0.5, a.values)
+		a.values = nil
+	}
+}
+
+// Adds a value only if it is positive, for metrics where zero means the value is unknown
+func (a *statAccumulator) addPositive(value float64) {
+	if value > 0 {
+		a.add(value)
+	}
+}
+
+// Returns the average of the values, or zero without values
+func (a *statAccumulator) mean() float64 {
+	if a.count == 0 {
+		return 0
+	}
+	return a.sum / float64(a.count)
+}
+
+// Returns the median of the values, or zero without values
+func (a *statAccumulator) medianValue() float64 {
+	if a.median != nil {
+		return a.median.quantile()
+	}
+	return calculateMedianFloat(a.values)
+}
+
+// Running average of one metric weighted by another, such as lead time weighted by PR size
+type weightedAccumulator struct {
+	weightedSum float64
+	weight      float64
+}
+
+// Adds a value with its weight, skipping non-positive values, which mean the value is unknown
+func (a *weightedAccumulator) addPositive(value, weight float64) {
+	if value > 0 && weight > 0 {
+		a.weightedSum += value * weight
+		a.weight += weight
+	}
+}
+
+// Returns the weighted average of the values, or zero without weight
+func (a *weightedAccumulator) mean() float64 {
+	if a.weight == 0 {
+		return 0
+	}
+	return a.weightedSum / a.weight
+}
+
+// Estimates a quantile in constant memory with the P² algorithm of Jain and Chlamtac, which
+// moves five markers toward the minimum, the maximum, the quantile, and halfway between them
+type p2Estimator struct {
+	heights   [5]float64
+	positions [5]float64
+	desired   [5]float64
+	increment [5]float64
+}
+
+// Initializes the estimator with the first values observed, which must be at least five
+func newP2Estimator(quantile float64, initial []float64) *p2Estimator {
+	estimator := &p2Estimator{
+		positions: [5]float64{1, 2, 3, 4, 5},
+		desired:   [5]float64{1, 1 + 2*quantile, 1 + 4*quantile, 3 + 2*quantile, 5},
+		increment: [5]float64{0, quantile / 2, quantile, (1 + quantile) / 2, 1},
+	}
+	copy(estimator.heights[:], initial[:5])
+	sort.Float64s(estimator.heights[:])
+
+	for _, value := range initial[5:] {
+		estimator.add(value)
+	}
+	return estimator
+}
+
+// Adds a value, adjusting the markers that drifted from their desired positions
+func (e *p2Estimator) add(value float64) {
+	var cell int
+	switch {
+	case value < e.heights[0]:
+		e.heights[0] = value
+		cell = 0
+	case value >= e.heights[4]:
+		e.heights[4] = value
+		cell = 3
+	default:
+		for cell = 0; cell < 3 && value >= e.heights[cell+1]; cell++ {
+		}
+	}
+
+	for i := cell + 1; i < 5; i++ {
+		e.positions[i]++
+	}
+	for i := range e.desired {
+		e.desired[i] += e.increment[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		drift := e.desired[i] - e.positions[i]
+		if (drift >= 1 && e.positions[i+1]-e.positions[i] > 1) || (drift <= -1 && e.positions[i-1]-e.positions[i] < -1) {
+			step := 1.0
+			if drift < 0 {
+				step = -1
+			}
+			height := e.parabolic(i, step)
+			if height <= e.heights[i-1] || height >= e.heights[i+1] {
+				height = e.linear(i, step)
+			}
+			e.heights[i] = height
+			e.positions[i] += step
+		}
+	}
+}
+
+// Predicts the height of a moved marker from its neighbors with a piecewise parabola
+func (e *p2Estimator) parabolic(i int, step float64) float64 {
+	return e.heights[i] + step/(e.positions[i+1]-e.positions[i-1])*
+		((e.positions[i]-e.positions[i-1]+step)*(e.heights[i+1]-e.heights[i])/(e.positions[i+1]-e.positions[i])+
+			(e.positions[i+1]-e.positions[i]-step)*(e.heights[i]-e.heights[i-1])/(e.positions[i]-e.positions[i-1]))
+}
+
+// Predicts the height of a moved marker by linear interpolation toward the neighbor it moves to
+func (e *p2Estimator) linear(i int, step float64) float64 {
+	neighbor := i + int(step)
+	return e.heights[i] + step*(e.heights[neighbor]-e.heights[i])/(e.positions[neighbor]-e.positions[i])
+}
+
+// Returns the estimated quantile
+func (e *p2Estimator) quantile() float64 {
+	return e.heights[2]
+}
@@ -1,8 +1,9 @@
 package metrics
 
 import (
-	"fmt"
+	"runtime"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/fukuchancat/github-pr-metrics/internal/api"
@@ -24,326 +25,415 @@ func NewAggregatedMetricsCalculator(logger *utils.Logger) *AggregatedMetricsCalc
 // Groups PRs by ISO week and computes averages and medians
 func (c *AggregatedMetricsCalculator) CalculateWeeklyAggregatedMetrics(prMetrics []*api.PRMetrics) ([]*api.AggregatedMetrics, error) {
 	c.logger.Info("Calculating weekly aggregated metrics")
+	weeklyMetrics := c.calculatePeriodMetrics(prMetrics, GranularityWeek)
+	c.logger.Info("Successfully calculated weekly aggregated metrics for %d weeks", len(weeklyMetrics))
+	return weeklyMetrics, nil
+}
 
-	// Group PRs by week
-	weeklyPRs := make(map[string][]*api.PRMetrics)
-	weeklyStartDates := make(map[string]time.Time)
-	weeklyEndDates := make(map[string]time.Time)
+// Groups PRs by calendar month and computes statistical summaries
+func (c *AggregatedMetricsCalculator) CalculateMonthlyAggregatedMetrics(prMetrics []*api.PRMetrics) ([]*api.AggregatedMetrics, error) {
+	c.logger.Info("Calculating monthly aggregated metrics")
+	monthlyMetrics := c.calculatePeriodMetrics(prMetrics, GranularityMonth)
+	c.logger.Info("Successfully calculated monthly aggregated metrics for %d months", len(monthlyMetrics))
+	return monthlyMetrics, nil
+}
+
+// Groups merged PRs by the period of their merge and aggregates the periods in parallel,
+// one worker per CPU, returning them sorted by period
+func (c *AggregatedMetricsCalculator) calculatePeriodMetrics(prMetrics []*api.PRMetrics, granularity string) []*api.AggregatedMetrics {
+	type periodGroup struct {
+		period    string
+		startDate time.Time
+		endDate   time.Time
+		prs       []*api.PRMetrics
+	}
 
+	groups := make(map[string]*periodGroup)
 	for _, pr := range prMetrics {
 		// Skip PRs that haven't been merged
 		if pr.MergedAt.IsZero() {
 			continue
 		}
 
-		// Get the week number (ISO week)
-		year, week := pr.MergedAt.ISOWeek()
-		weekKey := fmt.Sprintf("%d-W%02d", year, week)
-
-		// Calculate the start and end date of the week
-		// ISO week starts on Monday
-		startOfWeek := getStartOfISOWeek(pr.MergedAt)
-		endOfWeek := startOfWeek.AddDate(0, 0, 6) // End of week (Sunday)
-
-		if _, exists := weeklyPRs[weekKey]; !exists {
-			weeklyPRs[weekKey] = []*api.PRMetrics{}
-			weeklyStartDates[weekKey] = startOfWeek
-			weeklyEndDates[weekKey] = endOfWeek
+		period, startDate, endDate := CalendarPeriod(pr.MergedAt, granularity)
+		group, exists := groups[period]
+		if !exists {
+			group = &periodGroup{period: period, startDate: startDate, endDate: endDate}
+			groups[period] = group
 		}
-
-		weeklyPRs[weekKey] = append(weeklyPRs[weekKey], pr)
+		group.prs = append(group.prs, pr)
 	}
 
-	// Calculate aggregated metrics for each week
-	var weeklyMetrics []*api.AggregatedMetrics
-
-	for weekKey, prs := range weeklyPRs {
-		aggregated := c.calculateAggregatedMetrics(weekKey, weeklyStartDates[weekKey], weeklyEndDates[weekKey], prs)
-		weeklyMetrics = append(weeklyMetrics, aggregated)
+	periods := make([]*periodGroup, 0, len(groups))
+	for _, group := range groups {
+		periods = append(periods, group)
 	}
-
-	// Sort by period
-	sort.Slice(weeklyMetrics, func(i, j int) bool {
-		return weeklyMetrics[i].Period < weeklyMetrics[j].Period
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].period < periods[j].period
 	})
 
-	c.logger.Info("Successfully calculated weekly aggregated metrics for %d weeks", len(weeklyMetrics))
-	return weeklyMetrics, nil
-}
+	// Each worker writes only the slots of the periods it takes, so no locking is needed
+	periodMetrics := make([]*api.AggregatedMetrics, len(periods))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(periods)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				group := periods[i]
+				periodMetrics[i] = c.calculateAggregatedMetrics(group.period, group.startDate, group.endDate, group.prs)
+			}
+		}()
+	}
+	for i := range periods {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 
-// Groups PRs by calendar month and computes statistical summaries
-func (c *AggregatedMetricsCalculator) CalculateMonthlyAggregatedMetrics(prMetrics []*api.PRMetrics) ([]*api.AggregatedMetrics, error) {
-	c.logger.Info("Calculating monthly aggregated metrics")
+	return periodMetrics
+}
 
-	// Group PRs by month
-	monthlyPRs := make(map[string][]*api.PRMetrics)
-	monthlyStartDates := make(map[string]time.Time)
-	monthlyEndDates := make(map[string]time.Time)
+// Computes averages and medians over all merged PRs, spanning the first to the last merge
+func (c *AggregatedMetricsCalculator) CalculateOverallAggregatedMetrics(prMetrics []*api.PRMetrics) *api.AggregatedMetrics {
+	var startDate, endDate time.Time
+	accumulator := &aggregateAccumulator{}
 
 	for _, pr := range prMetrics {
-		// Skip PRs that haven't been merged
 		if pr.MergedAt.IsZero() {
 			continue
 		}
+		accumulator.add(pr)
 
-		// Get the month
-		year, month, _ := pr.MergedAt.Date()
-		monthKey := fmt.Sprintf("%d-%02d", year, month)
-
-		// Calculate the start and end date of the month
-		startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, pr.MergedAt.Location())
-		endOfMonth := startOfMonth.AddDate(0, 1, -1) // Last day of month
-
-		if _, exists := monthlyPRs[monthKey]; !exists {
-			monthlyPRs[monthKey] = []*api.PRMetrics{}
-			monthlyStartDates[monthKey] = startOfMonth
-			monthlyEndDates[monthKey] = endOfMonth
+		if startDate.IsZero() || pr.MergedAt.Before(startDate) {
+			startDate = pr.MergedAt
+		}
+		if pr.MergedAt.After(endDate) {
+			endDate = pr.MergedAt
 		}
-
-		monthlyPRs[monthKey] = append(monthlyPRs[monthKey], pr)
-	}
-
-	// Calculate aggregated metrics for each month
-	var monthlyMetrics []*api.AggregatedMetrics
-
-	for monthKey, prs := range monthlyPRs {
-		aggregated := c.calculateAggregatedMetrics(monthKey, monthlyStartDates[monthKey], monthlyEndDates[monthKey], prs)
-		monthlyMetrics = append(monthlyMetrics, aggregated)
 	}
 
-	// Sort by period
-	sort.Slice(monthlyMetrics, func(i, j int) bool {
-		return monthlyMetrics[i].Period < monthlyMetrics[j].Period
-	})
-
-	c.logger.Info("Successfully calculated monthly aggregated metrics for %d months", len(monthlyMetrics))
-	return monthlyMetrics, nil
+	return accumulator.result("All", startDate, endDate)
 }
 
 // Computes averages and medians for all metrics within a PR group
 func (c *AggregatedMetricsCalculator) calculateAggregatedMetrics(period string, startDate, endDate time.Time, prs []*api.PRMetrics) *api.AggregatedMetrics {
-	prCount := len(prs)
-	if prCount == 0 {
-		return &api.AggregatedMetrics{
-			Period:    period,
-			StartDate: startDate,
-			EndDate:   endDate,
-			PRCount:   0,
-		}
-	}
-
-	// Initialize sums and slices for median calculation
-	var (
-		sumCommitCount                int
-		sumCommentCount               int
-		sumReviewCount                int
-		sumApprovalCount              int
-		sumAdditions                  int
-		sumDeletions                  int
-		sumChangedFiles               int
-		sumCommitCountDuringPR        int
-		sumFirstCommitToCreateHours   float64
-		sumCreateToLastCommitHours    float64
-		sumFirstCommitToMergeHours    float64
-		sumLastCommitToMergeHours     float64
-		sumCreatedToFirstCommentHours float64
-		sumTimeToApprovalHours        float64
-		sumTotalPRLifetimeHours       float64
-		sumMaxNoCommentPeriodHours    float64
-		sumMaxNoCommitPeriodHours     float64
-		sumMaxNoActivityPeriodHours   float64
-
-		countFirstCommitToCreate   int
-		countCreateToLastCommit    int
-		countFirstCommitToMerge    int
-		countLastCommitToMerge     int
-		countCreatedToFirstComment int
-		countTimeToApproval        int
-		countTotalPRLifetime       int
-		countMaxNoCommentPeriod    int
-		countMaxNoCommitPeriod     int
-		countMaxNoActivityPeriod   int
-
-		commitCounts               []int
-		commentCounts              []int
-		reviewCounts               []int
-		approvalCounts             []int
-		additions                  []int
-		deletions                  []int
-		changedFiles               []int
-		commitCountsDuringPR       []int
-		firstCommitToCreateHours   []float64
-		createToLastCommitHours    []float64
-		firstCommitToMergeHours    []float64
-		lastCommitToMergeHours     []float64
-		createdToFirstCommentHours []float64
-		timeToApprovalHours        []float64
-		totalPRLifetimeHours       []float64
-		maxNoCommentPeriodHours    []float64
-		maxNoCommitPeriodHours     []float64
-		maxNoActivityPeriodHours   []float64
-	)
-
-	// Calculate sums and collect values for median calculation
+	accumulator := &aggregateAccumulator{}
 	for _, pr := range prs {
-		// Sums for averages
-		sumCommitCount += pr.CommitCount
-		sumCommentCount += pr.CommentCount
-		sumReviewCount += pr.ReviewCount
-		sumApprovalCount += pr.ApprovalCount
-		sumAdditions += pr.Additions
-		sumDeletions += pr.Deletions
-		sumChangedFiles += pr.ChangedFiles
-		sumCommitCountDuringPR += pr.CommitCountDuringPR
-
-		// Values for median calculation
-		commitCounts = append(commitCounts, pr.CommitCount)
-		commentCounts = append(commentCounts, pr.CommentCount)
-		reviewCounts = append(reviewCounts, pr.ReviewCount)
-		approvalCounts = append(approvalCounts, pr.ApprovalCount)
-		additions = append(additions, pr.Additions)
-		deletions = append(deletions, pr.Deletions)
-		changedFiles = append(changedFiles, pr.ChangedFiles)
-		commitCountsDuringPR = append(commitCountsDuringPR, pr.CommitCountDuringPR)
-
-		// Time metrics
-		if pr.FirstCommitToCreateHours > 0 {
-			sumFirstCommitToCreateHours += pr.FirstCommitToCreateHours
-			countFirstCommitToCreate++
-			firstCommitToCreateHours = append(firstCommitToCreateHours, pr.FirstCommitToCreateHours)
-		}
-
-		if pr.CreateToLastCommitHours > 0 {
-			sumCreateToLastCommitHours += pr.CreateToLastCommitHours
-			countCreateToLastCommit++
-			createToLastCommitHours = append(createToLastCommitHours, pr.CreateToLastCommitHours)
-		}
-
-		if pr.FirstCommitToMergeHours > 0 {
-			sumFirstCommitToMergeHours += pr.FirstCommitToMergeHours
-			countFirstCommitToMerge++
-			firstCommitToMergeHours = append(firstCommitToMergeHours, pr.FirstCommitToMergeHours)
-		}
-
-		if pr.LastCommitToMergeHours > 0 {
-			sumLastCommitToMergeHours += pr.LastCommitToMergeHours
-			countLastCommitToMerge++
-			lastCommitToMergeHours = append(lastCommitToMergeHours, pr.LastCommitToMergeHours)
-		}
+		accumulator.add(pr)
+	}
+	return accumulator.result(period, startDate, endDate)
+}
 
-		if pr.CreatedToFirstCommentHours > 0 {
-			sumCreatedToFirstCommentHours += pr.CreatedToFirstCommentHours
-			countCreatedToFirstComment++
-			createdToFirstCommentHours = append(createdToFirstCommentHours, pr.CreatedToFirstCommentHours)
-		}
+// Running totals of every aggregated metric, fed one PR at a time so a group of any size
+// is summarized in bounded memory
+type aggregateAccumulator struct {
+	prCount                int
+	selfMergedCount        int
+	unreviewedMergeCount   int
+	reopenedCount          int
+	requestedCount         int
+	requestedReviewedCount int
+	approvedCount          int
+	staleApprovalCount     int
+	baseSyncMergedCount    int
+	afterApprovalCount     int
+	mergedLines            int
+	authors                map[string]bool // Grows with the team rather than the PR count
+	compliantCount         int
+	flakyPRCount           int
+	flakyCheckRetries      int
+	nonCompliantCount      int
+	ownedPRCount           int
+	ownedApprovers         int // Approvers of owned PRs
+	ownedOwnerApprovers    int // Approvers of owned PRs who owned a changed file
+	fullyOwnerApproved     int
+
+	commitCount           statAccumulator
+	reviewCommentCount    statAccumulator
+	conversationComments  statAccumulator
+	reviewCount           statAccumulator
+	approvalCount         statAccumulator
+	approverCount         statAccumulator
+	codeOwnerApprovals    statAccumulator
+	additions             statAccumulator
+	deletions             statAccumulator
+	changedFiles          statAccumulator
+	commitCountDuringPR   statAccumulator
+	reviewCoveragePercent statAccumulator
+	baseSyncMergeCount    statAccumulator
+
+	firstCommitToCreateHours   statAccumulator
+	createToLastCommitHours    statAccumulator
+	firstCommitToMergeHours    statAccumulator
+	lastCommitToMergeHours     statAccumulator
+	createdToFirstCommentHours statAccumulator
+	timeToApprovalHours        statAccumulator
+	firstToLastApprovalHours   statAccumulator
+	totalPRLifetimeHours       statAccumulator
+	maxNoCommentPeriodHours    statAccumulator
+	maxNoCommitPeriodHours     statAccumulator
+	maxNoActivityPeriodHours   statAccumulator
+	authorResponseLatencyHours statAccumulator
+	codingHours                statAccumulator
+	waitingForReviewHours      statAccumulator
+	inReviewHours              statAccumulator
+	waitingToMergeHours        statAccumulator
+	testChangeRatio            statAccumulator
+	reReviewLatencyHours       statAccumulator
+	issueLeadTimeHours         statAccumulator
+	ciQueueHours               statAccumulator
+	codeOwnerApprovalCoverage  statAccumulator
+
+	sizeWeightedFirstCommitToMergeHours weightedAccumulator
+	sizeWeightedTotalPRLifetimeHours    weightedAccumulator
+}
 
-		if pr.TimeToApprovalHours > 0 {
-			sumTimeToApprovalHours += pr.TimeToApprovalHours
-			countTimeToApproval++
-			timeToApprovalHours = append(timeToApprovalHours, pr.TimeToApprovalHours)
-		}
+// Adds the metrics of a merged PR
+func (a *aggregateAccumulator) add(pr *api.PRMetrics) {
+	a.prCount++
+	a.mergedLines += pr.Additions + pr.Deletions
+	if a.authors == nil {
+		a.authors = make(map[string]bool)
+	}
+	a.authors[pr.Author] = true
 
-		if pr.TotalPRLifetimeHours > 0 {
-			sumTotalPRLifetimeHours += pr.TotalPRLifetimeHours
-			countTotalPRLifetime++
-			totalPRLifetimeHours = append(totalPRLifetimeHours, pr.TotalPRLifetimeHours)
+	// Compliance counts
+	if pr.SelfMerged {
+		a.selfMergedCount++
+	}
+	if pr.UnreviewedMerge {
+		a.unreviewedMergeCount++
+	}
+	if pr.ReopenCount > 0 {
+		a.reopenedCount++
+	}
+	if pr.ApprovalCount > 0 {
+		a.approvedCount++
+		if pr.StaleApprovalCount > 0 {
+			a.staleApprovalCount++
 		}
-
-		if pr.MaxNoCommentPeriodHours > 0 {
-			sumMaxNoCommentPeriodHours += pr.MaxNoCommentPeriodHours
-			countMaxNoCommentPeriod++
-			maxNoCommentPeriodHours = append(maxNoCommentPeriodHours, pr.MaxNoCommentPeriodHours)
+		if pr.CommitsAfterApproval > 0 {
+			a.afterApprovalCount++
 		}
-
-		if pr.MaxNoCommitPeriodHours > 0 {
-			sumMaxNoCommitPeriodHours += pr.MaxNoCommitPeriodHours
-			countMaxNoCommitPeriod++
-			maxNoCommitPeriodHours = append(maxNoCommitPeriodHours, pr.MaxNoCommitPeriodHours)
+	}
+	if pr.BaseSyncMergeCount > 0 {
+		a.baseSyncMergedCount++
+	}
+	if pr.FlakyCheckRetries > 0 {
+		a.flakyPRCount++
+		a.flakyCheckRetries += pr.FlakyCheckRetries
+	}
+	if pr.OwnedFileCount > 0 {
+		a.ownedPRCount++
+		a.ownedApprovers += pr.CodeOwnerApprovalCount + pr.NonOwnerApprovalCount
+		a.ownedOwnerApprovers += pr.CodeOwnerApprovalCount
+		a.codeOwnerApprovalCoverage.add(pr.CodeOwnerCoveragePercent)
+		if pr.CodeOwnerCoveragePercent >= 100 {
+			a.fullyOwnerApproved++
 		}
-
-		if pr.MaxNoActivityPeriodHours > 0 {
-			sumMaxNoActivityPeriodHours += pr.MaxNoActivityPeriodHours
-			countMaxNoActivityPeriod++
-			maxNoActivityPeriodHours = append(maxNoActivityPeriodHours, pr.MaxNoActivityPeriodHours)
+	}
+	if len(pr.RequestedReviewers) > 0 {
+		a.requestedCount++
+		if pr.RequestedReviewerReviewed {
+			a.requestedReviewedCount++
 		}
 	}
-
-	// Calculate averages and medians
-	metrics := &api.AggregatedMetrics{
-		Period:                 period,
-		StartDate:              startDate,
-		EndDate:                endDate,
-		PRCount:                prCount,
-		AvgCommitCount:         float64(sumCommitCount) / float64(prCount),
-		AvgCommentCount:        float64(sumCommentCount) / float64(prCount),
-		AvgReviewCount:         float64(sumReviewCount) / float64(prCount),
-		AvgApprovalCount:       float64(sumApprovalCount) / float64(prCount),
-		AvgAdditions:           float64(sumAdditions) / float64(prCount),
-		AvgDeletions:           float64(sumDeletions) / float64(prCount),
-		AvgChangedFiles:        float64(sumChangedFiles) / float64(prCount),
-		AvgCommitCountDuringPR: float64(sumCommitCountDuringPR) / float64(prCount),
-
-		// Calculate medians for count metrics
-		MedianCommitCount:         calculateMedianInt(commitCounts),
-		MedianCommentCount:        calculateMedianInt(commentCounts),
-		MedianReviewCount:         calculateMedianInt(reviewCounts),
-		MedianApprovalCount:       calculateMedianInt(approvalCounts),
-		MedianAdditions:           calculateMedianInt(additions),
-		MedianDeletions:           calculateMedianInt(deletions),
-		MedianChangedFiles:        calculateMedianInt(changedFiles),
-		MedianCommitCountDuringPR: calculateMedianInt(commitCountsDuringPR),
+	switch pr.ComplianceStatus {
+	case ComplianceStatusCompliant:
+		a.compliantCount++
+	case ComplianceStatusNonCompliant:
+		a.nonCompliantCount++
 	}
 
-	// Calculate averages for time metrics (only if we have valid data)
-	if countFirstCommitToCreate > 0 {
-		metrics.AvgFirstCommitToCreateHours = sumFirstCommitToCreateHours / float64(countFirstCommitToCreate)
-		metrics.MedianFirstCommitToCreateHours = calculateMedianFloat(firstCommitToCreateHours)
+	// Count metrics are meaningful even when zero, so every PR contributes
+	a.commitCount.add(float64(pr.CommitCount))
+	a.reviewCommentCount.add(float64(pr.ReviewCommentCount))
+	a.conversationComments.add(float64(pr.ConversationCommentCount))
+	a.reviewCount.add(float64(pr.ReviewCount))
+	a.approvalCount.add(float64(pr.ApprovalCount))
+	a.approverCount.add(float64(pr.ApproverCount))
+	a.codeOwnerApprovals.add(float64(pr.CodeOwnerApprovalCount))
+	a.additions.add(float64(pr.Additions))
+	a.deletions.add(float64(pr.Deletions))
+	a.changedFiles.add(float64(pr.ChangedFiles))
+	a.commitCountDuringPR.add(float64(pr.CommitCountDuringPR))
+	if pr.ReviewCoveragePercent != ReviewCoverageUnknown {
+		a.reviewCoveragePercent.add(pr.ReviewCoveragePercent)
 	}
-
-	if countCreateToLastCommit > 0 {
-		metrics.AvgCreateToLastCommitHours = sumCreateToLastCommitHours / float64(countCreateToLastCommit)
-		metrics.MedianCreateToLastCommitHours = calculateMedianFloat(createToLastCommitHours)
+	a.baseSyncMergeCount.add(float64(pr.BaseSyncMergeCount))
+
+	// Time metrics are zero when unknown
+	a.firstCommitToCreateHours.addPositive(pr.FirstCommitToCreateHours)
+	a.createToLastCommitHours.addPositive(pr.CreateToLastCommitHours)
+	a.firstCommitToMergeHours.addPositive(pr.FirstCommitToMergeHours)
+	a.lastCommitToMergeHours.addPositive(pr.LastCommitToMergeHours)
+	a.createdToFirstCommentHours.addPositive(pr.CreatedToFirstCommentHours)
+	a.timeToApprovalHours.addPositive(pr.TimeToApprovalHours)
+	a.totalPRLifetimeHours.addPositive(pr.TotalPRLifetimeHours)
+	a.maxNoCommentPeriodHours.addPositive(pr.MaxNoCommentPeriodHours)
+	a.maxNoCommitPeriodHours.addPositive(pr.MaxNoCommitPeriodHours)
+	a.maxNoActivityPeriodHours.addPositive(pr.MaxNoActivityPeriodHours)
+	a.authorResponseLatencyHours.addPositive(pr.AuthorResponseLatencyHours)
+	a.codingHours.addPositive(pr.CodingHours)
+	a.waitingForReviewHours.addPositive(pr.WaitingForReviewHours)
+	a.inReviewHours.addPositive(pr.InReviewHours)
+	a.waitingToMergeHours.addPositive(pr.WaitingToMergeHours)
+
+	// Weight lead times by the changed lines, so one large refactor is not drowned out by small PRs
+	size := float64(pr.Additions + pr.Deletions)
+	a.sizeWeightedFirstCommitToMergeHours.addPositive(pr.FirstCommitToMergeHours, size)
+	a.sizeWeightedTotalPRLifetimeHours.addPositive(pr.TotalPRLifetimeHours, size)
+
+	// Only PRs approved more than once have a span between approvals
+	a.firstToLastApprovalHours.addPositive(pr.FirstToLastApprovalHours)
+
+	// Only PRs referring to a known issue have a lead time
+	a.issueLeadTimeHours.addPositive(pr.IssueLeadTimeHours)
+
+	// Only PRs whose checks were fetched and started have a queue time
+	a.ciQueueHours.addPositive(pr.CIQueueHours)
+
+	// PRs without changed lines have no ratio
+	if pr.Additions+pr.Deletions > 0 {
+		a.testChangeRatio.add(pr.TestChangeRatio)
 	}
 
-	if countFirstCommitToMerge > 0 {
-		metrics.AvgFirstCommitToMergeHours = sumFirstCommitToMergeHours / float64(countFirstCommitToMerge)
-		metrics.MedianFirstCommitToMergeHours = calculateMedianFloat(firstCommitToMergeHours)
+	// Only PRs reviewed again after new commits have a re-review latency
+	if pr.ReReviewCount > 0 {
+		a.reReviewLatencyHours.add(pr.ReReviewLatencyHours)
 	}
+}
 
-	if countLastCommitToMerge > 0 {
-		metrics.AvgLastCommitToMergeHours = sumLastCommitToMergeHours / float64(countLastCommitToMerge)
-		metrics.MedianLastCommitToMergeHours = calculateMedianFloat(lastCommitToMergeHours)
+// Computes averages, medians, and percentages of the PRs added so far
+func (a *aggregateAccumulator) result(period string, startDate, endDate time.Time) *api.AggregatedMetrics {
+	if a.prCount == 0 {
+		return &api.AggregatedMetrics{
+			Period:    period,
+			StartDate: startDate,
+			EndDate:   endDate,
+			PRCount:   0,
+		}
 	}
 
-	if countCreatedToFirstComment > 0 {
-		metrics.AvgCreatedToFirstCommentHours = sumCreatedToFirstCommentHours / float64(countCreatedToFirstComment)
-		metrics.MedianCreatedToFirstCommentHours = calculateMedianFloat(createdToFirstCommentHours)
+	metrics := &api.AggregatedMetrics{
+		Period:                      period,
+		StartDate:                   startDate,
+		EndDate:                     endDate,
+		PRCount:                     a.prCount,
+		SelfMergedCount:             a.selfMergedCount,
+		SelfMergedPercent:           float64(a.selfMergedCount) / float64(a.prCount) * 100,
+		UnreviewedMergeCount:        a.unreviewedMergeCount,
+		UnreviewedMergePercent:      float64(a.unreviewedMergeCount) / float64(a.prCount) * 100,
+		AvgCommitCount:              a.commitCount.mean(),
+		AvgReviewCommentCount:       a.reviewCommentCount.mean(),
+		AvgConversationCommentCount: a.conversationComments.mean(),
+		AvgReviewCount:              a.reviewCount.mean(),
+		AvgApprovalCount:            a.approvalCount.mean(),
+		AvgApproverCount:            a.approverCount.mean(),
+		AvgCodeOwnerApprovalCount:   a.codeOwnerApprovals.mean(),
+		AvgAdditions:                a.additions.mean(),
+		AvgDeletions:                a.deletions.mean(),
+		AvgChangedFiles:             a.changedFiles.mean(),
+		AvgCommitCountDuringPR:      a.commitCountDuringPR.mean(),
+		AvgReviewCoveragePercent:    a.reviewCoveragePercent.mean(),
+
+		MedianCommitCount:              a.commitCount.medianValue(),
+		MedianReviewCommentCount:       a.reviewCommentCount.medianValue(),
+		MedianConversationCommentCount: a.conversationComments.medianValue(),
+		MedianReviewCount:              a.reviewCount.medianValue(),
+		MedianApprovalCount:            a.approvalCount.medianValue(),
+		MedianApproverCount:            a.approverCount.medianValue(),
+		MedianCodeOwnerApprovalCount:   a.codeOwnerApprovals.medianValue(),
+		MedianAdditions:                a.additions.medianValue(),
+		MedianDeletions:                a.deletions.medianValue(),
+		MedianChangedFiles:             a.changedFiles.medianValue(),
+		MedianCommitCountDuringPR:      a.commitCountDuringPR.medianValue(),
+		MedianReviewCoveragePercent:    a.reviewCoveragePercent.medianValue(),
+
+		// Time metrics average only the PRs where they are known, and stay zero without any
+		AvgFirstCommitToCreateHours:      a.firstCommitToCreateHours.mean(),
+		MedianFirstCommitToCreateHours:   a.firstCommitToCreateHours.medianValue(),
+		AvgCreateToLastCommitHours:       a.createToLastCommitHours.mean(),
+		MedianCreateToLastCommitHours:    a.createToLastCommitHours.medianValue(),
+		AvgFirstCommitToMergeHours:       a.firstCommitToMergeHours.mean(),
+		MedianFirstCommitToMergeHours:    a.firstCommitToMergeHours.medianValue(),
+		AvgLastCommitToMergeHours:        a.lastCommitToMergeHours.mean(),
+		MedianLastCommitToMergeHours:     a.lastCommitToMergeHours.medianValue(),
+		AvgCreatedToFirstCommentHours:    a.createdToFirstCommentHours.mean(),
+		MedianCreatedToFirstCommentHours: a.createdToFirstCommentHours.medianValue(),
+		AvgTimeToApprovalHours:           a.timeToApprovalHours.mean(),
+		MedianTimeToApprovalHours:        a.timeToApprovalHours.medianValue(),
+		AvgFirstToLastApprovalHours:      a.firstToLastApprovalHours.mean(),
+		MedianFirstToLastApprovalHours:   a.firstToLastApprovalHours.medianValue(),
+		AvgTotalPRLifetimeHours:          a.totalPRLifetimeHours.mean(),
+		MedianTotalPRLifetimeHours:       a.totalPRLifetimeHours.medianValue(),
+		AvgMaxNoCommentPeriodHours:       a.maxNoCommentPeriodHours.mean(),
+		MedianMaxNoCommentPeriodHours:    a.maxNoCommentPeriodHours.medianValue(),
+		AvgMaxNoCommitPeriodHours:        a.maxNoCommitPeriodHours.mean(),
+		MedianMaxNoCommitPeriodHours:     a.maxNoCommitPeriodHours.medianValue(),
+		AvgMaxNoActivityPeriodHours:      a.maxNoActivityPeriodHours.mean(),
+		MedianMaxNoActivityPeriodHours:   a.maxNoActivityPeriodHours.medianValue(),
+		AvgAuthorResponseLatencyHours:    a.authorResponseLatencyHours.mean(),
+		MedianAuthorResponseLatencyHours: a.authorResponseLatencyHours.medianValue(),
+		AvgCodingHours:                   a.codingHours.mean(),
+		MedianCodingHours:                a.codingHours.medianValue(),
+		AvgWaitingForReviewHours:         a.waitingForReviewHours.mean(),
+		MedianWaitingForReviewHours:      a.waitingForReviewHours.medianValue(),
+		AvgInReviewHours:                 a.inReviewHours.mean(),
+		MedianInReviewHours:              a.inReviewHours.medianValue(),
+		AvgWaitingToMergeHours:           a.waitingToMergeHours.mean(),
+		MedianWaitingToMergeHours:        a.waitingToMergeHours.medianValue(),
+		AvgTestChangeRatio:               a.testChangeRatio.mean(),
+		MedianTestChangeRatio:            a.testChangeRatio.medianValue(),
+		AvgReReviewLatencyHours:          a.reReviewLatencyHours.mean(),
+		MedianReReviewLatencyHours:       a.reReviewLatencyHours.medianValue(),
+		AvgIssueLeadTimeHours:            a.issueLeadTimeHours.mean(),
+		MedianIssueLeadTimeHours:         a.issueLeadTimeHours.medianValue(),
 	}
 
-	if countTimeToApproval > 0 {
-		metrics.AvgTimeToApprovalHours = sumTimeToApprovalHours / float64(countTimeToApproval)
-		metrics.MedianTimeToApprovalHours = calculateMedianFloat(timeToApprovalHours)
+	// Compliance percentage only covers PRs whose compliance could be determined
+	metrics.CompliantCount = a.compliantCount
+	metrics.NonCompliantCount = a.nonCompliantCount
+	if a.compliantCount+a.nonCompliantCount > 0 {
+		metrics.CompliantPercent = float64(a.compliantCount) / float64(a.compliantCount+a.nonCompliantCount) * 100
 	}
 
-	if countTotalPRLifetime > 0 {
-		metrics.AvgTotalPRLifetimeHours = sumTotalPRLifetimeHours / float64(countTotalPRLifetime)
-		metrics.MedianTotalPRLifetimeHours = calculateMedianFloat(totalPRLifetimeHours)
+	metrics.ReopenedCount = a.reopenedCount
+	metrics.ReopenedPercent = float64(a.reopenedCount) / float64(a.prCount) * 100
+	if a.requestedCount > 0 {
+		metrics.RequestedReviewerReviewedPercent = float64(a.requestedReviewedCount) / float64(a.requestedCount) * 100
 	}
-
-	if countMaxNoCommentPeriod > 0 {
-		metrics.AvgMaxNoCommentPeriodHours = sumMaxNoCommentPeriodHours / float64(countMaxNoCommentPeriod)
-		metrics.MedianMaxNoCommentPeriodHours = calculateMedianFloat(maxNoCommentPeriodHours)
+	metrics.StaleApprovalPRCount = a.staleApprovalCount
+	if a.approvedCount > 0 {
+		metrics.StaleApprovalPercent = float64(a.staleApprovalCount) / float64(a.approvedCount) * 100
 	}
-
-	if countMaxNoCommitPeriod > 0 {
-		metrics.AvgMaxNoCommitPeriodHours = sumMaxNoCommitPeriodHours / float64(countMaxNoCommitPeriod)
-		metrics.MedianMaxNoCommitPeriodHours = calculateMedianFloat(maxNoCommitPeriodHours)
+	metrics.CommitsAfterApprovalPRCount = a.afterApprovalCount
+	if a.approvedCount > 0 {
+		metrics.CommitsAfterApprovalPercent = float64(a.afterApprovalCount) / float64(a.approvedCount) * 100
 	}
-
-	if countMaxNoActivityPeriod > 0 {
-		metrics.AvgMaxNoActivityPeriodHours = sumMaxNoActivityPeriodHours / float64(countMaxNoActivityPeriod)
-		metrics.MedianMaxNoActivityPeriodHours = calculateMedianFloat(maxNoActivityPeriodHours)
+	// Normalize throughput by the people contributing, so teams of different sizes compare
+	metrics.ActiveAuthorCount = len(a.authors)
+	metrics.MergedPRsPerAuthor = float64(a.prCount) / float64(len(a.authors))
+	metrics.LinesMergedPerAuthor = float64(a.mergedLines) / float64(len(a.authors))
+	metrics.AvgBaseSyncMergeCount = a.baseSyncMergeCount.mean()
+	metrics.BaseSyncMergedPercent = float64(a.baseSyncMergedCount) / float64(a.prCount) * 100
+	metrics.SizeWeightedAvgFirstCommitToMergeHours = a.sizeWeightedFirstCommitToMergeHours.mean()
+	metrics.SizeWeightedAvgTotalPRLifetimeHours = a.sizeWeightedTotalPRLifetimeHours.mean()
+	metrics.AvgCIQueueHours = a.ciQueueHours.mean()
+	metrics.MedianCIQueueHours = a.ciQueueHours.medianValue()
+	metrics.FlakyPRCount = a.flakyPRCount
+	metrics.FlakyCheckRetries = a.flakyCheckRetries
+
+	// Ownership metrics only cover PRs that changed a file with a user owner
+	metrics.OwnedPRCount = a.ownedPRCount
+	if a.ownedApprovers > 0 {
+		metrics.CodeOwnerApproverPercent = float64(a.ownedOwnerApprovers) / float64(a.ownedApprovers) * 100
+	}
+	if a.ownedPRCount > 0 {
+		metrics.AvgCodeOwnerCoveragePercent = a.codeOwnerApprovalCoverage.mean()
+		metrics.FullyOwnerApprovedPercent = float64(a.fullyOwnerApproved) / float64(a.ownedPRCount) * 100
 	}
-
 	return metrics
 }
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Component of files directly in the repository root
+const RootComponent = "."
+
+// Returns the component of a file: its directory path cut to at most depth directories
+func componentOf(filePath string, depth int) string {
+	dir := path.Dir(strings.TrimPrefix(filePath, "/"))
+	if dir == "." {
+		return RootComponent
+	}
+	parts := strings.Split(dir, "/")
+	return strings.Join(parts[:min(depth, len(parts))], "/")
+}
+
+// Counts the distinct reviewers of the components merged PRs changed, per ISO week and calendar
+// month, grouping PRs by merge date like the aggregated metrics. A component is a path prefix of
+// depth directories, such as "internal/api" at depth 2.
+func CalculateBusFactor(prMetrics []*api.PRMetrics, depth int) []*api.BusFactor {
+	var busFactors []*api.BusFactor
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[string]map[string]*api.BusFactor)
+		reviewers := make(map[*api.BusFactor]map[string]bool)
+		for _, pr := range prMetrics {
+			if pr.MergedAt.IsZero() {
+				continue
+			}
+
+			period, startDate, endDate := CalendarPeriod(pr.MergedAt, granularity)
+			if periods[period] == nil {
+				periods[period] = make(map[string]*api.BusFactor)
+			}
+			touched := make(map[string]bool)
+			for _, file := range pr.Files {
+				component := componentOf(file.Path, depth)
+				// Count each PR once per component even when it changed several files in it
+				if touched[component] {
+					continue
+				}
+				touched[component] = true
+
+				busFactor, exists := periods[period][component]
+				if !exists {
+					busFactor = &api.BusFactor{
+						Granularity: granularity,
+						Period:      period,
+						StartDate:   startDate,
+						EndDate:     endDate,
+						Component:   component,
+					}
+					periods[period][component] = busFactor
+					reviewers[busFactor] = make(map[string]bool)
+				}
+				busFactor.PRCount++
+				for _, reviewer := range pr.Reviewers {
+					reviewers[busFactor][reviewer.Reviewer] = true
+				}
+			}
+		}
+
+		var periodBusFactors []*api.BusFactor
+		for _, components := range periods {
+			for _, busFactor := range components {
+				for reviewer := range reviewers[busFactor] {
+					busFactor.Reviewers = append(busFactor.Reviewers, reviewer)
+				}
+				sort.Strings(busFactor.Reviewers)
+				busFactor.ReviewerCount = len(busFactor.Reviewers)
+				busFactor.SingleReviewer = busFactor.ReviewerCount == 1
+				periodBusFactors = append(periodBusFactors, busFactor)
+			}
+		}
+		sort.Slice(periodBusFactors, func(i, j int) bool {
+			if periodBusFactors[i].Period != periodBusFactors[j].Period {
+				return periodBusFactors[i].Period < periodBusFactors[j].Period
+			}
+			return periodBusFactors[i].Component < periodBusFactors[j].Component
+		})
+		busFactors = append(busFactors, periodBusFactors...)
+	}
+	return busFactors
+}
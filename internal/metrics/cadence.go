@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Returns the PRs of each author ordered by creation
+func prsByAuthor(prMetrics []*api.PRMetrics) map[string][]*api.PRMetrics {
+	byAuthor := make(map[string][]*api.PRMetrics)
+	for _, pr := range prMetrics {
+		byAuthor[pr.Author] = append(byAuthor[pr.Author], pr)
+	}
+	for _, prs := range byAuthor {
+		sort.SliceStable(prs, func(i, j int) bool {
+			return prs[i].CreatedAt.Before(prs[j].CreatedAt)
+		})
+	}
+	return byAuthor
+}
+
+// Returns the days between two times
+func daysBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24
+}
+
+// Computes the median days between consecutive PR creations of each author, ordered by author.
+// Unlike the aggregated metrics, every PR counts, merged or not.
+func CalculateAuthorCadence(prMetrics []*api.PRMetrics) []*api.AuthorCadence {
+	var cadences []*api.AuthorCadence
+	for author, prs := range prsByAuthor(prMetrics) {
+		var gaps []float64
+		for i := 1; i < len(prs); i++ {
+			gaps = append(gaps, daysBetween(prs[i-1].CreatedAt, prs[i].CreatedAt))
+		}
+		cadences = append(cadences, &api.AuthorCadence{
+			Author:               author,
+			PRCount:              len(prs),
+			FirstCreatedAt:       prs[0].CreatedAt,
+			LastCreatedAt:        prs[len(prs)-1].CreatedAt,
+			MedianDaysBetweenPRs: calculateMedianFloat(gaps),
+		})
+	}
+	sort.Slice(cadences, func(i, j int) bool {
+		return cadences[i].Author < cadences[j].Author
+	})
+	return cadences
+}
+
+// Computes the team's median days between consecutive PRs of the same author per ISO week and
+// calendar month, grouping PRs by creation date. Each gap belongs to the period of the later PR,
+// so an author's first PR of the run opens no gap.
+func CalculateTeamCadence(prMetrics []*api.PRMetrics) []*api.TeamCadence {
+	byAuthor := prsByAuthor(prMetrics)
+
+	var cadences []*api.TeamCadence
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[string]*api.TeamCadence)
+		authors := make(map[*api.TeamCadence]map[string]bool)
+		gaps := make(map[*api.TeamCadence][]float64)
+		for author, prs := range byAuthor {
+			for i, pr := range prs {
+				period, startDate, endDate := CalendarPeriod(pr.CreatedAt, granularity)
+				cadence, exists := periods[period]
+				if !exists {
+					cadence = &api.TeamCadence{
+						Granularity: granularity,
+						Period:      period,
+						StartDate:   startDate,
+						EndDate:     endDate,
+					}
+					periods[period] = cadence
+					authors[cadence] = make(map[string]bool)
+				}
+				cadence.PRCount++
+				authors[cadence][author] = true
+				if i > 0 {
+					gaps[cadence] = append(gaps[cadence], daysBetween(prs[i-1].CreatedAt, pr.CreatedAt))
+				}
+			}
+		}
+
+		var periodCadences []*api.TeamCadence
+		for _, cadence := range periods {
+			cadence.AuthorCount = len(authors[cadence])
+			cadence.GapCount = len(gaps[cadence])
+			cadence.MedianDaysBetweenPRs = calculateMedianFloat(gaps[cadence])
+			periodCadences = append(periodCadences, cadence)
+		}
+		sort.Slice(periodCadences, func(i, j int) bool {
+			return periodCadences[i].Period < periodCadences[j].Period
+		})
+		cadences = append(cadences, periodCadences...)
+	}
+	return cadences
+}
@@ -1,11 +1,39 @@
 package metrics
 
 import (
+	"sort"
+
 	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/calendar"
+	"github.com/fukuchancat/github-pr-metrics/internal/jira"
+	"github.com/fukuchancat/github-pr-metrics/internal/localgit"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
 	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
-	"github.com/google/go-github/v74/github"
 )
 
+// Commit date sources usable for commit timing metrics
+const (
+	CommitDateSourceAuthor    = "author"
+	CommitDateSourceCommitter = "committer"
+)
+
+// Options tunes how PR metrics are derived from the fetched data
+type Options struct {
+	CommitDateSource  string                  // Author or committer date; committer dates survive rebases better
+	ClampCommitTimes  bool                    // Clamp commit times after the merge to the merge time
+	Calendar          *calendar.Calendar      // Measure durations in business hours when set
+	LocalGit          *localgit.Repository    // Analyze each PR's commits in this clone when set
+	TestFilePatterns  []string                // Files counted as tests for the test change ratio; defaults to common conventions
+	SizeExclusions    SizeExclusionOptions    // Files left out of the size metrics
+	Categories        []CategoryRule          // Rules assigning each PR a category; the first match wins
+	IssueKeys         IssueKeyOptions         // Issue tracker keys extracted from titles and branches
+	Jira              *jira.Client            // Look up when referenced issues were created when set
+	DependencyUpdates DependencyUpdateOptions // Bots whose PRs are dependency updates besides Dependabot and Renovate
+	OnError           string                  // How failures while fetching a PR's data are handled: skip, fail, or retry
+	CIMetrics         bool                    // Fetch the check runs of each head commit for the CI queue time and flaky checks
+	Progress          func(done, total int)   // Called after each PR's data is fetched, for live progress displays
+}
+
 // Orchestrates individual PR and aggregated metrics computation
 type Calculator struct {
 	prCalculator         *PRMetricsCalculator
@@ -13,25 +41,38 @@ type Calculator struct {
 	logger               *utils.Logger
 }
 
-// Initializes both individual and aggregated metrics calculators
-func NewCalculator(client *api.Client, logger *utils.Logger) *Calculator {
+// Initializes both individual and aggregated metrics calculators, reading PR data from a source
+// in the provider-neutral model, such as a provider wrapped by api.NewSource
+func NewCalculator(source model.Source, logger *utils.Logger, options Options) *Calculator {
 	return &Calculator{
-		prCalculator:         NewPRMetricsCalculator(client, logger),
+		prCalculator:         NewPRMetricsCalculator(source, logger, options),
 		aggregatedCalculator: NewAggregatedMetricsCalculator(logger),
 		logger:               logger,
 	}
 }
 
+// Initializes a calculator over PR data the caller already fetched, such as a mirror fed by
+// webhooks, so metrics are computed without an API client. Convert the PullRequest of each PRData
+// with api.GitHubConverter and pass it to CalculatePRMetrics or CalculateAllPRMetrics.
+func NewCalculatorFromData(prs []*api.PRData, repository api.RepositoryData, logger *utils.Logger, options Options) *Calculator {
+	return NewCalculator(api.NewSource(api.NewMemoryProvider(prs, repository), api.GitHubConverter{}), logger, options)
+}
+
 // Delegates PR metrics calculation to the PR calculator
-func (c *Calculator) CalculatePRMetrics(owner, repo string, pr *github.PullRequest) (*api.PRMetrics, error) {
+func (c *Calculator) CalculatePRMetrics(owner, repo string, pr *model.PullRequest) (*api.PRMetrics, error) {
 	return c.prCalculator.CalculatePRMetrics(owner, repo, pr)
 }
 
 // Delegates batch PR metrics calculation to the PR calculator
-func (c *Calculator) CalculateAllPRMetrics(owner, repo string, prs []*github.PullRequest) ([]*api.PRMetrics, error) {
+func (c *Calculator) CalculateAllPRMetrics(owner, repo string, prs []*model.PullRequest) ([]*api.PRMetrics, error) {
 	return c.prCalculator.CalculateAllPRMetrics(owner, repo, prs)
 }
 
+// Delegates stacked PR detection to the PR calculator
+func (c *Calculator) DetectStacks(prMetrics []*api.PRMetrics) int {
+	return c.prCalculator.DetectStacks(prMetrics)
+}
+
 // Delegates weekly metrics aggregation to the aggregated calculator
 func (c *Calculator) CalculateWeeklyAggregatedMetrics(prMetrics []*api.PRMetrics) ([]*api.AggregatedMetrics, error) {
 	return c.aggregatedCalculator.CalculateWeeklyAggregatedMetrics(prMetrics)
@@ -41,3 +82,50 @@ func (c *Calculator) CalculateWeeklyAggregatedMetrics(prMetrics []*api.PRMetrics
 func (c *Calculator) CalculateMonthlyAggregatedMetrics(prMetrics []*api.PRMetrics) ([]*api.AggregatedMetrics, error) {
 	return c.aggregatedCalculator.CalculateMonthlyAggregatedMetrics(prMetrics)
 }
+
+// Delegates overall metrics aggregation to the aggregated calculator
+func (c *Calculator) CalculateOverallAggregatedMetrics(prMetrics []*api.PRMetrics) *api.AggregatedMetrics {
+	return c.aggregatedCalculator.CalculateOverallAggregatedMetrics(prMetrics)
+}
+
+// Calculates weekly and monthly aggregated metrics separately for the PRs of each category
+func (c *Calculator) CalculateCategoryAggregatedMetrics(prMetrics []*api.PRMetrics) ([]*api.CategoryAggregatedMetrics, error) {
+	categoryPRs := make(map[string][]*api.PRMetrics)
+	for _, pr := range prMetrics {
+		if pr.Category != "" {
+			categoryPRs[pr.Category] = append(categoryPRs[pr.Category], pr)
+		}
+	}
+	categories := make([]string, 0, len(categoryPRs))
+	for category := range categoryPRs {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var categoryMetrics []*api.CategoryAggregatedMetrics
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		for _, category := range categories {
+			aggregate := c.aggregatedCalculator.CalculateWeeklyAggregatedMetrics
+			if granularity == GranularityMonth {
+				aggregate = c.aggregatedCalculator.CalculateMonthlyAggregatedMetrics
+			}
+			periods, err := aggregate(categoryPRs[category])
+			if err != nil {
+				return nil, err
+			}
+			for _, period := range periods {
+				categoryMetrics = append(categoryMetrics, &api.CategoryAggregatedMetrics{
+					Granularity: granularity,
+					Category:    category,
+					Metrics:     period,
+				})
+			}
+		}
+	}
+	return categoryMetrics, nil
+}
+
+// Delegates failure reporting to the PR calculator
+func (c *Calculator) Errors() []*api.PRError {
+	return c.prCalculator.Errors()
+}
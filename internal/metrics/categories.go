@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+)
+
+// Category of PRs matching none of the category rules
+const CategoryOther = "other"
+
+// Assigns a category, such as feature, bugfix, or chore, to the PRs whose title,
+// head branch, or one of whose labels matches the rule's regular expressions
+type CategoryRule struct {
+	Category string `json:"category"`
+	Title    string `json:"title"`  // Regular expression matched against the PR title
+	Branch   string `json:"branch"` // Regular expression matched against the head branch name
+	Label    string `json:"label"`  // Regular expression matched against each label
+}
+
+// Validates the category name and the syntax of each regular expression
+func (r CategoryRule) Validate() error {
+	if r.Category == "" {
+		return fmt.Errorf("category name is required")
+	}
+	if r.Title == "" && r.Branch == "" && r.Label == "" {
+		return fmt.Errorf("category %q needs a title, branch, or label pattern", r.Category)
+	}
+	for _, pattern := range []string{r.Title, r.Branch, r.Label} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q for category %q: %v", pattern, r.Category, err)
+		}
+	}
+	return nil
+}
+
+// Category rule with its regular expressions compiled; nil expressions match nothing
+type compiledCategoryRule struct {
+	category string
+	title    *regexp.Regexp
+	branch   *regexp.Regexp
+	label    *regexp.Regexp
+}
+
+// Assigns categories to PRs by the first matching rule
+type categoryClassifier struct {
+	rules []compiledCategoryRule
+}
+
+// Initializes classifier with the rules, which are expected to have been validated
+func newCategoryClassifier(rules []CategoryRule) *categoryClassifier {
+	compile := func(pattern string) *regexp.Regexp {
+		if pattern == "" {
+			return nil
+		}
+		compiled, _ := regexp.Compile(pattern)
+		return compiled
+	}
+
+	classifier := &categoryClassifier{}
+	for _, rule := range rules {
+		classifier.rules = append(classifier.rules, compiledCategoryRule{
+			category: rule.Category,
+			title:    compile(rule.Title),
+			branch:   compile(rule.Branch),
+			label:    compile(rule.Label),
+		})
+	}
+	return classifier
+}
+
+// Returns the category of the first rule matching the PR, CategoryOther if none does,
+// or an empty string if no rules are configured
+func (c *categoryClassifier) classify(pr *model.PullRequest) string {
+	if len(c.rules) == 0 {
+		return ""
+	}
+
+	for _, rule := range c.rules {
+		if rule.title != nil && rule.title.MatchString(pr.Title) {
+			return rule.category
+		}
+		if rule.branch != nil && rule.branch.MatchString(pr.HeadBranch) {
+			return rule.category
+		}
+		if rule.label != nil {
+			for _, label := range pr.Labels {
+				if rule.label.MatchString(label) {
+					return rule.category
+				}
+			}
+		}
+	}
+	return CategoryOther
+}
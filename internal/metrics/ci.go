@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+)
+
+// Measures the hours from the push of the head commit to the first check run starting on it, or
+// zero if no check run started. The API does not expose push times, so the committer date of the
+// head commit stands in for it; when the checks only started after the PR was opened from an
+// earlier commit, they were triggered by opening the PR and the creation time is used instead.
+// Queueing happens around the clock, so business hours do not apply.
+func calculateCIQueueHours(pr *model.PullRequest, commits []*model.CommitEvent, checkRuns []*model.CheckRun) float64 {
+	var firstStartedAt time.Time
+	for _, checkRun := range checkRuns {
+		startedAt := checkRun.StartedAt
+		if !startedAt.IsZero() && (firstStartedAt.IsZero() || startedAt.Before(firstStartedAt)) {
+			firstStartedAt = startedAt
+		}
+	}
+	if firstStartedAt.IsZero() {
+		return 0
+	}
+
+	pushedAt := headCommitDate(pr.HeadSHA, commits)
+	if pushedAt.IsZero() {
+		return 0
+	}
+	if createdAt := pr.CreatedAt; createdAt.After(pushedAt) && firstStartedAt.After(createdAt) {
+		pushedAt = createdAt
+	}
+
+	// Clock skew between the committer's machine and the runners can put the start first
+	if !firstStartedAt.After(pushedAt) {
+		return 0
+	}
+	return firstStartedAt.Sub(pushedAt).Hours()
+}
+
+// Returns the committer date of the head commit, falling back to the last listed commit when the
+// head SHA is not among them, or the zero time without commits
+func headCommitDate(sha string, commits []*model.CommitEvent) time.Time {
+	if len(commits) == 0 {
+		return time.Time{}
+	}
+	head := commits[len(commits)-1]
+	for _, commit := range commits {
+		if commit.SHA == sha {
+			head = commit
+			break
+		}
+	}
+	return head.CommittedAt
+}
+
+// Counts the failed runs of each check that a later run of the same check on the commit passed,
+// which are re-runs of a flaky check rather than fixes, since the commit did not change.
+// Cancelled runs are left out, as they are usually superseded rather than failed.
+func countFlakyCheckRetries(checkRuns []*model.CheckRun) int {
+	count := 0
+	for _, runs := range checkRunsByName(checkRuns) {
+		failed := 0
+		for _, run := range runs {
+			switch run.Conclusion {
+			case "failure", "timed_out":
+				failed++
+			case "success":
+				count += failed
+				failed = 0
+			}
+		}
+	}
+	return count
+}
+
+// Returns the last run of each check, which is the one that decides whether the check passed
+func latestCheckRuns(checkRuns []*model.CheckRun) []*model.CheckRun {
+	var latest []*model.CheckRun
+	for _, runs := range checkRunsByName(checkRuns) {
+		latest = append(latest, runs[len(runs)-1])
+	}
+	return latest
+}
+
+// Groups check runs by name, each group in the order the runs started
+func checkRunsByName(checkRuns []*model.CheckRun) map[string][]*model.CheckRun {
+	byName := make(map[string][]*model.CheckRun)
+	for _, checkRun := range checkRuns {
+		byName[checkRun.Name] = append(byName[checkRun.Name], checkRun)
+	}
+	for _, runs := range byName {
+		sort.SliceStable(runs, func(i, j int) bool {
+			if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+				return runs[i].StartedAt.Before(runs[j].StartedAt)
+			}
+			return runs[i].ID < runs[j].ID
+		})
+	}
+	return byName
+}
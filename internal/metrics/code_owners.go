@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rules parsed from a CODEOWNERS file
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// A CODEOWNERS pattern and the owners it assigns
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Parses a CODEOWNERS file, skipping comments, section headers, and invalid patterns
+func ParseCodeOwners(content string) *CodeOwners {
+	codeOwners := &CodeOwners{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		// GitLab section headers such as [Backend] carry no pattern
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = line[:index]
+		}
+
+		fields := strings.Fields(line)
+		pattern, err := compileCodeOwnersPattern(fields[0])
+		if err != nil {
+			continue
+		}
+		codeOwners.rules = append(codeOwners.rules, codeOwnersRule{
+			pattern: pattern,
+			owners:  fields[1:],
+		})
+	}
+	return codeOwners
+}
+
+// Returns the owners of a file; the last matching rule wins, as on GitHub
+func (o *CodeOwners) Owners(path string) []string {
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if o.rules[i].pattern.MatchString(path) {
+			return o.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// Reports whether a user is listed by login (@login) as an owner of the file.
+// Team owners (@org/team) are not resolved.
+func (o *CodeOwners) IsOwner(path, login string) bool {
+	for _, owner := range o.Owners(path) {
+		if strings.EqualFold(strings.TrimPrefix(owner, "@"), login) {
+			return true
+		}
+	}
+	return false
+}
+
+// Converts a gitignore-style CODEOWNERS pattern into a regular expression over repository paths
+func compileCodeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	// Patterns with a slash other than a trailing one are relative to the repository root
+	directoryOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	// A pattern also matches everything inside a directory of that name, except that
+	// a trailing /* only matches the directory's direct children
+	switch {
+	case directoryOnly:
+		expr.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Days within which the share of a cohort merged is reported, forming its survival curve
+var CohortMergeDays = []int{1, 3, 7, 14, 30}
+
+// Follows the PRs opened each ISO week, reporting the cumulative share merged within each of
+// CohortMergeDays of creation in calendar time, later, or not at all
+func CalculateCohorts(prMetrics []*api.PRMetrics) []*api.Cohort {
+	cohorts := make(map[string]*api.Cohort)
+	for _, pr := range prMetrics {
+		period, startDate, endDate := CalendarPeriod(pr.CreatedAt, GranularityWeek)
+		cohort, exists := cohorts[period]
+		if !exists {
+			cohort = &api.Cohort{
+				Period:      period,
+				StartDate:   startDate,
+				EndDate:     endDate,
+				MergedCount: make([]int, len(CohortMergeDays)),
+			}
+			cohorts[period] = cohort
+		}
+
+		cohort.OpenedCount++
+		switch {
+		case !pr.MergedAt.IsZero():
+			mergeTime := pr.MergedAt.Sub(pr.CreatedAt)
+			if mergeTime > time.Duration(CohortMergeDays[len(CohortMergeDays)-1])*24*time.Hour {
+				cohort.MergedLaterCount++
+			}
+			for i, days := range CohortMergeDays {
+				if mergeTime <= time.Duration(days)*24*time.Hour {
+					cohort.MergedCount[i]++
+				}
+			}
+		case pr.State == "closed":
+			cohort.ClosedCount++
+		default:
+			cohort.OpenCount++
+		}
+	}
+
+	weeklyCohorts := make([]*api.Cohort, 0, len(cohorts))
+	for _, cohort := range cohorts {
+		weeklyCohorts = append(weeklyCohorts, cohort)
+	}
+	sort.Slice(weeklyCohorts, func(i, j int) bool {
+		return weeklyCohorts[i].Period < weeklyCohorts[j].Period
+	})
+	return weeklyCohorts
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Computes the median hours from opening to first review by the day of week and hour of day
+// the PR was opened in the location, to show whether PRs opened late in the day wait until the
+// next morning. PRs without a review are left out, since their wait has not ended.
+func CalculateCreationTimeReviewLatency(prMetrics []*api.PRMetrics, location *time.Location) *api.CreationTimeReviewLatency {
+	var hours [8][25][]float64
+	for _, pr := range prMetrics {
+		if pr.ReviewCount == 0 || pr.CreatedAt.IsZero() {
+			continue
+		}
+		createdAt := pr.CreatedAt.In(location)
+		// Weeks start on Monday, as ISO weeks do; the last row and column collect every day and hour
+		day, hour := int(createdAt.Weekday()+6)%7, createdAt.Hour()
+		for _, cell := range [][2]int{{day, hour}, {day, 24}, {7, hour}, {7, 24}} {
+			hours[cell[0]][cell[1]] = append(hours[cell[0]][cell[1]], pr.WaitingForReviewHours)
+		}
+	}
+
+	latency := &api.CreationTimeReviewLatency{}
+	for day := range hours {
+		for hour, values := range hours[day] {
+			latency.PRCounts[day][hour] = len(values)
+			latency.MedianHours[day][hour] = calculateMedianFloat(values)
+		}
+	}
+	return latency
+}
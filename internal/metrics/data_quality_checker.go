@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Policies for handling impossible metric values
+const (
+	DataQualityPolicyReport  = "report"  // Keep values as computed and only report them
+	DataQualityPolicyClamp   = "clamp"   // Clamp values into their valid range
+	DataQualityPolicyExclude = "exclude" // Drop affected PRs from all outputs
+)
+
+// Detects impossible metric values caused by clock skew, rebases, or API inconsistencies
+type DataQualityChecker struct {
+	logger *utils.Logger
+}
+
+// Initializes checker with logger dependency
+func NewDataQualityChecker(logger *utils.Logger) *DataQualityChecker {
+	return &DataQualityChecker{
+		logger: logger,
+	}
+}
+
+// Checks every PR, applies the policy, and returns the PRs to keep along with the issues found
+func (c *DataQualityChecker) Check(prMetrics []*api.PRMetrics, policy string) ([]*api.PRMetrics, []*api.DataQualityIssue) {
+	c.logger.Info("Checking data quality of %d PR metrics", len(prMetrics))
+
+	var kept []*api.PRMetrics
+	var allIssues []*api.DataQualityIssue
+
+	for _, pr := range prMetrics {
+		issues := c.checkPR(pr, policy)
+		allIssues = append(allIssues, issues...)
+
+		if len(issues) > 0 && policy == DataQualityPolicyExclude {
+			c.logger.Debug("Excluding PR #%d due to %d data quality issues", pr.Number, len(issues))
+			continue
+		}
+		kept = append(kept, pr)
+	}
+
+	if len(allIssues) > 0 {
+		affectedPRs := make(map[int]bool)
+		for _, issue := range allIssues {
+			affectedPRs[issue.PRNumber] = true
+		}
+		c.logger.Warn("Found %d data quality issues in %d PRs (policy: %s)", len(allIssues), len(affectedPRs), policy)
+	}
+
+	return kept, allIssues
+}
+
+// Validates a single PR and clamps its values when the policy asks for it
+func (c *DataQualityChecker) checkPR(pr *api.PRMetrics, policy string) []*api.DataQualityIssue {
+	var issues []*api.DataQualityIssue
+
+	action := policy
+	newIssue := func(field string, value float64, problem string) {
+		issues = append(issues, &api.DataQualityIssue{
+			PRNumber: pr.Number,
+			Field:    field,
+			Value:    value,
+			Problem:  problem,
+			Action:   action,
+		})
+	}
+
+	// Durations that can never be negative
+	durations := []struct {
+		field string
+		value *float64
+	}{
+		{"First Commit to Merge (Hours)", &pr.FirstCommitToMergeHours},
+		{"Last Commit to Merge (Hours)", &pr.LastCommitToMergeHours},
+		{"Created to First Comment (Hours)", &pr.CreatedToFirstCommentHours},
+		{"Time to Approval (Hours)", &pr.TimeToApprovalHours},
+		{"Total PR Lifetime (Hours)", &pr.TotalPRLifetimeHours},
+		{"Waiting for Review (Hours)", &pr.WaitingForReviewHours},
+		{"In Review (Hours)", &pr.InReviewHours},
+		{"Waiting to Merge (Hours)", &pr.WaitingToMergeHours},
+	}
+	for _, duration := range durations {
+		if *duration.value < 0 {
+			newIssue(duration.field, *duration.value, "negative duration")
+			if policy == DataQualityPolicyClamp {
+				*duration.value = 0
+			}
+		}
+	}
+
+	if !pr.MergedAt.IsZero() {
+		// The first commit must exist before the merge
+		if !pr.FirstCommitAt.IsZero() && pr.FirstCommitAt.After(pr.MergedAt) {
+			newIssue("First Commit At", pr.FirstCommitAt.Sub(pr.MergedAt).Hours(), "first commit after merge")
+			if policy == DataQualityPolicyClamp {
+				pr.FirstCommitAt = pr.MergedAt
+			}
+		}
+
+		// An approval after the merge did not gate the merge
+		if pr.TimeToApprovalHours > pr.TotalPRLifetimeHours && pr.TotalPRLifetimeHours > 0 {
+			newIssue("Time to Approval (Hours)", pr.TimeToApprovalHours, "approval after merge")
+			if policy == DataQualityPolicyClamp {
+				pr.TimeToApprovalHours = pr.TotalPRLifetimeHours
+			}
+		}
+	}
+
+	return issues
+}
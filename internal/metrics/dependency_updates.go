@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+)
+
+// Logins of the dependency update bots recognized without configuration, in lowercase
+var defaultDependencyBots = []string{
+	"dependabot[bot]",
+	"dependabot-preview[bot]",
+	"dependabot",
+	"renovate[bot]",
+	"renovate-bot",
+	"renovate",
+}
+
+// Authors whose PRs are dependency updates rather than human changes
+type DependencyUpdateOptions struct {
+	Authors []string `json:"authors"` // Additional bot logins, such as a self-hosted Renovate account
+}
+
+// Validates the author logins
+func (o DependencyUpdateOptions) Validate() error {
+	for _, author := range o.Authors {
+		if strings.TrimSpace(author) == "" {
+			return fmt.Errorf("dependency update author must not be empty")
+		}
+	}
+	return nil
+}
+
+// Recognizes the PRs opened by dependency update bots
+type dependencyBotMatcher struct {
+	authors map[string]bool
+}
+
+// Initializes matcher with the default bots and the configured authors
+func newDependencyBotMatcher(options DependencyUpdateOptions) *dependencyBotMatcher {
+	matcher := &dependencyBotMatcher{authors: make(map[string]bool)}
+	for _, author := range append(defaultDependencyBots, options.Authors...) {
+		matcher.authors[strings.ToLower(author)] = true
+	}
+	return matcher
+}
+
+// Reports whether the PR was opened by a dependency update bot
+func (m *dependencyBotMatcher) matches(author string) bool {
+	return m.authors[strings.ToLower(author)]
+}
+
+// Reports whether the PR was merged by auto-merge or by a bot rather than by a person
+func isAutoMerged(pr *model.PullRequest, mergedBy string) bool {
+	return pr.AutoMerge || strings.HasSuffix(strings.ToLower(mergedBy), "[bot]")
+}
+
+// Splits PRs into dependency updates and the rest
+func SplitDependencyUpdates(prMetrics []*api.PRMetrics) ([]*api.PRMetrics, []*api.PRMetrics) {
+	var updates, others []*api.PRMetrics
+	for _, pr := range prMetrics {
+		if pr.DependencyUpdate {
+			updates = append(updates, pr)
+		} else {
+			others = append(others, pr)
+		}
+	}
+	return updates, others
+}
+
+// Computes the volume and outcome of dependency update PRs per ISO week and calendar month.
+// Unlike the aggregated metrics, PRs are grouped by creation date, so PRs closed without
+// merging count toward the failure rate.
+func CalculateDependencyUpdateMetrics(prMetrics []*api.PRMetrics) []*api.DependencyUpdateMetrics {
+	var allMetrics []*api.DependencyUpdateMetrics
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[string]*api.DependencyUpdateMetrics)
+		mergeHours := make(map[string][]float64)
+		for _, pr := range prMetrics {
+			if !pr.DependencyUpdate {
+				continue
+			}
+
+			period, startDate, endDate := CalendarPeriod(pr.CreatedAt, granularity)
+			metrics, exists := periods[period]
+			if !exists {
+				metrics = &api.DependencyUpdateMetrics{
+					Granularity: granularity,
+					Period:      period,
+					StartDate:   startDate,
+					EndDate:     endDate,
+				}
+				periods[period] = metrics
+			}
+
+			metrics.PRCount++
+			switch {
+			case !pr.MergedAt.IsZero():
+				metrics.MergedCount++
+				if pr.AutoMerged {
+					metrics.AutoMergedCount++
+				}
+				mergeHours[period] = append(mergeHours[period], pr.TotalPRLifetimeHours)
+			case pr.State == "closed":
+				metrics.ClosedUnmergedCount++
+			default:
+				metrics.OpenCount++
+			}
+		}
+
+		periodMetrics := make([]*api.DependencyUpdateMetrics, 0, len(periods))
+		for period, metrics := range periods {
+			if metrics.MergedCount > 0 {
+				metrics.AutoMergePercent = float64(metrics.AutoMergedCount) / float64(metrics.MergedCount) * 100
+				sum := 0.0
+				for _, hours := range mergeHours[period] {
+					sum += hours
+				}
+				metrics.AvgTimeToMergeHours = sum / float64(len(mergeHours[period]))
+				metrics.MedianTimeToMergeHours = calculateMedianFloat(mergeHours[period])
+			}
+			if closed := metrics.MergedCount + metrics.ClosedUnmergedCount; closed > 0 {
+				metrics.FailurePercent = float64(metrics.ClosedUnmergedCount) / float64(closed) * 100
+			}
+			periodMetrics = append(periodMetrics, metrics)
+		}
+		sort.Slice(periodMetrics, func(i, j int) bool {
+			return periodMetrics[i].Period < periodMetrics[j].Period
+		})
+		allMetrics = append(allMetrics, periodMetrics...)
+	}
+	return allMetrics
+}
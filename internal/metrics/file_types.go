@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Kinds of changed files review comments are broken down by, in the order they are reported
+const (
+	FileTypeSource = "source"
+	FileTypeTest   = "test"
+	FileTypeConfig = "config"
+	FileTypeDocs   = "docs"
+	FileTypeOther  = "other"
+)
+
+// Order of the file types within a period
+var fileTypeOrder = map[string]int{
+	FileTypeSource: 0,
+	FileTypeTest:   1,
+	FileTypeConfig: 2,
+	FileTypeDocs:   3,
+	FileTypeOther:  4,
+}
+
+// Languages of configuration and build files rather than program source
+var configLanguages = map[string]bool{
+	"JSON": true, "YAML": true, "TOML": true, "XML": true, "HCL": true,
+	"Dockerfile": true, "Makefile": true, "CMake": true, "Starlark": true, "Go Module": true,
+}
+
+// Languages of documentation
+var docsLanguages = map[string]bool{
+	"Markdown": true, "reStructuredText": true, "Text": true,
+}
+
+// Classifies a changed file as a test, configuration, documentation, or source file. Tests are
+// recognized first, by the test file patterns, so a YAML fixture under testdata/ is a test.
+func classifyFileType(tests *testFileMatcher, filePath string) string {
+	if tests.matches(filePath) {
+		return FileTypeTest
+	}
+	name := path.Base(filePath)
+	language := classifyLanguage(filePath)
+	switch {
+	case docsLanguages[language] || strings.HasPrefix(filePath, "docs/") || strings.Contains(filePath, "/docs/"):
+		return FileTypeDocs
+	case configLanguages[language] || strings.HasPrefix(name, ".") || strings.HasPrefix(filePath, ".github/"):
+		return FileTypeConfig
+	case language == LanguageOther:
+		return FileTypeOther
+	default:
+		return FileTypeSource
+	}
+}
+
+// Counts the inline review comments of merged PRs per file type, ISO week, and calendar month,
+// grouping PRs by merge date like the aggregated metrics. Test files are those matching
+// testPatterns, or the default test file patterns if none are given.
+func CalculateFileTypeComments(prMetrics []*api.PRMetrics, testPatterns []string) []*api.FileTypeComments {
+	tests := newTestFileMatcher(testPatterns)
+
+	var fileTypeComments []*api.FileTypeComments
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[string]map[string]*api.FileTypeComments)
+		for _, pr := range prMetrics {
+			if pr.MergedAt.IsZero() {
+				continue
+			}
+
+			period, startDate, endDate := CalendarPeriod(pr.MergedAt, granularity)
+			if periods[period] == nil {
+				periods[period] = make(map[string]*api.FileTypeComments)
+			}
+			touched := make(map[string]bool)
+			for _, file := range pr.Files {
+				fileType := classifyFileType(tests, file.Path)
+				comments, exists := periods[period][fileType]
+				if !exists {
+					comments = &api.FileTypeComments{
+						Granularity: granularity,
+						Period:      period,
+						StartDate:   startDate,
+						EndDate:     endDate,
+						FileType:    fileType,
+					}
+					periods[period][fileType] = comments
+				}
+				// Count each PR once per file type even when it changed several files of it
+				if !touched[fileType] {
+					touched[fileType] = true
+					comments.PRCount++
+				}
+				comments.ChangedFiles++
+				comments.ChangedLines += file.Additions + file.Deletions
+				comments.ReviewCommentCount += file.ReviewCommentCount
+			}
+		}
+
+		var periodComments []*api.FileTypeComments
+		for _, fileTypes := range periods {
+			totalComments := 0
+			for _, comments := range fileTypes {
+				totalComments += comments.ReviewCommentCount
+			}
+			for _, comments := range fileTypes {
+				if totalComments > 0 {
+					comments.SharePercent = float64(comments.ReviewCommentCount) / float64(totalComments) * 100
+				}
+				if comments.ChangedLines > 0 {
+					comments.CommentsPer100Lines = float64(comments.ReviewCommentCount) / float64(comments.ChangedLines) * 100
+				}
+				periodComments = append(periodComments, comments)
+			}
+		}
+		sort.Slice(periodComments, func(i, j int) bool {
+			if periodComments[i].Period != periodComments[j].Period {
+				return periodComments[i].Period < periodComments[j].Period
+			}
+			return fileTypeOrder[periodComments[i].FileType] < fileTypeOrder[periodComments[j].FileType]
+		})
+		fileTypeComments = append(fileTypeComments, periodComments...)
+	}
+	return fileTypeComments
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Activities counted by the heatmap, in output order
+const (
+	HeatmapActivityCreated = "created"
+	HeatmapActivityReview  = "review"
+	HeatmapActivityMerge   = "merge"
+)
+
+var heatmapActivities = []string{HeatmapActivityCreated, HeatmapActivityReview, HeatmapActivityMerge}
+
+// Counts PR creations, reviews, and merges by day of week and hour of day in the location, per
+// ISO week and calendar month of the activity itself, to show when review work happens
+func CalculateHeatmap(prMetrics []*api.PRMetrics, location *time.Location) []*api.Heatmap {
+	var heatmaps []*api.Heatmap
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[[2]string]*api.Heatmap)
+		count := func(activity string, t time.Time) {
+			if t.IsZero() {
+				return
+			}
+			t = t.In(location)
+			period, startDate, endDate := CalendarPeriod(t, granularity)
+			key := [2]string{period, activity}
+			heatmap, exists := periods[key]
+			if !exists {
+				heatmap = &api.Heatmap{
+					Granularity: granularity,
+					Period:      period,
+					StartDate:   startDate,
+					EndDate:     endDate,
+					Activity:    activity,
+				}
+				periods[key] = heatmap
+			}
+			// Weeks start on Monday, as ISO weeks do
+			heatmap.Counts[(t.Weekday()+6)%7][t.Hour()]++
+		}
+
+		for _, pr := range prMetrics {
+			count(HeatmapActivityCreated, pr.CreatedAt)
+			count(HeatmapActivityMerge, pr.MergedAt)
+			for _, event := range pr.Events {
+				if event.Type == api.EventTypeReview || event.Type == api.EventTypeApproval {
+					count(HeatmapActivityReview, event.Timestamp)
+				}
+			}
+		}
+
+		periodHeatmaps := make([]*api.Heatmap, 0, len(periods))
+		for _, heatmap := range periods {
+			periodHeatmaps = append(periodHeatmaps, heatmap)
+		}
+		sort.Slice(periodHeatmaps, func(i, j int) bool {
+			if periodHeatmaps[i].Period != periodHeatmaps[j].Period {
+				return periodHeatmaps[i].Period < periodHeatmaps[j].Period
+			}
+			return slices.Index(heatmapActivities, periodHeatmaps[i].Activity) < slices.Index(heatmapActivities, periodHeatmaps[j].Activity)
+		})
+		heatmaps = append(heatmaps, periodHeatmaps...)
+	}
+	return heatmaps
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/jira"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+)
+
+// Matches Jira-style issue keys such as ABC-123
+const DefaultIssueKeyPattern = `\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`
+
+// Extracts issue tracker keys from PR titles and head branches, optionally looking up
+// when each issue was created in Jira
+type IssueKeyOptions struct {
+	Pattern string        `json:"pattern"` // Regular expression matching a key; defaults to DefaultIssueKeyPattern
+	Jira    *jira.Options `json:"jira"`    // Read the creation time of each issue from this Jira instance
+}
+
+// Validates the pattern and the Jira settings
+func (o IssueKeyOptions) Validate() error {
+	if _, err := regexp.Compile(o.Pattern); err != nil {
+		return fmt.Errorf("invalid issue key pattern %q: %v", o.Pattern, err)
+	}
+	if o.Jira != nil {
+		if err := o.Jira.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reports whether issue keys are not extracted
+func (o IssueKeyOptions) IsEmpty() bool {
+	return o.Pattern == "" && o.Jira == nil
+}
+
+// Finds the issue keys a PR refers to
+type issueKeyExtractor struct {
+	pattern *regexp.Regexp
+}
+
+// Initializes extractor with the validated options, or returns nil when extraction is disabled
+func newIssueKeyExtractor(options IssueKeyOptions) *issueKeyExtractor {
+	if options.IsEmpty() {
+		return nil
+	}
+	pattern := options.Pattern
+	if pattern == "" {
+		pattern = DefaultIssueKeyPattern
+	}
+	compiled, _ := regexp.Compile(pattern)
+	return &issueKeyExtractor{pattern: compiled}
+}
+
+// Returns the distinct keys in the PR title and then the head branch, in order of appearance
+func (e *issueKeyExtractor) extract(pr *model.PullRequest) []string {
+	if e == nil {
+		return nil
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, text := range []string{pr.Title, pr.HeadBranch} {
+		for _, key := range e.pattern.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// Returns when the earliest created of the issues was created, skipping keys Jira does not know
+func (c *PRMetricsCalculator) getIssueCreatedAt(keys []string) (time.Time, error) {
+	var earliest time.Time
+	for _, key := range keys {
+		createdAt, err := c.options.Jira.GetIssueCreatedAt(key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !createdAt.IsZero() && (earliest.IsZero() || createdAt.Before(earliest)) {
+			earliest = createdAt
+		}
+	}
+	return earliest, nil
+}
@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+)
+
+// Language of files with an unknown extension
+const LanguageOther = "Other"
+
+// Languages of files recognized by their whole name rather than their extension
+var languageByFileName = map[string]string{
+	"Dockerfile":     "Dockerfile",
+	"Makefile":       "Makefile",
+	"GNUmakefile":    "Makefile",
+	"CMakeLists.txt": "CMake",
+	"Jenkinsfile":    "Groovy",
+	"BUILD":          "Starlark",
+	"BUILD.bazel":    "Starlark",
+	"WORKSPACE":      "Starlark",
+	"Gemfile":        "Ruby",
+	"Rakefile":       "Ruby",
+	"go.mod":         "Go Module",
+	"go.sum":         "Go Module",
+}
+
+// Languages of files by lowercase extension
+var languageByExtension = map[string]string{
+	".go":         "Go",
+	".py":         "Python",
+	".pyi":        "Python",
+	".rb":         "Ruby",
+	".erb":        "Ruby",
+	".java":       "Java",
+	".kt":         "Kotlin",
+	".kts":        "Kotlin",
+	".scala":      "Scala",
+	".groovy":     "Groovy",
+	".gradle":     "Groovy",
+	".js":         "JavaScript",
+	".jsx":        "JavaScript",
+	".mjs":        "JavaScript",
+	".cjs":        "JavaScript",
+	".ts":         "TypeScript",
+	".tsx":        "TypeScript",
+	".vue":        "Vue",
+	".svelte":     "Svelte",
+	".c":          "C",
+	".h":          "C",
+	".cc":         "C++",
+	".cpp":        "C++",
+	".cxx":        "C++",
+	".hpp":        "C++",
+	".hh":         "C++",
+	".cs":         "C#",
+	".fs":         "F#",
+	".m":          "Objective-C",
+	".mm":         "Objective-C",
+	".swift":      "Swift",
+	".rs":         "Rust",
+	".php":        "PHP",
+	".pl":         "Perl",
+	".lua":        "Lua",
+	".r":          "R",
+	".dart":       "Dart",
+	".ex":         "Elixir",
+	".exs":        "Elixir",
+	".erl":        "Erlang",
+	".hs":         "Haskell",
+	".clj":        "Clojure",
+	".sh":         "Shell",
+	".bash":       "Shell",
+	".zsh":        "Shell",
+	".ps1":        "PowerShell",
+	".sql":        "SQL",
+	".html":       "HTML",
+	".htm":        "HTML",
+	".css":        "CSS",
+	".scss":       "SCSS",
+	".sass":       "SCSS",
+	".less":       "Less",
+	".proto":      "Protocol Buffers",
+	".graphql":    "GraphQL",
+	".tf":         "HCL",
+	".hcl":        "HCL",
+	".json":       "JSON",
+	".yaml":       "YAML",
+	".yml":        "YAML",
+	".toml":       "TOML",
+	".xml":        "XML",
+	".md":         "Markdown",
+	".mdx":        "Markdown",
+	".rst":        "reStructuredText",
+	".txt":        "Text",
+	".ipynb":      "Jupyter Notebook",
+	".dockerfile": "Dockerfile",
+}
+
+// Classifies a file by its name or extension, returning LanguageOther for unknown files
+func classifyLanguage(filePath string) string {
+	name := path.Base(filePath)
+	if language, exists := languageByFileName[name]; exists {
+		return language
+	}
+	if language, exists := languageByExtension[strings.ToLower(path.Ext(name))]; exists {
+		return language
+	}
+	return LanguageOther
+}
+
+// Sums the changed lines of the files per language, most changed lines first
+func (c *PRMetricsCalculator) calculateLanguages(files []*model.FileChange) []api.LanguageChange {
+	indexes := make(map[string]int)
+	var languages []api.LanguageChange
+	for _, file := range files {
+		language := classifyLanguage(file.Path)
+		index, exists := indexes[language]
+		if !exists {
+			index = len(languages)
+			indexes[language] = index
+			languages = append(languages, api.LanguageChange{Language: language})
+		}
+		languages[index].Additions += file.Additions
+		languages[index].Deletions += file.Deletions
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		linesI := languages[i].Additions + languages[i].Deletions
+		linesJ := languages[j].Additions + languages[j].Deletions
+		if linesI != linesJ {
+			return linesI > linesJ
+		}
+		return languages[i].Language < languages[j].Language
+	})
+	return languages
+}
+
+// Computes the changed lines of merged PRs per language, ISO week, and calendar month,
+// grouping PRs by merge date like the aggregated metrics
+func CalculateLanguageMix(prMetrics []*api.PRMetrics) []*api.LanguageMix {
+	var mixes []*api.LanguageMix
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[string]map[string]*api.LanguageMix)
+		for _, pr := range prMetrics {
+			if pr.MergedAt.IsZero() {
+				continue
+			}
+
+			period, startDate, endDate := CalendarPeriod(pr.MergedAt, granularity)
+			if periods[period] == nil {
+				periods[period] = make(map[string]*api.LanguageMix)
+			}
+			for _, change := range pr.Languages {
+				mix, exists := periods[period][change.Language]
+				if !exists {
+					mix = &api.LanguageMix{
+						Granularity: granularity,
+						Period:      period,
+						StartDate:   startDate,
+						EndDate:     endDate,
+						Language:    change.Language,
+					}
+					periods[period][change.Language] = mix
+				}
+				mix.PRCount++
+				mix.Additions += change.Additions
+				mix.Deletions += change.Deletions
+			}
+		}
+
+		var periodMixes []*api.LanguageMix
+		for _, languages := range periods {
+			totalLines := 0
+			for _, mix := range languages {
+				totalLines += mix.Additions + mix.Deletions
+			}
+			for _, mix := range languages {
+				if totalLines > 0 {
+					mix.SharePercent = float64(mix.Additions+mix.Deletions) / float64(totalLines) * 100
+				}
+				periodMixes = append(periodMixes, mix)
+			}
+		}
+		sort.Slice(periodMixes, func(i, j int) bool {
+			if periodMixes[i].Period != periodMixes[j].Period {
+				return periodMixes[i].Period < periodMixes[j].Period
+			}
+			linesI := periodMixes[i].Additions + periodMixes[i].Deletions
+			linesJ := periodMixes[j].Additions + periodMixes[j].Deletions
+			if linesI != linesJ {
+				return linesI > linesJ
+			}
+			return periodMixes[i].Language < periodMixes[j].Language
+		})
+		mixes = append(mixes, periodMixes...)
+	}
+	return mixes
+}
@@ -1,89 +1,234 @@
 package metrics
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
 	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
-	"github.com/google/go-github/v74/github"
 )
 
-// Aggregates GitHub API data to compute comprehensive PR analytics
+// Aggregates PR data from the provider-neutral model to compute comprehensive PR analytics
 type PRMetricsCalculator struct {
-	client *api.Client
-	logger *utils.Logger
+	client            model.Source
+	logger            *utils.Logger
+	options           Options
+	branchProtections map[string]*branchProtectionLookup
+	codeOwners        map[string]*codeOwnersLookup
+	gitAttributes     map[string]*gitAttributesLookup
+	testFiles         *testFileMatcher
+	categories        *categoryClassifier
+	issueKeys         *issueKeyExtractor
+	dependencyBots    *dependencyBotMatcher
+	errors            []*api.PRError
 }
 
-// Initializes calculator with API client and logger dependencies
-func NewPRMetricsCalculator(client *api.Client, logger *utils.Logger) *PRMetricsCalculator {
+// Stages of PR metrics calculation that can fail
+const (
+	StageDetails          = "details"
+	StageCommits          = "commits"
+	StageComments         = "comments"
+	StageIssueComments    = "issue_comments"
+	StageReviews          = "reviews"
+	StageFiles            = "files"
+	StageReviewThreads    = "review_threads"
+	StageTimelineEvents   = "timeline_events"
+	StageBranchProtection = "branch_protection"
+	StageStatusChecks     = "status_checks"
+	StageCheckRuns        = "check_runs"
+	StageCodeOwners       = "code_owners"
+	StageGitAttributes    = "gitattributes"
+	StageLocalGit         = "local_git"
+	StageIssueTracker     = "issue_tracker"
+)
+
+// Policies for failures while fetching the data of a PR
+const (
+	ErrorPolicySkip  = "skip"  // Warn and go on, leaving out PRs whose details or commits could not be fetched
+	ErrorPolicyFail  = "fail"  // Stop at the first failure
+	ErrorPolicyRetry = "retry" // Retry failed requests, then go on as with skip
+)
+
+// StageError identifies the calculation stage in which an error occurred
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+// Error returns the error message prefixed with the stage
+func (e *StageError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Caches the branch protection fetch result so each base branch is only requested once
+type branchProtectionLookup struct {
+	protection *model.BranchProtection
+	err        error
+}
+
+// Caches the CODEOWNERS fetch result so each base branch is only requested once
+type codeOwnersLookup struct {
+	codeOwners *CodeOwners
+	err        error
+}
+
+// Caches the .gitattributes fetch result so each base branch is only requested once
+type gitAttributesLookup struct {
+	gitAttributes *GitAttributes
+	err           error
+}
+
+// Initializes calculator with data source and logger dependencies
+func NewPRMetricsCalculator(client model.Source, logger *utils.Logger, options Options) *PRMetricsCalculator {
 	return &PRMetricsCalculator{
-		client: client,
-		logger: logger,
+		client:            client,
+		logger:            logger,
+		options:           options,
+		branchProtections: make(map[string]*branchProtectionLookup),
+		codeOwners:        make(map[string]*codeOwnersLookup),
+		gitAttributes:     make(map[string]*gitAttributesLookup),
+		testFiles:         newTestFileMatcher(options.TestFilePatterns),
+		categories:        newCategoryClassifier(options.Categories),
+		issueKeys:         newIssueKeyExtractor(options.IssueKeys),
+		dependencyBots:    newDependencyBotMatcher(options.DependencyUpdates),
 	}
 }
 
 // Aggregates commits, comments, reviews, and timing data into comprehensive metrics
-func (c *PRMetricsCalculator) CalculatePRMetrics(owner, repo string, pr *github.PullRequest) (*api.PRMetrics, error) {
-	c.logger.Debug("Calculating metrics for PR #%d: %s", pr.GetNumber(), pr.GetTitle())
+func (c *PRMetricsCalculator) CalculatePRMetrics(owner, repo string, pr *model.PullRequest) (*api.PRMetrics, error) {
+	c.logger.Debug("Calculating metrics for PR #%d: %s", pr.Number, pr.Title)
 
 	metrics := api.PRMetrics{
-		Number:    pr.GetNumber(),
-		Title:     pr.GetTitle(),
-		Author:    pr.User.GetLogin(),
-		CreatedAt: pr.GetCreatedAt().Time,
-		MergedAt:  pr.GetMergedAt().Time,
-		State:     pr.GetState(),
+		Number:    pr.Number,
+		Title:     pr.Title,
+		Author:    pr.Author,
+		CreatedAt: pr.CreatedAt,
+		MergedAt:  pr.MergedAt,
+		State:     pr.State,
+		Milestone: pr.Milestone,
+		Labels:    slices.Clone(pr.Labels),
 	}
-
-	// Get milestone information
-	if pr.Milestone != nil {
-		metrics.Milestone = pr.Milestone.GetTitle()
+	metrics.Category = c.categories.classify(pr)
+	metrics.IssueKeys = c.issueKeys.extract(pr)
+	metrics.DependencyUpdate = c.dependencyBots.matches(metrics.Author)
+
+	// Record the branches for stack detection; a fork's branch cannot be the base of another PR,
+	// so it is qualified with the fork owner to keep it from matching one
+	metrics.BaseBranch = pr.BaseBranch
+	metrics.HeadBranch = pr.HeadBranch
+	if pr.HeadRepo != "" && pr.BaseRepo != "" && pr.HeadRepo != pr.BaseRepo {
+		metrics.HeadBranch = pr.HeadLabel
 	}
 
-	// Get PR details for additions, deletions, and changed files
-	additions, deletions, changedFiles, err := c.calculatePRDetails(owner, repo, pr.GetNumber())
+	// Get PR details for additions, deletions, changed files, and merger
+	details, err := c.calculatePRDetails(owner, repo, pr.Number)
 	if err != nil {
-		return nil, err
+		return nil, &StageError{Stage: StageDetails, Err: err}
+	}
+	metrics.Additions = details.Additions
+	metrics.Deletions = details.Deletions
+	metrics.ChangedFiles = details.ChangedFiles
+	metrics.MergedBy = details.MergedBy
+
+	// Some providers only report the exact merge time on the detail endpoint
+	if !details.MergedAt.IsZero() {
+		metrics.MergedAt = details.MergedAt
+	}
+	if !metrics.MergedAt.IsZero() {
+		metrics.AutoMerged = isAutoMerged(pr, metrics.MergedBy)
 	}
-	metrics.Additions = additions
-	metrics.Deletions = deletions
-	metrics.ChangedFiles = changedFiles
 
 	// Get commits and calculate commit-related metrics
-	commits, err := c.client.GetPRCommits(owner, repo, pr.GetNumber())
+	commits, err := c.client.GetPRCommits(owner, repo, pr.Number)
 	if err != nil {
-		return nil, err
+		return nil, &StageError{Stage: StageCommits, Err: err}
 	}
-	commitMetrics := c.calculateCommitMetrics(commits, metrics.CreatedAt)
+	commitTimes := c.resolveCommitTimes(commits, metrics.MergedAt)
+	metrics.CommitDateSkew = commitTimes.Skewed
+	if commitTimes.Skewed {
+		c.logger.With("pr", pr.Number, "stage", StageCommits).Warn("PR #%d has commits dated after its merge, likely from a rebase or squash", pr.Number)
+	}
+	commitMetrics := c.calculateCommitMetrics(commitTimes.Times, metrics.CreatedAt)
 	metrics.CommitCount = commitMetrics.CommitCount
 	metrics.FirstCommitAt = commitMetrics.FirstCommitAt
 	metrics.LastCommitAt = commitMetrics.LastCommitAt
 	metrics.CommitCountDuringPR = commitMetrics.CommitCountDuringPR
+	metrics.BaseSyncMergeCount = countBaseSyncMerges(commits, pr.BaseBranch)
 
-	// Get comments and calculate comment-related metrics
-	comments, err := c.client.GetPRComments(owner, repo, pr.GetNumber())
+	// Get inline review comments and conversation comments, continuing with empty data on errors
+	comments, err := c.client.GetPRComments(owner, repo, pr.Number)
 	if err != nil {
-		c.logger.Warn("Failed to get comments for PR #%d: %v", pr.GetNumber(), err)
-		// Continue with empty comments data
-	} else {
-		commentMetrics := c.calculateCommentMetrics(comments)
-		metrics.CommentCount = commentMetrics.CommentCount
-		metrics.FirstCommentAt = commentMetrics.FirstCommentAt
+		c.logger.With("pr", pr.Number, "stage", StageComments).Warn("Failed to get comments for PR #%d: %v", pr.Number, err)
+		c.recordError(pr.Number, StageComments, err, false)
 	}
+	issueComments, err := c.client.GetPRIssueComments(owner, repo, pr.Number)
+	if err != nil {
+		c.logger.With("pr", pr.Number, "stage", StageIssueComments).Warn("Failed to get conversation comments for PR #%d: %v", pr.Number, err)
+		c.recordError(pr.Number, StageIssueComments, err, false)
+	}
+
+	// Calculate comment-related metrics
+	commentMetrics := c.calculateCommentMetrics(comments, issueComments)
+	metrics.ReviewCommentCount = commentMetrics.ReviewCommentCount
+	metrics.ConversationCommentCount = commentMetrics.ConversationCommentCount
+	metrics.FirstCommentAt = commentMetrics.FirstCommentAt
+	metrics.FirstInlineCommentAt = commentMetrics.FirstInlineCommentAt
 
 	// Calculate review-related metrics
-	reviewMetrics, err := c.calculateReviewMetrics(owner, repo, pr.GetNumber())
+	reviews, err := c.client.GetPRReviews(owner, repo, pr.Number)
 	if err != nil {
 		// Continue with empty reviews data if there's an error
-		c.logger.Warn("Failed to get reviews for PR #%d: %v", pr.GetNumber(), err)
-	} else {
-		metrics.ReviewCount = reviewMetrics.ReviewCount
-		metrics.ApprovalCount = reviewMetrics.ApprovalCount
+		c.logger.With("pr", pr.Number, "stage", StageReviews).Warn("Failed to get reviews for PR #%d: %v", pr.Number, err)
+		c.recordError(pr.Number, StageReviews, err, false)
+	}
+	reviewMetrics := c.calculateReviewMetrics(reviews, metrics.Author)
+	metrics.ReviewCount = reviewMetrics.ReviewCount
+	metrics.ApprovalCount = reviewMetrics.ApprovalCount
+	metrics.ApproverCount = len(reviewMetrics.Approvers)
+	metrics.Reviewers = c.calculateReviewerResponses(reviews, metrics.Author, metrics.CreatedAt)
+
+	// Calculate time to first approval and from the first to the final approval
+	if !reviewMetrics.FirstApprovalAt.IsZero() {
+		metrics.TimeToApprovalHours = c.hoursBetween(metrics.CreatedAt, reviewMetrics.FirstApprovalAt)
+		metrics.FirstToLastApprovalHours = c.hoursBetween(reviewMetrics.FirstApprovalAt, reviewMetrics.LastApprovalAt)
+	}
+
+	// Flag merges that bypassed review
+	if !metrics.MergedAt.IsZero() {
+		metrics.SelfMerged = metrics.MergedBy != "" && metrics.MergedBy == metrics.Author
+		metrics.UnreviewedMerge = metrics.ApprovalCount == 0
+	}
+
+	// Evaluate branch protection compliance for merged PRs
+	if !metrics.MergedAt.IsZero() {
+		compliance := c.calculateComplianceMetrics(owner, repo, pr, metrics.ApprovalCount)
+		metrics.RequiredApprovals = compliance.RequiredApprovals
+		metrics.ReviewRequirementMet = compliance.ReviewRequirementMet
+		metrics.StatusChecksMet = compliance.StatusChecksMet
+		metrics.ComplianceStatus = compliance.Status
+	}
 
-		// Calculate time to first approval
-		if !reviewMetrics.FirstApprovalAt.IsZero() {
-			metrics.TimeToApprovalHours = reviewMetrics.FirstApprovalAt.Sub(metrics.CreatedAt).Hours()
+	// Measure how long the CI checks of the head commit waited for a runner, and how often they
+	// were re-run after failing
+	if c.options.CIMetrics && pr.HeadSHA != "" {
+		checkRuns, err := c.client.GetCheckRuns(owner, repo, pr.HeadSHA)
+		if err != nil {
+			c.logger.With("pr", pr.Number, "stage", StageCheckRuns).Warn("Failed to get check runs for PR #%d: %v", pr.Number, err)
+			c.recordError(pr.Number, StageCheckRuns, err, false)
+		} else {
+			metrics.CIQueueHours = calculateCIQueueHours(pr, commits, checkRuns)
+			metrics.FlakyCheckRetries = countFlakyCheckRetries(checkRuns)
 		}
 	}
 
@@ -102,28 +247,161 @@ func (c *PRMetricsCalculator) CalculatePRMetrics(owner, repo string, pr *github.
 	metrics.TotalPRLifetimeHours = timeMetrics.TotalPRLifetimeHours
 	metrics.CreatedToFirstCommentHours = timeMetrics.CreatedToFirstCommentHours
 
+	// Measure the lead time from the creation of the referenced issue to the merge
+	if c.options.Jira != nil && len(metrics.IssueKeys) > 0 {
+		issueCreatedAt, err := c.getIssueCreatedAt(metrics.IssueKeys)
+		if err != nil {
+			c.logger.With("pr", pr.Number, "stage", StageIssueTracker).Warn("Failed to get issues %s for PR #%d: %v", strings.Join(metrics.IssueKeys, ", "), pr.Number, err)
+			c.recordError(pr.Number, StageIssueTracker, err, false)
+		} else {
+			metrics.IssueCreatedAt = issueCreatedAt
+			if !issueCreatedAt.IsZero() && !metrics.MergedAt.IsZero() && issueCreatedAt.Before(metrics.MergedAt) {
+				metrics.IssueLeadTimeHours = c.hoursBetween(issueCreatedAt, metrics.MergedAt)
+			}
+		}
+	}
+
+	// Break the PR lifetime down into phases
+	phases := c.calculateLifecyclePhases(
+		metrics.FirstCommitAt,
+		metrics.CreatedAt,
+		reviewMetrics.FirstReviewAt,
+		reviewMetrics.FirstApprovalAt,
+		metrics.MergedAt,
+	)
+	metrics.CodingHours = phases.CodingHours
+	metrics.WaitingForReviewHours = phases.WaitingForReviewHours
+	metrics.InReviewHours = phases.InReviewHours
+	metrics.WaitingToMergeHours = phases.WaitingToMergeHours
+
+	// Calculate review coverage from changed files and review comment paths
+	var codeOwnerApprovers []string
+	files, err := c.client.GetPRFiles(owner, repo, pr.Number)
+	if err != nil {
+		c.logger.With("pr", pr.Number, "stage", StageFiles).Warn("Failed to get files for PR #%d: %v", pr.Number, err)
+		c.recordError(pr.Number, StageFiles, err, false)
+		metrics.ReviewCoveragePercent = ReviewCoverageUnknown
+	} else {
+		metrics.ReviewCoveragePercent = c.calculateReviewCoverage(files, comments)
+		metrics.Files = c.calculateFileMetrics(files, comments)
+		metrics.TestChangeRatio = c.calculateTestChangeRatio(files)
+		sizedFiles := files
+		if !c.options.SizeExclusions.IsEmpty() {
+			sizedFiles = c.excludeFromSize(&metrics, owner, repo, pr, files)
+		}
+		metrics.Languages = c.calculateLanguages(sizedFiles)
+
+		// Find the code owners of the changed files and count their approvals
+		codeOwners, err := c.getCodeOwners(owner, repo, pr.BaseBranch, pr.Number)
+		if err == nil {
+			metrics.CodeOwners = c.collectCodeOwners(codeOwners, files, metrics.Author)
+			codeOwnerApprovers = c.findCodeOwnerApprovers(codeOwners, files, reviewMetrics.Approvers)
+			metrics.CodeOwnerApprovalCount = len(codeOwnerApprovers)
+			metrics.NonOwnerApprovalCount = len(reviewMetrics.Approvers) - len(codeOwnerApprovers)
+			metrics.OwnedFileCount, metrics.CodeOwnerCoveragePercent = c.calculateCodeOwnerCoverage(codeOwners, files, reviewMetrics.Approvers)
+		}
+	}
+
+	// Count the review threads left unresolved
+	threads, err := c.client.GetPRReviewThreads(owner, repo, pr.Number)
+	if err != nil {
+		c.logger.With("pr", pr.Number, "stage", StageReviewThreads).Warn("Failed to get review threads for PR #%d: %v", pr.Number, err)
+		c.recordError(pr.Number, StageReviewThreads, err, false)
+	} else {
+		metrics.ReviewThreadCount = len(threads)
+		for _, thread := range threads {
+			if thread.Resolved {
+				metrics.ResolvedThreadCount++
+			} else {
+				metrics.UnresolvedThreadCount++
+			}
+		}
+	}
+
+	// Count how often the PR was closed and reopened, and who was asked to review
+	timelineEvents, err := c.client.GetPRTimelineEvents(owner, repo, pr.Number)
+	if err != nil {
+		c.logger.With("pr", pr.Number, "stage", StageTimelineEvents).Warn("Failed to get timeline events for PR #%d: %v", pr.Number, err)
+		c.recordError(pr.Number, StageTimelineEvents, err, false)
+	}
+	for _, event := range timelineEvents {
+		if event.Type == model.TimelineEventReopened {
+			metrics.ReopenCount++
+		}
+	}
+	metrics.Assignees = slices.Clone(pr.Assignees)
+	metrics.RequestedReviewers, metrics.RequestedTeams = c.calculateReviewRequests(pr, timelineEvents)
+	metrics.RequestedReviewerCount = len(metrics.RequestedReviewers) + len(metrics.RequestedTeams)
+	for _, review := range reviews {
+		if slices.Contains(metrics.RequestedReviewers, review.Reviewer) {
+			metrics.RequestedReviewerReviewed = true
+			break
+		}
+	}
+
+	// Analyze the commits in the local clone if one was given
+	if c.options.LocalGit != nil && len(commits) > 0 {
+		if err := c.calculateLocalGitMetrics(&metrics, pr, commits); err != nil {
+			c.logger.With("pr", pr.Number, "stage", StageLocalGit).Warn("Failed to analyze PR #%d in the local clone: %v", pr.Number, err)
+			c.recordError(pr.Number, StageLocalGit, err, false)
+		}
+	}
+
 	// Calculate waiting periods
 	if len(commits) > 0 && len(comments) > 0 {
-		waitingPeriods := c.calculateWaitingPeriods(commits, comments)
+		waitingPeriods := c.calculateWaitingPeriods(commitTimes.Times, comments)
 		metrics.MaxNoActivityPeriodHours = waitingPeriods.MaxNoActivityPeriodHours
 		metrics.MaxNoCommentPeriodHours = waitingPeriods.MaxNoCommentPeriodHours
 		metrics.MaxNoCommitPeriodHours = waitingPeriods.MaxNoCommitPeriodHours
 	}
 
+	// Calculate how quickly the author responds to reviewer feedback
+	metrics.AuthorResponseLatencyHours = c.calculateAuthorResponseLatency(metrics.Author, commitTimes.Times, comments, issueComments, reviews)
+
+	// Calculate how quickly reviewers return to the PR after new commits
+	metrics.ReReviewLatencyHours, metrics.ReReviewCount = c.calculateReReviewLatency(metrics.Author, commitTimes.Times, comments, reviews)
+
+	// Count approvals that new commits made stale
+	metrics.StaleApprovalCount = c.countStaleApprovals(metrics.Author, commitTimes.Times, reviews)
+	metrics.CommitsAfterApproval = c.countCommitsAfterApproval(metrics.Author, commitTimes.Times, reviews, metrics.MergedAt)
+
+	// Build the normalized event stream, flagging the approvals of code owners
+	metrics.Events = c.buildEvents(&metrics, commits, commitTimes.Times, comments, issueComments, reviews, timelineEvents)
+	for i := range metrics.Events {
+		if metrics.Events[i].Type == api.EventTypeApproval && slices.Contains(codeOwnerApprovers, metrics.Events[i].Actor) {
+			metrics.Events[i].CodeOwner = true
+		}
+	}
+
 	c.logger.Debug("Calculated metrics for PR #%d: %d commits, %d comments, %d reviews, %d approvals",
-		pr.GetNumber(), metrics.CommitCount, metrics.CommentCount, metrics.ReviewCount, metrics.ApprovalCount)
+		pr.Number, metrics.CommitCount, metrics.ReviewCommentCount+metrics.ConversationCommentCount, metrics.ReviewCount, metrics.ApprovalCount)
 
 	return &metrics, nil
 }
 
-// Fetches additions, deletions, and changed files count from GitHub API
-func (c *PRMetricsCalculator) calculatePRDetails(owner, repo string, number int) (int, int, int, error) {
+// PRDetailsResult contains size and merge data only available from the PR detail endpoint
+type PRDetailsResult struct {
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	MergedBy     string
+	MergedAt     time.Time
+}
+
+// Fetches additions, deletions, changed files count, and merger from GitHub API
+func (c *PRMetricsCalculator) calculatePRDetails(owner, repo string, number int) (PRDetailsResult, error) {
 	prDetails, err := c.client.GetPRDetails(owner, repo, number)
 	if err != nil {
-		return 0, 0, 0, err
+		return PRDetailsResult{}, err
 	}
 
-	return prDetails.GetAdditions(), prDetails.GetDeletions(), prDetails.GetChangedFiles(), nil
+	return PRDetailsResult{
+		Additions:    prDetails.Additions,
+		Deletions:    prDetails.Deletions,
+		ChangedFiles: prDetails.ChangedFiles,
+		MergedBy:     prDetails.MergedBy,
+		MergedAt:     prDetails.MergedAt,
+	}, nil
 }
 
 // CommitMetricsResult contains timing and frequency data for commits
@@ -134,32 +412,55 @@ type CommitMetricsResult struct {
 	CommitCountDuringPR int
 }
 
-// Processes commit timestamps to derive timing and frequency metrics
-func (c *PRMetricsCalculator) calculateCommitMetrics(commits []*github.RepositoryCommit, createdAt time.Time) CommitMetricsResult {
-	result := CommitMetricsResult{
-		CommitCount: len(commits),
-	}
+// CommitTimesResult contains the effective time of each commit and whether any looked skewed
+type CommitTimesResult struct {
+	Times  []time.Time // Aligned with the commit list; zero when the commit has no date
+	Skewed bool
+}
 
-	if len(commits) > 0 {
-		firstCommit := commits[0]
-		lastCommit := commits[len(commits)-1]
+// Resolves each commit's time from the configured date source, flagging commits dated after
+// the merge and clamping them to the merge time when requested
+func (c *PRMetricsCalculator) resolveCommitTimes(commits []*model.CommitEvent, mergedAt time.Time) CommitTimesResult {
+	result := CommitTimesResult{
+		Times: make([]time.Time, len(commits)),
+	}
 
-		if firstCommit.Commit != nil && firstCommit.Commit.Author != nil && firstCommit.Commit.Author.Date != nil {
-			result.FirstCommitAt = firstCommit.Commit.Author.GetDate().Time
+	for i, commit := range commits {
+		commitTime := commit.AuthoredAt
+		if c.options.CommitDateSource == CommitDateSourceCommitter {
+			commitTime = commit.CommittedAt
+		}
+		if commitTime.IsZero() {
+			continue
 		}
 
-		if lastCommit.Commit != nil && lastCommit.Commit.Author != nil && lastCommit.Commit.Author.Date != nil {
-			result.LastCommitAt = lastCommit.Commit.Author.GetDate().Time
+		if !mergedAt.IsZero() && commitTime.After(mergedAt) {
+			result.Skewed = true
+			if c.options.ClampCommitTimes {
+				commitTime = mergedAt
+			}
 		}
+		result.Times[i] = commitTime
+	}
+
+	return result
+}
+
+// Processes commit timestamps to derive timing and frequency metrics
+func (c *PRMetricsCalculator) calculateCommitMetrics(commitTimes []time.Time, createdAt time.Time) CommitMetricsResult {
+	result := CommitMetricsResult{
+		CommitCount: len(commitTimes),
+	}
+
+	if len(commitTimes) > 0 {
+		result.FirstCommitAt = commitTimes[0]
+		result.LastCommitAt = commitTimes[len(commitTimes)-1]
 
 		// Count commits made during PR (after PR creation)
 		commitsDuringPR := 0
-		for _, commit := range commits {
-			if commit.Commit != nil && commit.Commit.Author != nil && commit.Commit.Author.Date != nil {
-				commitTime := commit.Commit.Author.GetDate().Time
-				if !commitTime.Before(createdAt) {
-					commitsDuringPR++
-				}
+		for _, commitTime := range commitTimes {
+			if !commitTime.IsZero() && !commitTime.Before(createdAt) {
+				commitsDuringPR++
 			}
 		}
 		result.CommitCountDuringPR = commitsDuringPR
@@ -168,41 +469,316 @@ func (c *PRMetricsCalculator) calculateCommitMetrics(commits []*github.Repositor
 	return result
 }
 
-// CommentMetricsResult contains comment count and timing data
+// CommentMetricsResult contains comment counts and timing data
 type CommentMetricsResult struct {
-	CommentCount   int
-	FirstCommentAt time.Time
+	ReviewCommentCount       int
+	ConversationCommentCount int
+	FirstCommentAt           time.Time // Of inline comments, like the comment gaps of the waiting periods
+	FirstInlineCommentAt     time.Time
 }
 
-// Extracts comment count and first comment timing
-func (c *PRMetricsCalculator) calculateCommentMetrics(comments []*github.PullRequestComment) CommentMetricsResult {
+// Extracts inline and conversation comment counts and first comment timings. Conversation
+// comments are only counted, since author replies and bot reports among them say nothing
+// about when reviewers first responded.
+func (c *PRMetricsCalculator) calculateCommentMetrics(comments []*model.CommentEvent, issueComments []*model.CommentEvent) CommentMetricsResult {
 	result := CommentMetricsResult{
-		CommentCount: len(comments),
+		ReviewCommentCount:       len(comments),
+		ConversationCommentCount: len(issueComments),
 	}
 
-	if len(comments) > 0 {
-		result.FirstCommentAt = comments[0].GetCreatedAt().Time
+	for _, comment := range comments {
+		createdAt := comment.CreatedAt
+		if result.FirstInlineCommentAt.IsZero() || createdAt.Before(result.FirstInlineCommentAt) {
+			result.FirstInlineCommentAt = createdAt
+		}
 	}
 
+	result.FirstCommentAt = result.FirstInlineCommentAt
+
 	return result
 }
 
+// Computes the median hours between each reviewer comment and the author's next commit or comment
+func (c *PRMetricsCalculator) calculateAuthorResponseLatency(author string, commitTimes []time.Time, comments []*model.CommentEvent, issueComments []*model.CommentEvent, reviews []*model.ReviewEvent) float64 {
+	var feedbackTimes, responseTimes []time.Time
+	addComment := func(login string, createdAt time.Time) {
+		if login == author {
+			responseTimes = append(responseTimes, createdAt)
+		} else {
+			feedbackTimes = append(feedbackTimes, createdAt)
+		}
+	}
+
+	for _, comment := range comments {
+		addComment(comment.Author, comment.CreatedAt)
+	}
+	for _, comment := range issueComments {
+		addComment(comment.Author, comment.CreatedAt)
+	}
+	for _, review := range reviews {
+		// Approvals need no response
+		if review.Reviewer != author && review.State != model.ReviewStateApproved {
+			feedbackTimes = append(feedbackTimes, review.SubmittedAt)
+		}
+	}
+	responseTimes = append(responseTimes, commitTimes...)
+
+	if len(feedbackTimes) == 0 || len(responseTimes) == 0 {
+		return 0
+	}
+	sort.Slice(responseTimes, func(i, j int) bool {
+		return responseTimes[i].Before(responseTimes[j])
+	})
+
+	var latencies []float64
+	for _, feedbackAt := range feedbackTimes {
+		index := sort.Search(len(responseTimes), func(i int) bool {
+			return responseTimes[i].After(feedbackAt)
+		})
+		if index < len(responseTimes) {
+			latencies = append(latencies, c.hoursBetween(feedbackAt, responseTimes[index]))
+		}
+	}
+
+	return calculateMedianFloat(latencies)
+}
+
+// Measures the median hours reviewers took to return after the author pushed commits following a review,
+// from the last commit pushed between two review activities to the later activity, and counts these re-reviews
+func (c *PRMetricsCalculator) calculateReReviewLatency(author string, commitTimes []time.Time, comments []*model.CommentEvent, reviews []*model.ReviewEvent) (float64, int) {
+	var reviewTimes []time.Time
+	for _, review := range reviews {
+		if review.Reviewer != author && !review.SubmittedAt.IsZero() {
+			reviewTimes = append(reviewTimes, review.SubmittedAt)
+		}
+	}
+	for _, comment := range comments {
+		if comment.Author != author {
+			reviewTimes = append(reviewTimes, comment.CreatedAt)
+		}
+	}
+	if len(reviewTimes) < 2 || len(commitTimes) == 0 {
+		return 0, 0
+	}
+
+	sortedCommitTimes := slices.Clone(commitTimes)
+	slices.SortFunc(sortedCommitTimes, time.Time.Compare)
+	slices.SortFunc(reviewTimes, time.Time.Compare)
+
+	var latencies []float64
+	for i := 1; i < len(reviewTimes); i++ {
+		previousReviewAt, reviewAt := reviewTimes[i-1], reviewTimes[i]
+		index := sort.Search(len(sortedCommitTimes), func(j int) bool {
+			return !sortedCommitTimes[j].Before(reviewAt)
+		})
+		if index > 0 && sortedCommitTimes[index-1].After(previousReviewAt) {
+			latencies = append(latencies, c.hoursBetween(sortedCommitTimes[index-1], reviewAt))
+		}
+	}
+
+	return calculateMedianFloat(latencies), len(latencies)
+}
+
+// Counts the commits pushed after the final approval and, for merged PRs, before the merge,
+// which landed without anyone approving them
+func (c *PRMetricsCalculator) countCommitsAfterApproval(author string, commitTimes []time.Time, reviews []*model.ReviewEvent, mergedAt time.Time) int {
+	var lastApprovalAt time.Time
+	for _, review := range reviews {
+		if review.State == model.ReviewStateApproved && review.Reviewer != author && review.SubmittedAt.After(lastApprovalAt) {
+			lastApprovalAt = review.SubmittedAt
+		}
+	}
+	if lastApprovalAt.IsZero() {
+		return 0
+	}
+
+	count := 0
+	for _, commitTime := range commitTimes {
+		if commitTime.After(lastApprovalAt) && (mergedAt.IsZero() || !commitTime.After(mergedAt)) {
+			count++
+		}
+	}
+	return count
+}
+
+// Matches the subject git gives a merge of another branch, such as "Merge branch 'main' into
+// feature" or "Merge remote-tracking branch 'origin/main'", capturing the merged branch
+var mergeMessagePattern = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'`)
+
+// Counts the commits that merged the base branch into the PR branch, a proxy for the churn of
+// keeping up with the base and resolving conflicts. A merge commit counts unless its message
+// names another branch, so merges made by the "Update branch" button or git pull are included.
+func countBaseSyncMerges(commits []*model.CommitEvent, baseBranch string) int {
+	count := 0
+	for _, commit := range commits {
+		subject, _, _ := strings.Cut(commit.Message, "\n")
+		if match := mergeMessagePattern.FindStringSubmatch(subject); match != nil {
+			if strings.TrimPrefix(match[1], "origin/") == baseBranch {
+				count++
+			}
+			continue
+		}
+		if commit.ParentCount > 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// Counts the approvals submitted before the last commit, which a branch protection rule that
+// dismisses stale reviews would have dismissed
+func (c *PRMetricsCalculator) countStaleApprovals(author string, commitTimes []time.Time, reviews []*model.ReviewEvent) int {
+	var lastCommitAt time.Time
+	for _, commitTime := range commitTimes {
+		if commitTime.After(lastCommitAt) {
+			lastCommitAt = commitTime
+		}
+	}
+
+	count := 0
+	for _, review := range reviews {
+		if review.State == model.ReviewStateApproved && review.Reviewer != author && review.SubmittedAt.Before(lastCommitAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// Collects the users and teams asked to review, replaying request events since GitHub drops
+// reviewers from the pending requests of the PR once they review
+func (c *PRMetricsCalculator) calculateReviewRequests(pr *model.PullRequest, timelineEvents []*model.TimelineEvent) ([]string, []string) {
+	var reviewers, teams []string
+	for _, event := range timelineEvents {
+		switch event.Type {
+		case model.TimelineEventReviewRequested:
+			if login := event.RequestedReviewer; login != "" && !slices.Contains(reviewers, login) {
+				reviewers = append(reviewers, login)
+			}
+			if team := event.RequestedTeam; team != "" && !slices.Contains(teams, team) {
+				teams = append(teams, team)
+			}
+		case model.TimelineEventReviewRequestRemoved:
+			reviewers = slices.DeleteFunc(reviewers, func(login string) bool { return login == event.RequestedReviewer })
+			teams = slices.DeleteFunc(teams, func(team string) bool { return team == event.RequestedTeam })
+		}
+	}
+
+	// Pending requests cover providers without request events
+	for _, login := range pr.RequestedReviewers {
+		if !slices.Contains(reviewers, login) {
+			reviewers = append(reviewers, login)
+		}
+	}
+	for _, team := range pr.RequestedTeams {
+		if !slices.Contains(teams, team) {
+			teams = append(teams, team)
+		}
+	}
+	return reviewers, teams
+}
+
+// Measures the hours between two times, counting only working hours when a business calendar is set
+func (c *PRMetricsCalculator) hoursBetween(from, to time.Time) float64 {
+	if c.options.Calendar != nil {
+		return c.options.Calendar.BusinessHours(from, to)
+	}
+	return to.Sub(from).Hours()
+}
+
+// Review coverage of a PR whose changed files could not be fetched, kept out of the aggregates
+const ReviewCoverageUnknown = -1.0
+
+// Computes the percentage of changed files that received at least one review comment
+func (c *PRMetricsCalculator) calculateReviewCoverage(files []*model.FileChange, comments []*model.CommentEvent) float64 {
+	if len(files) == 0 {
+		return 0
+	}
+
+	commentedPaths := make(map[string]bool)
+	for _, comment := range comments {
+		if comment.Path != "" {
+			commentedPaths[comment.Path] = true
+		}
+	}
+
+	coveredFiles := 0
+	for _, file := range files {
+		if commentedPaths[file.Path] {
+			coveredFiles++
+		}
+	}
+
+	return float64(coveredFiles) / float64(len(files)) * 100
+}
+
+// Lists the changed files with their churn and the number of review comments on each
+func (c *PRMetricsCalculator) calculateFileMetrics(files []*model.FileChange, comments []*model.CommentEvent) []api.PRFile {
+	commentCounts := make(map[string]int)
+	for _, comment := range comments {
+		if comment.Path != "" {
+			commentCounts[comment.Path]++
+		}
+	}
+
+	prFiles := make([]api.PRFile, 0, len(files))
+	for _, file := range files {
+		prFiles = append(prFiles, api.PRFile{
+			Path:               file.Path,
+			Additions:          file.Additions,
+			Deletions:          file.Deletions,
+			ReviewCommentCount: commentCounts[file.Path],
+		})
+	}
+
+	return prFiles
+}
+
+// Finds each reviewer's first review and how long after the PR was opened it came
+func (c *PRMetricsCalculator) calculateReviewerResponses(reviews []*model.ReviewEvent, author string, createdAt time.Time) []api.ReviewerResponse {
+	firstReviews := make(map[string]time.Time)
+	var reviewers []string
+	for _, review := range reviews {
+		reviewer := review.Reviewer
+		if reviewer == "" || reviewer == author {
+			continue
+		}
+
+		submittedAt := review.SubmittedAt
+		firstReviewAt, exists := firstReviews[reviewer]
+		if !exists {
+			reviewers = append(reviewers, reviewer)
+		}
+		if !exists || submittedAt.Before(firstReviewAt) {
+			firstReviews[reviewer] = submittedAt
+		}
+	}
+
+	responses := make([]api.ReviewerResponse, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		responses = append(responses, api.ReviewerResponse{
+			Reviewer:           reviewer,
+			FirstReviewAt:      firstReviews[reviewer],
+			HoursToFirstReview: c.hoursBetween(createdAt, firstReviews[reviewer]),
+		})
+	}
+
+	return responses
+}
+
 // ReviewMetricsResult contains review counts and approval timing data
 type ReviewMetricsResult struct {
 	ReviewCount     int
 	ApprovalCount   int
+	Approvers       []string // Distinct approvers other than the author, in order of approval
+	FirstReviewAt   time.Time
 	FirstApprovalAt time.Time
+	LastApprovalAt  time.Time
 }
 
-// Processes review states to count approvals and track approval timing
-func (c *PRMetricsCalculator) calculateReviewMetrics(owner, repo string, number int) (ReviewMetricsResult, error) {
+// Processes review states to count approvals and track review and approval timing
+func (c *PRMetricsCalculator) calculateReviewMetrics(reviews []*model.ReviewEvent, author string) ReviewMetricsResult {
 	result := ReviewMetricsResult{}
 
-	reviews, err := c.client.GetPRReviews(owner, repo, number)
-	if err != nil {
-		return result, err
-	}
-
 	result.ReviewCount = len(reviews)
 
 	// Calculate review-related metrics
@@ -210,12 +786,27 @@ func (c *PRMetricsCalculator) calculateReviewMetrics(owner, repo string, number
 	var firstApprovalAt time.Time
 
 	for _, review := range reviews {
-		if review.GetState() == "APPROVED" {
+		// Replies by the author to review threads are not reviews
+		if review.Reviewer != author {
+			if result.FirstReviewAt.IsZero() || review.SubmittedAt.Before(result.FirstReviewAt) {
+				result.FirstReviewAt = review.SubmittedAt
+			}
+		}
+
+		if review.State == model.ReviewStateApproved {
 			approvalCount++
 
-			// Record the time of the first approval
-			if firstApprovalAt.IsZero() || review.GetSubmittedAt().Before(firstApprovalAt) {
-				firstApprovalAt = review.GetSubmittedAt().Time
+			// Record the time of the first and final approval
+			if firstApprovalAt.IsZero() || review.SubmittedAt.Before(firstApprovalAt) {
+				firstApprovalAt = review.SubmittedAt
+			}
+			if review.SubmittedAt.After(result.LastApprovalAt) {
+				result.LastApprovalAt = review.SubmittedAt
+			}
+
+			approver := review.Reviewer
+			if approver != "" && approver != author && !slices.Contains(result.Approvers, approver) {
+				result.Approvers = append(result.Approvers, approver)
 			}
 		}
 	}
@@ -223,7 +814,337 @@ func (c *PRMetricsCalculator) calculateReviewMetrics(owner, repo string, number
 	result.ApprovalCount = approvalCount
 	result.FirstApprovalAt = firstApprovalAt
 
-	return result, nil
+	return result
+}
+
+// ComplianceMetricsResult contains whether a merge satisfied the base branch protection rules
+type ComplianceMetricsResult struct {
+	RequiredApprovals    int
+	ReviewRequirementMet bool
+	StatusChecksMet      bool
+	Status               string
+}
+
+// Compliance statuses reported per PR
+const (
+	ComplianceStatusCompliant    = "compliant"
+	ComplianceStatusNonCompliant = "non-compliant"
+	ComplianceStatusUnknown      = "unknown"
+)
+
+// Compares the approvals and status checks of a merged PR against its base branch protection.
+// Providers only return the current rules, so older merges are judged by rules that may have
+// changed since.
+func (c *PRMetricsCalculator) calculateComplianceMetrics(owner, repo string, pr *model.PullRequest, approvalCount int) ComplianceMetricsResult {
+	result := ComplianceMetricsResult{
+		Status: ComplianceStatusUnknown,
+	}
+
+	protection, err := c.getBranchProtection(owner, repo, pr.BaseBranch, pr.Number)
+	if err != nil {
+		return result
+	}
+
+	// Check required approving reviews
+	if protection != nil {
+		result.RequiredApprovals = protection.RequiredApprovals
+	}
+	result.ReviewRequirementMet = approvalCount >= result.RequiredApprovals
+
+	// Check required status checks
+	var requiredContexts []string
+	if protection != nil {
+		requiredContexts = protection.RequiredChecks
+	}
+
+	if len(requiredContexts) == 0 {
+		result.StatusChecksMet = true
+	} else {
+		passed, err := c.getPassedContexts(owner, repo, pr.HeadSHA)
+		if err != nil {
+			c.logger.With("pr", pr.Number, "stage", StageStatusChecks).Warn("Failed to get status checks for PR #%d: %v", pr.Number, err)
+			c.recordError(pr.Number, StageStatusChecks, err, false)
+			return result
+		}
+
+		result.StatusChecksMet = true
+		for _, context := range requiredContexts {
+			if !passed[context] {
+				result.StatusChecksMet = false
+				break
+			}
+		}
+	}
+
+	if result.ReviewRequirementMet && result.StatusChecksMet {
+		result.Status = ComplianceStatusCompliant
+	} else {
+		result.Status = ComplianceStatusNonCompliant
+	}
+
+	return result
+}
+
+// Fetches branch protection once per branch, warning and recording only the first failure
+func (c *PRMetricsCalculator) getBranchProtection(owner, repo, branch string, number int) (*model.BranchProtection, error) {
+	if lookup, exists := c.branchProtections[branch]; exists {
+		return lookup.protection, lookup.err
+	}
+
+	protection, err := c.client.GetBranchProtection(owner, repo, branch)
+	if err != nil {
+		c.logger.With("pr", number, "stage", StageBranchProtection).Warn("Failed to get branch protection for %s (compliance will be reported as unknown): %v", branch, err)
+		c.recordError(number, StageBranchProtection, err, false)
+	}
+
+	c.branchProtections[branch] = &branchProtectionLookup{
+		protection: protection,
+		err:        err,
+	}
+	return protection, err
+}
+
+// Fetches and parses CODEOWNERS once per branch, warning and recording only the first failure
+func (c *PRMetricsCalculator) getCodeOwners(owner, repo, branch string, number int) (*CodeOwners, error) {
+	if lookup, exists := c.codeOwners[branch]; exists {
+		return lookup.codeOwners, lookup.err
+	}
+
+	var codeOwners *CodeOwners
+	content, err := c.client.GetCodeOwners(owner, repo, branch)
+	if err != nil {
+		c.logger.With("pr", number, "stage", StageCodeOwners).Warn("Failed to get CODEOWNERS for %s (code owner approvals will be reported as zero): %v", branch, err)
+		c.recordError(number, StageCodeOwners, err, false)
+	} else {
+		codeOwners = ParseCodeOwners(content)
+	}
+
+	c.codeOwners[branch] = &codeOwnersLookup{
+		codeOwners: codeOwners,
+		err:        err,
+	}
+	return codeOwners, err
+}
+
+// Fetches and parses .gitattributes once per branch, warning and recording only the first failure
+func (c *PRMetricsCalculator) getGitAttributes(owner, repo, branch string, number int) (*GitAttributes, error) {
+	if lookup, exists := c.gitAttributes[branch]; exists {
+		return lookup.gitAttributes, lookup.err
+	}
+
+	var gitAttributes *GitAttributes
+	content, err := c.client.GetGitAttributes(owner, repo, branch)
+	if err != nil {
+		c.logger.With("pr", number, "stage", StageGitAttributes).Warn("Failed to get .gitattributes for %s (only the configured size exclusion patterns will apply): %v", branch, err)
+		c.recordError(number, StageGitAttributes, err, false)
+	} else {
+		gitAttributes = ParseGitAttributes(content)
+	}
+
+	c.gitAttributes[branch] = &gitAttributesLookup{
+		gitAttributes: gitAttributes,
+		err:           err,
+	}
+	return gitAttributes, err
+}
+
+// Subtracts the lines and files of excluded changed files from the PR size, returning the files left.
+// Subtracting rather than summing the file list keeps the size right when the API truncates the file
+// list of very large PRs.
+func (c *PRMetricsCalculator) excludeFromSize(metrics *api.PRMetrics, owner, repo string, pr *model.PullRequest, files []*model.FileChange) []*model.FileChange {
+	var gitAttributes *GitAttributes
+	if c.options.SizeExclusions.GitAttributes {
+		gitAttributes, _ = c.getGitAttributes(owner, repo, pr.BaseBranch, pr.Number)
+	}
+
+	var sizedFiles []*model.FileChange
+	for _, file := range files {
+		if !matchesAnyPathPattern(c.options.SizeExclusions.Patterns, file.Path) && !gitAttributes.Excludes(file.Path) {
+			sizedFiles = append(sizedFiles, file)
+			continue
+		}
+		metrics.Additions -= file.Additions
+		metrics.Deletions -= file.Deletions
+		metrics.ChangedFiles--
+		metrics.ExcludedFileCount++
+	}
+
+	// Some providers report detail counts that disagree with their file lists
+	metrics.Additions = max(metrics.Additions, 0)
+	metrics.Deletions = max(metrics.Deletions, 0)
+	metrics.ChangedFiles = max(metrics.ChangedFiles, 0)
+	return sizedFiles
+}
+
+// Lists the users other than the author who own at least one of the changed files, sorted.
+// Team and email owners are left out, since they cannot be matched to reviewers.
+func (c *PRMetricsCalculator) collectCodeOwners(codeOwners *CodeOwners, files []*model.FileChange, author string) []string {
+	var owners []string
+	for _, file := range files {
+		for _, owner := range codeOwners.Owners(file.Path) {
+			login, isUser := strings.CutPrefix(owner, "@")
+			if !isUser || strings.Contains(login, "/") || strings.EqualFold(login, author) || slices.Contains(owners, login) {
+				continue
+			}
+			owners = append(owners, login)
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// Returns the approvers who own at least one of the changed files
+func (c *PRMetricsCalculator) findCodeOwnerApprovers(codeOwners *CodeOwners, files []*model.FileChange, approvers []string) []string {
+	var owners []string
+	for _, approver := range approvers {
+		for _, file := range files {
+			if codeOwners.IsOwner(file.Path, approver) {
+				owners = append(owners, approver)
+				break
+			}
+		}
+	}
+	return owners
+}
+
+// Counts the changed files with a user owner and returns the share of them approved by one of
+// their owners. Files owned only by teams or email addresses are left out, since their owners
+// cannot be matched to approvers.
+func (c *PRMetricsCalculator) calculateCodeOwnerCoverage(codeOwners *CodeOwners, files []*model.FileChange, approvers []string) (int, float64) {
+	owned, covered := 0, 0
+	for _, file := range files {
+		hasUserOwner := slices.ContainsFunc(codeOwners.Owners(file.Path), func(owner string) bool {
+			login, isUser := strings.CutPrefix(owner, "@")
+			return isUser && !strings.Contains(login, "/")
+		})
+		if !hasUserOwner {
+			continue
+		}
+		owned++
+		if slices.ContainsFunc(approvers, func(approver string) bool { return codeOwners.IsOwner(file.Path, approver) }) {
+			covered++
+		}
+	}
+
+	if owned == 0 {
+		return 0, 0
+	}
+	return owned, float64(covered) / float64(owned) * 100
+}
+
+// Collects the names of commit statuses and check runs that passed on a commit
+func (c *PRMetricsCalculator) getPassedContexts(owner, repo, sha string) (map[string]bool, error) {
+	passed := make(map[string]bool)
+
+	statuses, err := c.client.GetCommitStatuses(owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if status.State == "success" {
+			passed[status.Context] = true
+		}
+	}
+
+	checkRuns, err := c.client.GetCheckRuns(owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	// Only the last run of a re-run check counts
+	for _, checkRun := range latestCheckRuns(checkRuns) {
+		switch checkRun.Conclusion {
+		case "success", "neutral", "skipped":
+			passed[checkRun.Name] = true
+		}
+	}
+
+	return passed, nil
+}
+
+// LifecyclePhasesResult contains the hours a PR spent in each phase of its lifecycle
+type LifecyclePhasesResult struct {
+	CodingHours           float64
+	WaitingForReviewHours float64
+	InReviewHours         float64
+	WaitingToMergeHours   float64
+}
+
+// Splits the PR lifecycle into coding (first commit to open), waiting for review (open to first
+// review), in review (first review to approval), and waiting to merge (approval to merge).
+// Phases whose boundaries did not happen are left at zero.
+func (c *PRMetricsCalculator) calculateLifecyclePhases(firstCommitAt, createdAt, firstReviewAt, firstApprovalAt, mergedAt time.Time) LifecyclePhasesResult {
+	result := LifecyclePhasesResult{}
+
+	// Commits made after opening the PR are not part of the coding phase
+	if !firstCommitAt.IsZero() && firstCommitAt.Before(createdAt) {
+		result.CodingHours = c.hoursBetween(firstCommitAt, createdAt)
+	}
+
+	if !firstReviewAt.IsZero() {
+		result.WaitingForReviewHours = c.hoursBetween(createdAt, firstReviewAt)
+
+		if !firstApprovalAt.IsZero() {
+			result.InReviewHours = c.hoursBetween(firstReviewAt, firstApprovalAt)
+		}
+	}
+
+	if !firstApprovalAt.IsZero() && !mergedAt.IsZero() {
+		result.WaitingToMergeHours = c.hoursBetween(firstApprovalAt, mergedAt)
+	}
+
+	return result
+}
+
+// Computes the share of changed lines in test files from the PR's file list
+func (c *PRMetricsCalculator) calculateTestChangeRatio(files []*model.FileChange) float64 {
+	paths := make([]string, len(files))
+	changedLines := make([]int, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+		changedLines[i] = file.Additions + file.Deletions
+	}
+	return c.testFiles.changeRatio(paths, changedLines)
+}
+
+// Computes touched functions, test change ratio, and renames from the PR's commits in the local clone
+func (c *PRMetricsCalculator) calculateLocalGitMetrics(metrics *api.PRMetrics, pr *model.PullRequest, commits []*model.CommitEvent) error {
+	repository := c.options.LocalGit
+	head := pr.HeadSHA
+	if head == "" {
+		head = commits[len(commits)-1].SHA
+	}
+	pullRefs := []string{
+		fmt.Sprintf("refs/pull/%d/head", pr.Number),           // GitHub, Gitea, Bitbucket Server
+		fmt.Sprintf("refs/merge-requests/%d/head", pr.Number), // GitLab
+	}
+	if err := repository.Ensure(head, pullRefs...); err != nil {
+		return err
+	}
+
+	// Without a recorded base commit, compare against the parent of the first commit
+	base := pr.BaseSHA
+	if base == "" || repository.Ensure(base) != nil {
+		base = commits[0].SHA + "^"
+	}
+
+	analysis, err := repository.Analyze(base, head)
+	if err != nil {
+		return err
+	}
+
+	// The clone sees every file, while the API truncates the file list of very large PRs
+	paths := make([]string, len(analysis.Files))
+	changedLines := make([]int, len(analysis.Files))
+	for i, file := range analysis.Files {
+		paths[i] = file.Path
+		changedLines[i] = file.Additions + file.Deletions
+		if file.Renamed {
+			metrics.RenamedFileCount++
+		}
+	}
+	metrics.TestChangeRatio = c.testFiles.changeRatio(paths, changedLines)
+	metrics.TouchedFunctionCount = analysis.TouchedFunctions
+	return nil
 }
 
 // TimeMetricsResult contains durations between key PR lifecycle events
@@ -242,31 +1163,31 @@ func (c *PRMetricsCalculator) calculateTimeMetrics(createdAt, mergedAt, firstCom
 
 	// Calculate first commit to PR creation time
 	if !firstCommitAt.IsZero() {
-		result.FirstCommitToCreateHours = createdAt.Sub(firstCommitAt).Hours()
+		result.FirstCommitToCreateHours = c.hoursBetween(firstCommitAt, createdAt)
 	}
 
 	// Calculate PR creation to last commit time
 	if !lastCommitAt.IsZero() {
-		result.CreateToLastCommitHours = lastCommitAt.Sub(createdAt).Hours()
+		result.CreateToLastCommitHours = c.hoursBetween(createdAt, lastCommitAt)
 	}
 
 	// Calculate merge-related time metrics
 	if !mergedAt.IsZero() {
 		if !firstCommitAt.IsZero() {
-			result.FirstCommitToMergeHours = mergedAt.Sub(firstCommitAt).Hours()
+			result.FirstCommitToMergeHours = c.hoursBetween(firstCommitAt, mergedAt)
 		}
 
 		if !lastCommitAt.IsZero() {
-			result.LastCommitToMergeHours = mergedAt.Sub(lastCommitAt).Hours()
+			result.LastCommitToMergeHours = c.hoursBetween(lastCommitAt, mergedAt)
 		}
 
 		// Calculate total PR lifetime
-		result.TotalPRLifetimeHours = mergedAt.Sub(createdAt).Hours()
+		result.TotalPRLifetimeHours = c.hoursBetween(createdAt, mergedAt)
 	}
 
 	// Calculate time from PR creation to first comment
 	if !firstCommentAt.IsZero() {
-		result.CreatedToFirstCommentHours = firstCommentAt.Sub(createdAt).Hours()
+		result.CreatedToFirstCommentHours = c.hoursBetween(createdAt, firstCommentAt)
 	}
 
 	return result
@@ -280,22 +1201,22 @@ type WaitingPeriodsResult struct {
 }
 
 // Identifies maximum gaps between commits, comments, and all activities
-func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.RepositoryCommit, comments []*github.PullRequestComment) WaitingPeriodsResult {
+func (c *PRMetricsCalculator) calculateWaitingPeriods(resolvedCommitTimes []time.Time, comments []*model.CommentEvent) WaitingPeriodsResult {
 	result := WaitingPeriodsResult{}
 
 	// Store commit and comment times in a sorted slice
 	var allEvents []time.Time
 
 	// Add commit times
-	for _, commit := range commits {
-		if commit.Commit != nil && commit.Commit.Author != nil && commit.Commit.Author.Date != nil {
-			allEvents = append(allEvents, commit.Commit.Author.GetDate().Time)
+	for _, commitTime := range resolvedCommitTimes {
+		if !commitTime.IsZero() {
+			allEvents = append(allEvents, commitTime)
 		}
 	}
 
 	// Add comment times
 	for _, comment := range comments {
-		allEvents = append(allEvents, comment.GetCreatedAt().Time)
+		allEvents = append(allEvents, comment.CreatedAt)
 	}
 
 	// Sort by time
@@ -310,9 +1231,9 @@ func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.Reposito
 
 	// Extract commit times only
 	var commitTimes []time.Time
-	for _, commit := range commits {
-		if commit.Commit != nil && commit.Commit.Author != nil && commit.Commit.Author.Date != nil {
-			commitTimes = append(commitTimes, commit.Commit.Author.GetDate().Time)
+	for _, commitTime := range resolvedCommitTimes {
+		if !commitTime.IsZero() {
+			commitTimes = append(commitTimes, commitTime)
 		}
 	}
 	sort.Slice(commitTimes, func(i, j int) bool {
@@ -322,7 +1243,7 @@ func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.Reposito
 	// Extract comment times only
 	var commentTimes []time.Time
 	for _, comment := range comments {
-		commentTimes = append(commentTimes, comment.GetCreatedAt().Time)
+		commentTimes = append(commentTimes, comment.CreatedAt)
 	}
 	sort.Slice(commentTimes, func(i, j int) bool {
 		return commentTimes[i].Before(commentTimes[j])
@@ -330,7 +1251,7 @@ func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.Reposito
 
 	// Calculate maximum interval between all activities
 	for i := 0; i < len(allEvents)-1; i++ {
-		gap := allEvents[i+1].Sub(allEvents[i]).Hours()
+		gap := c.hoursBetween(allEvents[i], allEvents[i+1])
 		if gap > maxNoActivityPeriod {
 			maxNoActivityPeriod = gap
 		}
@@ -338,7 +1259,7 @@ func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.Reposito
 
 	// Calculate maximum interval between comments
 	for i := 0; i < len(commentTimes)-1; i++ {
-		gap := commentTimes[i+1].Sub(commentTimes[i]).Hours()
+		gap := c.hoursBetween(commentTimes[i], commentTimes[i+1])
 		if gap > maxNoCommentPeriod {
 			maxNoCommentPeriod = gap
 		}
@@ -346,7 +1267,7 @@ func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.Reposito
 
 	// Calculate maximum interval between commits
 	for i := 0; i < len(commitTimes)-1; i++ {
-		gap := commitTimes[i+1].Sub(commitTimes[i]).Hours()
+		gap := c.hoursBetween(commitTimes[i], commitTimes[i+1])
 		if gap > maxNoCommitPeriod {
 			maxNoCommitPeriod = gap
 		}
@@ -359,18 +1280,138 @@ func (c *PRMetricsCalculator) calculateWaitingPeriods(commits []*github.Reposito
 	return result
 }
 
+// Merges commits, comments, reviews, and lifecycle events into a single time-ordered stream
+func (c *PRMetricsCalculator) buildEvents(metrics *api.PRMetrics, commits []*model.CommitEvent, commitTimes []time.Time, comments []*model.CommentEvent, issueComments []*model.CommentEvent, reviews []*model.ReviewEvent, timelineEvents []*model.TimelineEvent) []api.PREvent {
+	events := []api.PREvent{{
+		PRNumber:  metrics.Number,
+		Type:      api.EventTypeCreated,
+		Timestamp: metrics.CreatedAt,
+		Actor:     metrics.Author,
+	}}
+
+	for i, commit := range commits {
+		if commitTimes[i].IsZero() {
+			continue
+		}
+		actor := commit.AuthorLogin
+		if actor == "" {
+			actor = commit.AuthorName
+		}
+		events = append(events, api.PREvent{
+			PRNumber:  metrics.Number,
+			Type:      api.EventTypeCommit,
+			Timestamp: commitTimes[i],
+			Actor:     actor,
+			Detail:    commit.SHA,
+		})
+	}
+
+	for _, comment := range comments {
+		events = append(events, api.PREvent{
+			PRNumber:  metrics.Number,
+			Type:      api.EventTypeComment,
+			Timestamp: comment.CreatedAt,
+			Actor:     comment.Author,
+			Detail:    comment.Path,
+		})
+	}
+
+	for _, comment := range issueComments {
+		events = append(events, api.PREvent{
+			PRNumber:  metrics.Number,
+			Type:      api.EventTypeComment,
+			Timestamp: comment.CreatedAt,
+			Actor:     comment.Author,
+		})
+	}
+
+	for _, review := range reviews {
+		eventType := api.EventTypeReview
+		if review.State == model.ReviewStateApproved {
+			eventType = api.EventTypeApproval
+		}
+		events = append(events, api.PREvent{
+			PRNumber:  metrics.Number,
+			Type:      eventType,
+			Timestamp: review.SubmittedAt,
+			Actor:     review.Reviewer,
+			Detail:    review.State,
+		})
+	}
+
+	for _, timelineEvent := range timelineEvents {
+		eventType := api.EventTypeReopen
+		if timelineEvent.Type == model.TimelineEventClosed {
+			// GitHub also reports the close that accompanies the merge
+			if !metrics.MergedAt.IsZero() && !timelineEvent.CreatedAt.Before(metrics.MergedAt) {
+				continue
+			}
+			eventType = api.EventTypeClose
+		}
+		events = append(events, api.PREvent{
+			PRNumber:  metrics.Number,
+			Type:      eventType,
+			Timestamp: timelineEvent.CreatedAt,
+			Actor:     timelineEvent.Actor,
+		})
+	}
+
+	if !metrics.MergedAt.IsZero() {
+		events = append(events, api.PREvent{
+			PRNumber:  metrics.Number,
+			Type:      api.EventTypeMerge,
+			Timestamp: metrics.MergedAt,
+			Actor:     metrics.MergedBy,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return events
+}
+
 // Processes multiple PRs with error handling and progress logging
-func (c *PRMetricsCalculator) CalculateAllPRMetrics(owner, repo string, prs []*github.PullRequest) ([]*api.PRMetrics, error) {
+func (c *PRMetricsCalculator) CalculateAllPRMetrics(owner, repo string, prs []*model.PullRequest) ([]*api.PRMetrics, error) {
 	c.logger.Info("Calculating metrics for %d pull requests", len(prs))
 
 	var allMetrics []*api.PRMetrics
 
 	for i, pr := range prs {
-		c.logger.Debug("Processing PR #%d (%d/%d)", pr.GetNumber(), i+1, len(prs))
+		c.logger.Debug("Processing PR #%d (%d/%d)", pr.Number, i+1, len(prs))
 
+		recorded := len(c.errors)
 		metrics, err := c.CalculatePRMetrics(owner, repo, pr)
+		if c.options.Progress != nil {
+			c.options.Progress(i+1, len(prs))
+		}
+
+		// Stop once the request budget is used up, leaving out the PR that may be incomplete
+		if c.client.BudgetExhausted() {
+			c.logger.Warn("API request budget exhausted; stopping after %d of %d pull requests", i, len(prs))
+			break
+		}
+
+		if err != nil {
+			stage := "unknown"
+			var stageErr *StageError
+			if errors.As(err, &stageErr) {
+				stage = stageErr.Stage
+			}
+			c.logger.With("pr", pr.Number, "stage", stage).Error("Failed to calculate metrics for PR #%d: %v", pr.Number, err)
+			if stageErr != nil {
+				err = stageErr.Err
+			}
+			c.recordError(pr.Number, stage, err, true)
+		}
+
+		// Stop at the first failure under the fail policy, even one the PR could do without
+		if c.options.OnError == ErrorPolicyFail && len(c.errors) > recorded {
+			failure := c.errors[recorded]
+			return nil, fmt.Errorf("PR #%d failed at %s: %s", failure.PRNumber, failure.Stage, failure.Message)
+		}
 		if err != nil {
-			c.logger.Error("Failed to calculate metrics for PR #%d: %v", pr.GetNumber(), err)
 			continue
 		}
 
@@ -380,3 +1421,18 @@ func (c *PRMetricsCalculator) CalculateAllPRMetrics(owner, repo string, prs []*g
 	c.logger.Info("Successfully calculated metrics for %d/%d pull requests", len(allMetrics), len(prs))
 	return allMetrics, nil
 }
+
+// Records a failure so it can be reported at the end of the run
+func (c *PRMetricsCalculator) recordError(number int, stage string, err error, skipped bool) {
+	c.errors = append(c.errors, &api.PRError{
+		PRNumber: number,
+		Stage:    stage,
+		Message:  err.Error(),
+		Skipped:  skipped,
+	})
+}
+
+// Returns all failures recorded while calculating PR metrics
+func (c *PRMetricsCalculator) Errors() []*api.PRError {
+	return c.errors
+}
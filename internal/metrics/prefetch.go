@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Fetches every response metric calculation requests, without calculating anything, so that a
+// response cache or recording filled ahead of time serves later runs
+type Prefetcher struct {
+	client         model.Source
+	logger         *utils.Logger
+	gitAttributes  bool                // Also fetch .gitattributes, for size exclusions that use it
+	checkRuns      bool                // Also fetch the check runs of each head commit, for the CI metrics
+	branches       map[string]bool     // Base branches whose settings were fetched
+	requiredChecks map[string][]string // Required status checks of each base branch
+	errors         []*api.PRError
+}
+
+// Initializes prefetcher for the source, fetching .gitattributes if size exclusions read it and
+// check runs if the CI metrics are measured
+func NewPrefetcher(client model.Source, logger *utils.Logger, gitAttributes, checkRuns bool) *Prefetcher {
+	return &Prefetcher{
+		client:         client,
+		logger:         logger,
+		gitAttributes:  gitAttributes,
+		checkRuns:      checkRuns,
+		branches:       make(map[string]bool),
+		requiredChecks: make(map[string][]string),
+	}
+}
+
+// Fetches the responses of each PR in turn, stopping early if the request budget runs out, and
+// returns how many PRs were fetched completely
+func (p *Prefetcher) PrefetchAll(owner, repo string, prs []*model.PullRequest) int {
+	p.logger.Info("Prefetching responses for %d pull requests", len(prs))
+
+	fetched := 0
+	for i, pr := range prs {
+		p.logger.Debug("Prefetching PR #%d (%d/%d)", pr.Number, i+1, len(prs))
+		complete := p.prefetch(owner, repo, pr)
+
+		if p.client.BudgetExhausted() {
+			p.logger.Warn("API request budget exhausted; stopping after %d of %d pull requests", i, len(prs))
+			break
+		}
+		if complete {
+			fetched++
+		}
+	}
+
+	p.logger.Info("Prefetched responses for %d/%d pull requests", fetched, len(prs))
+	return fetched
+}
+
+// Fetches the responses of one PR, recording failures, and reports whether all succeeded
+func (p *Prefetcher) prefetch(owner, repo string, pr *model.PullRequest) bool {
+	number := pr.Number
+	stages := []struct {
+		stage string
+		fetch func() error
+	}{
+		{StageDetails, func() error { _, err := p.client.GetPRDetails(owner, repo, number); return err }},
+		{StageCommits, func() error { _, err := p.client.GetPRCommits(owner, repo, number); return err }},
+		{StageComments, func() error { _, err := p.client.GetPRComments(owner, repo, number); return err }},
+		{StageIssueComments, func() error { _, err := p.client.GetPRIssueComments(owner, repo, number); return err }},
+		{StageReviews, func() error { _, err := p.client.GetPRReviews(owner, repo, number); return err }},
+		{StageFiles, func() error { _, err := p.client.GetPRFiles(owner, repo, number); return err }},
+		{StageReviewThreads, func() error { _, err := p.client.GetPRReviewThreads(owner, repo, number); return err }},
+		{StageTimelineEvents, func() error { _, err := p.client.GetPRTimelineEvents(owner, repo, number); return err }},
+	}
+
+	complete := true
+	for _, stage := range stages {
+		if err := stage.fetch(); err != nil {
+			p.logger.With("pr", number, "stage", stage.stage).Warn("Failed to prefetch %s for PR #%d: %v", stage.stage, number, err)
+			p.recordError(number, stage.stage, err)
+			complete = false
+		}
+	}
+
+	p.prefetchBranch(owner, repo, pr.BaseBranch, number)
+
+	if p.checkRuns && pr.HeadSHA != "" {
+		if _, err := p.client.GetCheckRuns(owner, repo, pr.HeadSHA); err != nil {
+			p.recordError(number, StageCheckRuns, err)
+		}
+	}
+
+	// Status checks are only read for merged PRs whose base branch requires some
+	if !pr.MergedAt.IsZero() && len(p.requiredChecks[pr.BaseBranch]) > 0 {
+		sha := pr.HeadSHA
+		if _, err := p.client.GetCommitStatuses(owner, repo, sha); err != nil {
+			p.recordError(number, StageStatusChecks, err)
+		} else if _, err := p.client.GetCheckRuns(owner, repo, sha); err != nil {
+			p.recordError(number, StageStatusChecks, err)
+		}
+	}
+	return complete
+}
+
+// Fetches the protection, CODEOWNERS, and .gitattributes of a base branch once. These are often
+// missing, so failures are recorded without counting against the PR.
+func (p *Prefetcher) prefetchBranch(owner, repo, branch string, number int) {
+	if p.branches[branch] {
+		return
+	}
+	p.branches[branch] = true
+
+	protection, err := p.client.GetBranchProtection(owner, repo, branch)
+	if err != nil {
+		p.recordError(number, StageBranchProtection, err)
+	} else if protection != nil {
+		p.requiredChecks[branch] = protection.RequiredChecks
+	}
+
+	if _, err := p.client.GetCodeOwners(owner, repo, branch); err != nil {
+		p.recordError(number, StageCodeOwners, err)
+	}
+	if p.gitAttributes {
+		if _, err := p.client.GetGitAttributes(owner, repo, branch); err != nil {
+			p.recordError(number, StageGitAttributes, err)
+		}
+	}
+}
+
+// Records a failure so it can be reported at the end of the run
+func (p *Prefetcher) recordError(number int, stage string, err error) {
+	p.errors = append(p.errors, &api.PRError{
+		PRNumber: number,
+		Stage:    stage,
+		Message:  err.Error(),
+	})
+}
+
+// Returns all failures recorded while prefetching
+func (p *Prefetcher) Errors() []*api.PRError {
+	return p.errors
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Running counts of one period's review routing
+type reviewRoutingCounts struct {
+	routing              *api.ReviewRouting
+	reviewedRequests     int
+	outsiderReviewers    int
+	codeOwnerPRRequests  int // Requests on PRs with code owners
+	codeOwnerRequests    int
+	codeOwnerPRReviewers int // Reviewers on PRs with code owners
+	codeOwnerReviewers   int
+}
+
+// Compares the requested reviewers, actual reviewers, and code owners of merged PRs per ISO
+// week and calendar month, grouping PRs by merge date like the aggregated metrics, to show how
+// well reviews are routed by hand or by an auto-assignment bot
+func CalculateReviewRouting(prMetrics []*api.PRMetrics) []*api.ReviewRouting {
+	var routings []*api.ReviewRouting
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		periods := make(map[string]*reviewRoutingCounts)
+		for _, pr := range prMetrics {
+			if pr.MergedAt.IsZero() || (len(pr.RequestedReviewers) == 0 && len(pr.Reviewers) == 0) {
+				continue
+			}
+
+			period, startDate, endDate := CalendarPeriod(pr.MergedAt, granularity)
+			counts, exists := periods[period]
+			if !exists {
+				counts = &reviewRoutingCounts{routing: &api.ReviewRouting{
+					Granularity: granularity,
+					Period:      period,
+					StartDate:   startDate,
+					EndDate:     endDate,
+				}}
+				periods[period] = counts
+			}
+			counts.add(pr)
+		}
+
+		periodRoutings := make([]*api.ReviewRouting, 0, len(periods))
+		for _, counts := range periods {
+			periodRoutings = append(periodRoutings, counts.result())
+		}
+		sort.Slice(periodRoutings, func(i, j int) bool {
+			return periodRoutings[i].Period < periodRoutings[j].Period
+		})
+		routings = append(routings, periodRoutings...)
+	}
+	return routings
+}
+
+// Counts the requests and reviewers of a merged PR
+func (c *reviewRoutingCounts) add(pr *api.PRMetrics) {
+	var reviewers []string
+	for _, reviewer := range pr.Reviewers {
+		if !containsLogin(reviewers, reviewer.Reviewer) {
+			reviewers = append(reviewers, reviewer.Reviewer)
+		}
+	}
+	hasCodeOwners := len(pr.CodeOwners) > 0
+
+	c.routing.PRCount++
+	for _, requested := range pr.RequestedReviewers {
+		c.routing.RequestCount++
+		if containsLogin(reviewers, requested) {
+			c.reviewedRequests++
+		}
+		if hasCodeOwners {
+			c.codeOwnerPRRequests++
+			if containsLogin(pr.CodeOwners, requested) {
+				c.codeOwnerRequests++
+			}
+		}
+	}
+	for _, reviewer := range reviewers {
+		c.routing.ReviewerCount++
+		if !containsLogin(pr.RequestedReviewers, reviewer) {
+			c.outsiderReviewers++
+		}
+		if hasCodeOwners {
+			c.codeOwnerPRReviewers++
+			if containsLogin(pr.CodeOwners, reviewer) {
+				c.codeOwnerReviewers++
+			}
+		}
+	}
+}
+
+// Computes the percentages of the PRs added so far
+func (c *reviewRoutingCounts) result() *api.ReviewRouting {
+	routing := c.routing
+	if routing.RequestCount > 0 {
+		routing.RequestReviewedPercent = float64(c.reviewedRequests) / float64(routing.RequestCount) * 100
+	}
+	if routing.ReviewerCount > 0 {
+		routing.OutsiderReviewPercent = float64(c.outsiderReviewers) / float64(routing.ReviewerCount) * 100
+	}
+	if c.codeOwnerPRRequests > 0 {
+		routing.CodeOwnerRequestPercent = float64(c.codeOwnerRequests) / float64(c.codeOwnerPRRequests) * 100
+	}
+	if c.codeOwnerPRReviewers > 0 {
+		routing.CodeOwnerReviewerPercent = float64(c.codeOwnerReviewers) / float64(c.codeOwnerPRReviewers) * 100
+	}
+	return routing
+}
+
+// Reports whether a login is in the list, ignoring case as GitHub does
+func containsLogin(logins []string, login string) bool {
+	return slices.ContainsFunc(logins, func(l string) bool {
+		return strings.EqualFold(l, login)
+	})
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Files left out of Additions, Deletions, and ChangedFiles, such as lockfiles, vendored
+// code, and generated protobufs that would otherwise dominate the size of a PR
+type SizeExclusionOptions struct {
+	Patterns      []string `json:"patterns"`      // Same syntax as the test file patterns
+	GitAttributes bool     `json:"gitattributes"` // Also exclude files marked linguist-generated or linguist-vendored in the base branch's .gitattributes
+}
+
+// Validates the glob syntax of each pattern
+func (o SizeExclusionOptions) Validate() error {
+	if err := validatePathPatterns(o.Patterns); err != nil {
+		return fmt.Errorf("invalid size exclusion pattern %v", err)
+	}
+	return nil
+}
+
+// Reports whether no files are excluded
+func (o SizeExclusionOptions) IsEmpty() bool {
+	return len(o.Patterns) == 0 && !o.GitAttributes
+}
+
+// Linguist attributes marking files that are not written by hand
+var excludingGitAttributes = []string{"linguist-generated", "linguist-vendored"}
+
+// Line of a .gitattributes file setting or unsetting Linguist attributes
+type gitAttributesRule struct {
+	pattern  []string        // Pattern segments, or a single segment matching the file name at any depth
+	anchored bool            // Whether the pattern matches the whole path rather than the file name
+	values   map[string]bool // Values of the Linguist attributes the line sets
+}
+
+// Linguist attributes parsed from a .gitattributes file
+type GitAttributes struct {
+	rules []gitAttributesRule
+}
+
+// Parses the lines of a .gitattributes file that set Linguist generated or vendored attributes
+func ParseGitAttributes(content string) *GitAttributes {
+	attributes := &GitAttributes{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		// Macro definitions start with [attr] and do not apply to files
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[attr]") {
+			continue
+		}
+
+		values := make(map[string]bool)
+		for _, field := range fields[1:] {
+			for _, name := range excludingGitAttributes {
+				switch field {
+				case name, name + "=true":
+					values[name] = true
+				case "-" + name, "!" + name, name + "=false":
+					values[name] = false
+				}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		// Patterns without a slash match the file name in any directory
+		pattern := fields[0]
+		rule := gitAttributesRule{values: values}
+		if strings.Contains(pattern, "/") {
+			rule.anchored = true
+			rule.pattern = strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+		} else {
+			rule.pattern = []string{pattern}
+		}
+		attributes.rules = append(attributes.rules, rule)
+	}
+	return attributes
+}
+
+// Reports whether the file is marked generated or vendored, letting later lines override earlier ones
+func (a *GitAttributes) Excludes(filePath string) bool {
+	if a == nil {
+		return false
+	}
+
+	segments := strings.Split(filePath, "/")
+	values := make(map[string]bool)
+	for _, rule := range a.rules {
+		var matched bool
+		if rule.anchored {
+			matched = matchPathSegments(rule.pattern, segments)
+		} else {
+			matched, _ = path.Match(rule.pattern[0], segments[len(segments)-1])
+		}
+		if !matched {
+			continue
+		}
+		for name, value := range rule.values {
+			values[name] = value
+		}
+	}
+
+	for _, name := range excludingGitAttributes {
+		if values[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches path segments against pattern segments, where a "**" segment matches any number of directories
+func matchPathSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPathSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchPathSegments(pattern[1:], segments[1:])
+}
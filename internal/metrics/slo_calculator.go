@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Lifecycle events an SLO can require within a deadline, measured from PR creation
+const (
+	SLOTargetFirstReview = "first_review"
+	SLOTargetApproval    = "approval"
+	SLOTargetMerge       = "merge"
+)
+
+// Service level objective for PRs with a label, such as merging bug fixes within 48 hours
+type SLO struct {
+	Label       string  `json:"label"`
+	Target      string  `json:"target"`       // first_review, approval, or merge
+	WithinHours float64 `json:"within_hours"` // Deadline from PR creation
+}
+
+// Validates the label, target, and deadline
+func (s SLO) Validate() error {
+	if s.Label == "" {
+		return fmt.Errorf("SLO label is required")
+	}
+	switch s.Target {
+	case SLOTargetFirstReview, SLOTargetApproval, SLOTargetMerge:
+	default:
+		return fmt.Errorf("invalid SLO target %q: must be 'first_review', 'approval', or 'merge'", s.Target)
+	}
+	if s.WithinHours <= 0 {
+		return fmt.Errorf("SLO within_hours must be positive: %v", s.WithinHours)
+	}
+	return nil
+}
+
+// Names the SLO, such as "bug: merge within 48h"
+func (s SLO) Name() string {
+	return fmt.Sprintf("%s: %s within %sh", s.Label, s.Target, strconv.FormatFloat(s.WithinHours, 'f', -1, 64))
+}
+
+// Reports whether the PR reached the target within the deadline; a target
+// the PR was merged without reaching, such as a review, counts as missed
+func (s SLO) met(pr *api.PRMetrics) bool {
+	switch s.Target {
+	case SLOTargetFirstReview:
+		return pr.ReviewCount > 0 && pr.WaitingForReviewHours <= s.WithinHours
+	case SLOTargetApproval:
+		return pr.ApprovalCount > 0 && pr.TimeToApprovalHours <= s.WithinHours
+	default:
+		return pr.TotalPRLifetimeHours <= s.WithinHours
+	}
+}
+
+// Computes the share of merged PRs meeting each SLO per ISO week and calendar month,
+// grouping PRs by merge date like the aggregated metrics
+func CalculateSLOAttainment(prMetrics []*api.PRMetrics, slos []SLO) []*api.SLOAttainment {
+	var attainments []*api.SLOAttainment
+	for _, granularity := range []string{GranularityWeek, GranularityMonth} {
+		for _, slo := range slos {
+			periods := make(map[string]*api.SLOAttainment)
+			for _, pr := range prMetrics {
+				if pr.MergedAt.IsZero() || !slices.Contains(pr.Labels, slo.Label) {
+					continue
+				}
+
+				period, startDate, endDate := CalendarPeriod(pr.MergedAt, granularity)
+				attainment, exists := periods[period]
+				if !exists {
+					attainment = &api.SLOAttainment{
+						Granularity: granularity,
+						Period:      period,
+						StartDate:   startDate,
+						EndDate:     endDate,
+						SLO:         slo.Name(),
+						Label:       slo.Label,
+						Target:      slo.Target,
+						WithinHours: slo.WithinHours,
+					}
+					periods[period] = attainment
+				}
+
+				attainment.PRCount++
+				if slo.met(pr) {
+					attainment.MetCount++
+				} else {
+					attainment.MissedCount++
+				}
+			}
+
+			sloAttainments := make([]*api.SLOAttainment, 0, len(periods))
+			for _, attainment := range periods {
+				attainment.AttainmentPercent = float64(attainment.MetCount) / float64(attainment.PRCount) * 100
+				sloAttainments = append(sloAttainments, attainment)
+			}
+			sort.Slice(sloAttainments, func(i, j int) bool {
+				return sloAttainments[i].Period < sloAttainments[j].Period
+			})
+			attainments = append(attainments, sloAttainments...)
+		}
+	}
+	return attainments
+}
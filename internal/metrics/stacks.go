@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Finds stacked PRs, whose base branch is the head branch of another PR, and sets their parent,
+// stack depth, and the hours they waited for the parent to merge. Returns the number of stacked
+// PRs. A branch reused by several PRs resolves to the latest one opened before the stacked PR.
+func (c *PRMetricsCalculator) DetectStacks(prMetrics []*api.PRMetrics) int {
+	byHeadBranch := make(map[string][]*api.PRMetrics)
+	for _, pr := range prMetrics {
+		if pr.HeadBranch != "" {
+			byHeadBranch[pr.HeadBranch] = append(byHeadBranch[pr.HeadBranch], pr)
+		}
+	}
+
+	parents := make(map[*api.PRMetrics]*api.PRMetrics)
+	for _, pr := range prMetrics {
+		if parent := stackParent(pr, byHeadBranch[pr.BaseBranch]); parent != nil {
+			parents[pr] = parent
+		}
+	}
+
+	for _, pr := range prMetrics {
+		pr.ParentPR, pr.StackDepth, pr.BlockedByParentHours = 0, 0, 0
+		parent, stacked := parents[pr]
+		if !stacked {
+			continue
+		}
+		pr.ParentPR = parent.Number
+
+		// Follow the parents down to the bottom of the stack, guarding against cycles
+		seen := map[*api.PRMetrics]bool{pr: true}
+		for below := parent; below != nil && !seen[below]; below = parents[below] {
+			seen[below] = true
+			pr.StackDepth++
+		}
+
+		// Only a PR still open when its parent merged had to wait for it
+		if !parent.MergedAt.IsZero() && parent.MergedAt.After(pr.CreatedAt) &&
+			(pr.MergedAt.IsZero() || pr.MergedAt.After(parent.MergedAt)) {
+			pr.BlockedByParentHours = c.hoursBetween(pr.CreatedAt, parent.MergedAt)
+		}
+	}
+
+	return len(parents)
+}
+
+// Picks the parent of a PR among the PRs whose head branch is its base branch: the latest opened
+// no later than the PR, or else the earliest opened after it
+func stackParent(pr *api.PRMetrics, candidates []*api.PRMetrics) *api.PRMetrics {
+	var before, after *api.PRMetrics
+	for _, candidate := range candidates {
+		if candidate == pr {
+			continue
+		}
+		if !candidate.CreatedAt.After(pr.CreatedAt) {
+			if before == nil || candidate.CreatedAt.After(before.CreatedAt) {
+				before = candidate
+			}
+		} else if after == nil || candidate.CreatedAt.Before(after.CreatedAt) {
+			after = candidate
+		}
+	}
+	if before != nil {
+		return before
+	}
+	return after
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Patterns matching test files when none are configured
+var defaultTestFilePatterns = []string{
+	"*_test.*", "*_spec.*", "*.test.*", "*.spec.*", "test_*", "*Test.*", "*Tests.*",
+	"test/", "tests/", "spec/", "specs/", "__tests__/", "testdata/",
+}
+
+// Patterns deciding which changed files count as tests for the test change ratio
+type TestFileOptions struct {
+	// Patterns ending in "/" match a directory at any depth, patterns containing "/" match
+	// the whole path, and other patterns match the file name; defaults to common conventions
+	Patterns []string `json:"patterns"`
+}
+
+// Validates the glob syntax of each pattern
+func (o TestFileOptions) Validate() error {
+	if err := validatePathPatterns(o.Patterns); err != nil {
+		return fmt.Errorf("invalid test file pattern %v", err)
+	}
+	return nil
+}
+
+// Decides whether changed files are tests
+type testFileMatcher struct {
+	patterns []string
+}
+
+// Initializes matcher with the configured patterns, or the defaults if none are given
+func newTestFileMatcher(patterns []string) *testFileMatcher {
+	if len(patterns) == 0 {
+		patterns = defaultTestFilePatterns
+	}
+	return &testFileMatcher{patterns: patterns}
+}
+
+// Reports whether a changed file matches any of the patterns
+func (m *testFileMatcher) matches(filePath string) bool {
+	return matchesAnyPathPattern(m.patterns, filePath)
+}
+
+// Returns the share of changed lines that are in test files, or 0 if no lines changed
+func (m *testFileMatcher) changeRatio(paths []string, changedLines []int) float64 {
+	total, tests := 0, 0
+	for i, filePath := range paths {
+		total += changedLines[i]
+		if m.matches(filePath) {
+			tests += changedLines[i]
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(tests) / float64(total)
+}
+
+// Returns an error naming the first pattern with invalid glob syntax
+func validatePathPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/"), ""); err != nil {
+			return fmt.Errorf("%q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Reports whether a file path matches any of the patterns. Patterns ending in "/" match a
+// directory at any depth, patterns containing "/" match the whole path, and other patterns
+// match the file name.
+func matchesAnyPathPattern(patterns []string, filePath string) bool {
+	segments := strings.Split(filePath, "/")
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasSuffix(pattern, "/"):
+			directory := strings.TrimSuffix(pattern, "/")
+			for _, segment := range segments[:len(segments)-1] {
+				if matched, _ := path.Match(directory, segment); matched {
+					return true
+				}
+			}
+		case strings.Contains(pattern, "/"):
+			if matched, _ := path.Match(pattern, filePath); matched {
+				return true
+			}
+		default:
+			if matched, _ := path.Match(pattern, segments[len(segments)-1]); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
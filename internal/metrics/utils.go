@@ -1,10 +1,17 @@
 package metrics
 
 import (
+	"fmt"
 	"sort"
 	"time"
 )
 
+// Granularities of the period breakdowns, such as SLO attainment
+const (
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
 // Computes the middle value of a sorted integer array, handling even-length arrays
 func calculateMedianInt(values []int) float64 {
 	if len(values) == 0 {
@@ -61,3 +68,16 @@ func getStartOfISOWeek(date time.Time) time.Time {
 	// Subtract days to get to Monday
 	return startOfDay.AddDate(0, 0, -daysToSubtract)
 }
+
+// Returns the ISO week or calendar month containing a time, with its first and last day
+func CalendarPeriod(t time.Time, granularity string) (string, time.Time, time.Time) {
+	if granularity == GranularityMonth {
+		year, month, _ := t.Date()
+		startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+		return fmt.Sprintf("%d-%02d", year, month), startOfMonth, startOfMonth.AddDate(0, 1, -1)
+	}
+
+	year, week := t.ISOWeek()
+	startOfWeek := getStartOfISOWeek(t)
+	return fmt.Sprintf("%d-W%02d", year, week), startOfWeek, startOfWeek.AddDate(0, 0, 6)
+}
@@ -0,0 +1,116 @@
+package model
+
+import "time"
+
+// Review states a ReviewEvent can have
+const (
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+)
+
+// Timeline event types the calculators act on
+const (
+	TimelineEventClosed               = "closed"
+	TimelineEventReopened             = "reopened"
+	TimelineEventReviewRequested      = "review_requested"
+	TimelineEventReviewRequestRemoved = "review_request_removed"
+)
+
+// Pull request as the calculators see it, whatever the provider it came from. Sizes and the
+// merger are only known once the PR details have been fetched.
+type PullRequest struct {
+	Number             int
+	Title              string
+	Author             string
+	State              string // open or closed; merged PRs are closed
+	CreatedAt          time.Time
+	MergedAt           time.Time // Zero unless merged
+	MergedBy           string
+	Milestone          string
+	Labels             []string
+	Assignees          []string
+	RequestedReviewers []string // Pending review requests of users
+	RequestedTeams     []string // Pending review requests of teams, by slug or name
+	AutoMerge          bool     // Auto-merge was enabled
+	BaseBranch         string
+	BaseSHA            string
+	BaseRepo           string // Full name of the repository the PR merges into
+	HeadBranch         string
+	HeadLabel          string // Head branch qualified with its owner, as owner:branch
+	HeadSHA            string
+	HeadRepo           string // Full name of the repository the head branch lives in
+	Additions          int
+	Deletions          int
+	ChangedFiles       int
+}
+
+// Commit on the branch of a pull request
+type CommitEvent struct {
+	SHA         string
+	Message     string
+	AuthorLogin string // Account of the author, empty when the email matches none
+	AuthorName  string // Name recorded in the commit
+	AuthoredAt  time.Time
+	CommittedAt time.Time
+	ParentCount int
+}
+
+// Inline review comment or conversation comment on a pull request
+type CommentEvent struct {
+	Author    string
+	Path      string // File an inline comment is on; empty for conversation comments
+	CreatedAt time.Time
+}
+
+// Review submitted on a pull request
+type ReviewEvent struct {
+	Reviewer    string
+	State       string // One of the ReviewState constants
+	SubmittedAt time.Time
+}
+
+// File changed by a pull request
+type FileChange struct {
+	Path      string
+	Additions int
+	Deletions int
+}
+
+// Event from the timeline of a pull request, such as a close or a review request
+type TimelineEvent struct {
+	Type              string
+	Actor             string
+	CreatedAt         time.Time
+	RequestedReviewer string // User asked to review, on review request events
+	RequestedTeam     string // Team asked to review by slug or name, on review request events
+}
+
+// Discussion thread on the diff of a PR, which reviewers can mark resolved
+type ReviewThread struct {
+	ID        string
+	Path      string // File the thread is on, when the provider reports it
+	Author    string
+	CreatedAt time.Time
+	Resolved  bool
+}
+
+// Run of a CI check on a commit
+type CheckRun struct {
+	ID         int64
+	Name       string
+	Conclusion string // success, failure, neutral, skipped, timed_out, cancelled, or empty while running
+	StartedAt  time.Time
+}
+
+// Commit status reported by an external service
+type CommitStatus struct {
+	Context string
+	State   string // success, failure, error, or pending
+}
+
+// Merge requirements of a protected branch
+type BranchProtection struct {
+	RequiredApprovals int
+	RequiredChecks    []string // Names of the status checks that must pass
+}
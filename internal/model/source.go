@@ -0,0 +1,20 @@
+package model
+
+// Supplies the data of pull requests in the provider-neutral model, so the calculators do not
+// depend on the types of any provider's API
+type Source interface {
+	GetPRDetails(owner, repo string, number int) (*PullRequest, error)
+	GetPRCommits(owner, repo string, number int) ([]*CommitEvent, error)
+	GetPRComments(owner, repo string, number int) ([]*CommentEvent, error)
+	GetPRIssueComments(owner, repo string, number int) ([]*CommentEvent, error)
+	GetPRReviews(owner, repo string, number int) ([]*ReviewEvent, error)
+	GetPRFiles(owner, repo string, number int) ([]*FileChange, error)
+	GetPRReviewThreads(owner, repo string, number int) ([]*ReviewThread, error)
+	GetPRTimelineEvents(owner, repo string, number int) ([]*TimelineEvent, error)
+	GetBranchProtection(owner, repo, branch string) (*BranchProtection, error)
+	GetCommitStatuses(owner, repo, ref string) ([]*CommitStatus, error)
+	GetCheckRuns(owner, repo, ref string) ([]*CheckRun, error)
+	GetCodeOwners(owner, repo, ref string) (string, error)
+	GetGitAttributes(owner, repo, ref string) (string, error)
+	BudgetExhausted() bool
+}
@@ -0,0 +1,238 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Defaults for the SMTP settings
+const (
+	defaultSMTPPort        = 587
+	defaultSMTPPasswordEnv = "SMTP_PASSWORD"
+	smtpsPort              = 465 // Port for SMTP over implicit TLS rather than STARTTLS
+)
+
+// SMTP server and distribution list for the email digest
+type EmailOptions struct {
+	Host        string   `json:"host"`
+	Port        int      `json:"port"`         // Defaults to 587; 465 uses implicit TLS
+	Username    string   `json:"username"`     // Leave empty for servers that accept unauthenticated mail
+	PasswordEnv string   `json:"password_env"` // Environment variable holding the password; defaults to SMTP_PASSWORD
+	From        string   `json:"from"`
+	To          []string `json:"to"`
+	Subject     string   `json:"subject"` // Defaults to "PR Metrics: <repository>"
+}
+
+// Validates the server and addresses
+func (o *EmailOptions) Validate() error {
+	if o.Host == "" {
+		return fmt.Errorf("email host is required")
+	}
+	if o.Port < 0 || o.Port > 65535 {
+		return fmt.Errorf("invalid email port: %d", o.Port)
+	}
+	if _, err := mail.ParseAddress(o.From); err != nil {
+		return fmt.Errorf("invalid email sender %q: %v", o.From, err)
+	}
+	if len(o.To) == 0 {
+		return fmt.Errorf("email recipients are required")
+	}
+	for _, recipient := range o.To {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("invalid email recipient %q: %v", recipient, err)
+		}
+	}
+	return nil
+}
+
+// Sends the report to a distribution list over SMTP
+type EmailSender struct {
+	options EmailOptions
+	logger  *utils.Logger
+}
+
+// Initializes email sender with SMTP settings and logger dependency
+func NewEmailSender(options EmailOptions, logger *utils.Logger) *EmailSender {
+	if options.Port == 0 {
+		options.Port = defaultSMTPPort
+	}
+	if options.PasswordEnv == "" {
+		options.PasswordEnv = defaultSMTPPasswordEnv
+	}
+
+	return &EmailSender{
+		options: options,
+		logger:  logger,
+	}
+}
+
+// Sends the report as an email with HTML and Markdown alternatives
+func (s *EmailSender) Send(report *output.Report) error {
+	s.logger.Info("Sending email digest to %d recipients via %s", len(s.options.To), s.options.Host)
+
+	message, err := s.buildMessage(report)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.options.Username != "" {
+		auth = smtp.PlainAuth("", s.options.Username, os.Getenv(s.options.PasswordEnv), s.options.Host)
+	}
+
+	from, err := mail.ParseAddress(s.options.From)
+	if err != nil {
+		return err
+	}
+	recipients := make([]string, 0, len(s.options.To))
+	for _, recipient := range s.options.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, address.Address)
+	}
+
+	address := net.JoinHostPort(s.options.Host, strconv.Itoa(s.options.Port))
+	if s.options.Port == smtpsPort {
+		err = s.sendOverTLS(address, auth, from.Address, recipients, message)
+	} else {
+		// SendMail upgrades the connection with STARTTLS when the server offers it
+		err = smtp.SendMail(address, auth, from.Address, recipients, message)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	s.logger.Info("Sent email digest")
+	return nil
+}
+
+// Sends the message over a connection that is encrypted from the start
+func (s *EmailSender) sendOverTLS(address string, auth smtp.Auth, from string, recipients []string, message []byte) error {
+	conn, err := tls.Dial("tcp", address, &tls.Config{ServerName: s.options.Host})
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.options.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Builds a multipart/alternative message, so clients without HTML support show the Markdown
+func (s *EmailSender) buildMessage(report *output.Report) ([]byte, error) {
+	var markdown, html bytes.Buffer
+	if err := report.RenderMarkdown(&markdown); err != nil {
+		return nil, err
+	}
+	if err := report.RenderHTML(&html); err != nil {
+		return nil, err
+	}
+
+	subject := s.options.Subject
+	if subject == "" {
+		subject = "PR Metrics: " + report.Repository
+	}
+	if len(report.Alerts) > 0 {
+		subject = fmt.Sprintf("[%d alerts] %s", len(report.Alerts), subject)
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		// Clients show the last alternative they support
+		{"text/plain; charset=utf-8", markdown.Bytes()},
+		{"text/html; charset=utf-8", html.Bytes()},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write([]byte(wrapBase64(part.content))); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	headers := []string{
+		"From: " + s.options.From,
+		"To: " + strings.Join(s.options.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	}
+	for _, header := range headers {
+		message.WriteString(header + "\r\n")
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// Encodes content as base64 in lines of 76 characters, as MIME requires
+func wrapBase64(content []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded + "\r\n")
+	return wrapped.String()
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Destinations the summary is sent to after a run with --notify
+type Options struct {
+	Email    *EmailOptions    `json:"email"`
+	Webhooks []WebhookOptions `json:"webhooks"` // Slack, Teams, or Discord, chosen per webhook
+}
+
+// Validates each configured destination
+func (o Options) Validate() error {
+	if o.Email != nil {
+		if err := o.Email.Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range o.Webhooks {
+		if err := o.Webhooks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reports whether no destination is configured
+func (o Options) IsEmpty() bool {
+	return o.Email == nil && len(o.Webhooks) == 0
+}
+
+// Sends the report to every configured destination, continuing past failures
+// so one broken destination does not keep the others from being notified
+func Send(options Options, report *output.Report, logger *utils.Logger) error {
+	destinations, failures := 0, 0
+	if options.Email != nil {
+		destinations++
+		if err := NewEmailSender(*options.Email, logger).Send(report); err != nil {
+			logger.Error("Failed to send email digest: %v", err)
+			failures++
+		}
+	}
+	for _, webhook := range options.Webhooks {
+		destinations++
+		if err := NewWebhookSender(webhook, logger).Send(report); err != nil {
+			logger.Error("Failed to notify %s: %v", webhook.displayName(), err)
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d destinations failed", failures, destinations)
+	}
+	return nil
+}
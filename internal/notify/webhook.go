@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Supported webhook payload formats
+const (
+	WebhookFormatSlack   = "slack"
+	WebhookFormatTeams   = "teams"
+	WebhookFormatDiscord = "discord"
+)
+
+const (
+	webhookTimeout = 30 * time.Second
+	webhookWeeks   = 8 // Most recent weeks posted, keeping messages within chat size limits
+)
+
+// Chat webhook the summary is posted to
+type WebhookOptions struct {
+	Name   string `json:"name"`    // Identifies the destination in logs; defaults to the format
+	Format string `json:"format"`  // slack (default), teams, or discord
+	URL    string `json:"url"`     // Webhook URL; prefer url_env, since the URL is a secret
+	URLEnv string `json:"url_env"` // Environment variable holding the webhook URL
+}
+
+// Validates the format and that exactly one URL source is set
+func (o *WebhookOptions) Validate() error {
+	switch o.Format {
+	case "", WebhookFormatSlack, WebhookFormatTeams, WebhookFormatDiscord:
+	default:
+		return fmt.Errorf("invalid webhook format %q: must be 'slack', 'teams', or 'discord'", o.Format)
+	}
+	if (o.URL == "") == (o.URLEnv == "") {
+		return fmt.Errorf("webhook %s requires exactly one of url and url_env", o.displayName())
+	}
+	if o.URL != "" {
+		if parsed, err := url.Parse(o.URL); err != nil || parsed.Host == "" {
+			return fmt.Errorf("invalid webhook URL for %s", o.displayName())
+		}
+	}
+	return nil
+}
+
+// Returns the name used in logs, never the URL
+func (o *WebhookOptions) displayName() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	if o.Format != "" {
+		return o.Format
+	}
+	return WebhookFormatSlack
+}
+
+// Posts the report summary to a Slack, Teams, or Discord webhook
+type WebhookSender struct {
+	options WebhookOptions
+	client  *http.Client
+	logger  *utils.Logger
+}
+
+// Initializes webhook sender with destination settings and logger dependency
+func NewWebhookSender(options WebhookOptions, logger *utils.Logger) *WebhookSender {
+	if options.Format == "" {
+		options.Format = WebhookFormatSlack
+	}
+
+	return &WebhookSender{
+		options: options,
+		client:  &http.Client{Timeout: webhookTimeout},
+		logger:  logger,
+	}
+}
+
+// Posts the summary in the format of the destination
+func (s *WebhookSender) Send(report *output.Report) error {
+	webhookURL := s.options.URL
+	if s.options.URLEnv != "" {
+		webhookURL = os.Getenv(s.options.URLEnv)
+		if webhookURL == "" {
+			return fmt.Errorf("environment variable %s is not set", s.options.URLEnv)
+		}
+	}
+
+	s.logger.Info("Posting summary to webhook %s", s.options.displayName())
+
+	var payload any
+	switch s.options.Format {
+	case WebhookFormatTeams:
+		payload = teamsPayload(report)
+	case WebhookFormatDiscord:
+		payload = discordPayload(report)
+	default:
+		payload = slackPayload(report)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The error includes the URL, which is a secret
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post to webhook: %v", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.logger.Warn("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	s.logger.Info("Posted summary to webhook %s", s.options.displayName())
+	return nil
+}
+
+// Title and one-line overview shared by every format
+func summaryLines(report *output.Report) (string, string) {
+	title := "PR Metrics: " + report.Repository
+	summary := fmt.Sprintf("%s to %s · %d PRs, %d merged",
+		report.StartDate.Format("2006-01-02"), report.EndDate.Format("2006-01-02"), report.PRCount, report.MergedCount)
+	return title, summary
+}
+
+// Lists the triggered alerts, one per line
+func alertLines(report *output.Report) []string {
+	lines := make([]string, len(report.Alerts))
+	for i, alert := range report.Alerts {
+		lines[i] = "⚠ " + alert.Message()
+	}
+	return lines
+}
+
+// Returns the most recent weeks of the phase breakdown
+func recentWeeks(report *output.Report) []output.PhaseBreakdown {
+	return report.PhaseBreakdown[max(len(report.PhaseBreakdown)-webhookWeeks, 0):]
+}
+
+// Describes a week's phases in one line
+func formatWeek(week output.PhaseBreakdown) string {
+	return fmt.Sprintf("%d PRs · coding %.1fh · waiting for review %.1fh · in review %.1fh · waiting to merge %.1fh",
+		week.PRCount, week.CodingHours, week.WaitingForReviewHours, week.InReviewHours, week.WaitingToMergeHours)
+}
+
+// Builds a Slack message with Block Kit sections and a plain text fallback
+func slackPayload(report *output.Report) map[string]any {
+	title, summary := summaryLines(report)
+
+	var weeks strings.Builder
+	for _, week := range recentWeeks(report) {
+		fmt.Fprintf(&weeks, "*%s*: %s\n", week.Period, formatWeek(week))
+	}
+
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": title}},
+		{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": summary}},
+	}
+	if alerts := alertLines(report); len(alerts) > 0 {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": strings.Join(alerts, "\n")},
+		})
+	}
+	if weeks.Len() > 0 {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": weeks.String()},
+		})
+	}
+
+	return map[string]any{
+		"text":   title + "\n" + summary,
+		"blocks": blocks,
+	}
+}
+
+// Builds a Teams message carrying an Adaptive Card with a fact per week
+func teamsPayload(report *output.Report) map[string]any {
+	title, summary := summaryLines(report)
+
+	body := []map[string]any{
+		{"type": "TextBlock", "text": title, "size": "Large", "weight": "Bolder", "wrap": true},
+		{"type": "TextBlock", "text": summary, "isSubtle": true, "wrap": true},
+	}
+	for _, alert := range alertLines(report) {
+		body = append(body, map[string]any{"type": "TextBlock", "text": alert, "color": "Attention", "wrap": true})
+	}
+	var facts []map[string]any
+	for _, week := range recentWeeks(report) {
+		facts = append(facts, map[string]any{"title": week.Period, "value": formatWeek(week)})
+	}
+	if len(facts) > 0 {
+		body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+}
+
+// Builds a Discord message with an embed holding a field per week
+func discordPayload(report *output.Report) map[string]any {
+	title, summary := summaryLines(report)
+
+	fields := []map[string]any{}
+	for _, week := range recentWeeks(report) {
+		fields = append(fields, map[string]any{"name": week.Period, "value": formatWeek(week)})
+	}
+
+	description := summary
+	if alerts := alertLines(report); len(alerts) > 0 {
+		description += "\n\n" + strings.Join(alerts, "\n")
+	}
+
+	return map[string]any{
+		"embeds": []map[string]any{{
+			"title":       title,
+			"description": description,
+			"fields":      fields,
+		}},
+	}
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Replaces logins with stable pseudonyms so metrics can be shared without naming people
+type Anonymizer struct {
+	salt string
+}
+
+// Initializes anonymizer with a salt; the same salt always yields the same pseudonyms
+func NewAnonymizer(salt string) *Anonymizer {
+	return &Anonymizer{
+		salt: salt,
+	}
+}
+
+// Replaces a login with a pseudonym derived from its salted hash
+func (a *Anonymizer) Pseudonym(login string) string {
+	if login == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(a.salt + login))
+	return "user-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// Replaces author, merger, assignee, reviewer, code owner, and event actor logins in place
+func (a *Anonymizer) AnonymizePRMetrics(prMetrics []*api.PRMetrics) {
+	for _, pr := range prMetrics {
+		pr.Author = a.Pseudonym(pr.Author)
+		pr.MergedBy = a.Pseudonym(pr.MergedBy)
+		for i := range pr.Assignees {
+			pr.Assignees[i] = a.Pseudonym(pr.Assignees[i])
+		}
+		for i := range pr.RequestedReviewers {
+			pr.RequestedReviewers[i] = a.Pseudonym(pr.RequestedReviewers[i])
+		}
+		for i := range pr.CodeOwners {
+			pr.CodeOwners[i] = a.Pseudonym(pr.CodeOwners[i])
+		}
+		for i := range pr.Reviewers {
+			pr.Reviewers[i].Reviewer = a.Pseudonym(pr.Reviewers[i].Reviewer)
+		}
+		for i := range pr.Events {
+			pr.Events[i].Actor = a.Pseudonym(pr.Events[i].Actor)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+	"gonum.org/v1/plot/vg/vgsvg"
+)
+
+// Supported chart image formats
+const (
+	ChartFormatPNG = "png"
+	ChartFormatSVG = "svg"
+)
+
+// Size and layout of chart images in points, which are pixels in PNG images
+const (
+	chartWidth        = 800
+	chartHeight       = 400
+	chartMargin       = 10  // Space around the plot
+	chartBarWidth     = 600 // Total width of the bars of all periods
+	chartPeriodLabels = 12  // Most period labels along the bottom
+	chartTicks        = 5   // Approximate number of gridlines on the value axis
+)
+
+// Colors of successive series
+var chartColors = []color.RGBA{
+	{0x4e, 0x79, 0xa7, 0xff},
+	{0xf2, 0x8e, 0x2b, 0xff},
+	{0x59, 0xa1, 0x4f, 0xff},
+	{0xe1, 0x57, 0x59, 0xff},
+}
+
+var (
+	chartBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	chartGrid       = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	chartText       = color.RGBA{0x22, 0x22, 0x22, 0xff}
+)
+
+// A trend chart over the weeks of a run
+type chart struct {
+	name    string // Output file name without extension
+	title   string
+	periods []string
+	series  []chartSeries
+	bars    bool // Draw the first series as bars of counts rather than lines
+}
+
+// Values of one metric, one per period
+type chartSeries struct {
+	name   string
+	values []float64
+}
+
+// Handles rendering trend charts as images
+type ChartWriter struct {
+	logger *utils.Logger
+}
+
+// Initializes chart writer with logger dependency
+func NewChartWriter(logger *utils.Logger) *ChartWriter {
+	return &ChartWriter{
+		logger: logger,
+	}
+}
+
+// Writes the lead time, throughput, and review latency charts of the weekly metrics
+// in each requested format
+func (w *ChartWriter) WriteToDirectory(namer *FileNamer, formats []string, weeklyMetrics []*api.AggregatedMetrics) error {
+	for _, c := range weeklyCharts(weeklyMetrics) {
+		for _, format := range formats {
+			filename := namer.Path(c.name + "." + format)
+			w.logger.Info("Writing %s chart: %s", c.title, filename)
+
+			if format != ChartFormatSVG && format != ChartFormatPNG {
+				return fmt.Errorf("unsupported chart format: %s", format)
+			}
+			data, err := c.render(format)
+			if err != nil {
+				return fmt.Errorf("failed to render %s chart: %v", c.title, err)
+			}
+			if err := os.WriteFile(filename, data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Builds the charts of the weekly metrics
+func weeklyCharts(weeklyMetrics []*api.AggregatedMetrics) []chart {
+	periods := make([]string, len(weeklyMetrics))
+	series := func(name string, value func(*api.AggregatedMetrics) float64) chartSeries {
+		values := make([]float64, len(weeklyMetrics))
+		for i, week := range weeklyMetrics {
+			values[i] = value(week)
+		}
+		return chartSeries{name: name, values: values}
+	}
+	for i, week := range weeklyMetrics {
+		periods[i] = week.Period
+	}
+
+	return []chart{
+		{
+			name:    "chart_lead_time",
+			title:   "Lead Time per Week (Hours)",
+			periods: periods,
+			series: []chartSeries{
+				series("Median PR lifetime", func(m *api.AggregatedMetrics) float64 { return m.MedianTotalPRLifetimeHours }),
+				series("Median first commit to merge", func(m *api.AggregatedMetrics) float64 { return m.MedianFirstCommitToMergeHours }),
+			},
+		},
+		{
+			name:    "chart_throughput",
+			title:   "PRs per Week",
+			periods: periods,
+			series: []chartSeries{
+				series("PRs", func(m *api.AggregatedMetrics) float64 { return float64(m.PRCount) }),
+			},
+			bars: true,
+		},
+		{
+			name:    "chart_review_latency",
+			title:   "Review Latency per Week (Hours)",
+			periods: periods,
+			series: []chartSeries{
+				series("Median time to first comment", func(m *api.AggregatedMetrics) float64 { return m.MedianCreatedToFirstCommentHours }),
+				series("Median time to approval", func(m *api.AggregatedMetrics) float64 { return m.MedianTimeToApprovalHours }),
+			},
+		},
+	}
+}
+
+// Returns the top of the value axis and the spacing of its gridlines
+func (c chart) scale() (float64, float64) {
+	maxValue := 0.0
+	for _, s := range c.series {
+		for _, value := range s.values {
+			maxValue = max(maxValue, value)
+		}
+	}
+	top, step := niceScale(maxValue)
+	// Counts have no fractional gridlines
+	if c.bars && step < 1 {
+		top, step = math.Ceil(top), 1
+	}
+	return top, step
+}
+
+// Returns an axis top covering the value and the spacing of about chartTicks gridlines,
+// rounded to 1, 2, or 5 times a power of ten
+func niceScale(maxValue float64) (float64, float64) {
+	if maxValue <= 0 {
+		return 1, 1.0 / chartTicks
+	}
+
+	rough := maxValue / chartTicks
+	magnitude := math.Pow(10, math.Floor(math.Log10(rough)))
+	step := magnitude
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if step = factor * magnitude; step >= rough {
+			break
+		}
+	}
+	return math.Ceil(maxValue/step) * step, step
+}
+
+// Formats a value axis label, without decimals for whole numbers
+func formatTick(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+// Builds the plot of the chart, with the periods on a nominal axis and the first series
+// drawn as bars when the chart counts PRs
+func (c chart) plot() (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = c.title
+	p.Title.TextStyle.Font.Size = vg.Points(14)
+	p.BackgroundColor = chartBackground
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	top, step := c.scale()
+	p.Y.Min = 0
+	p.Y.Max = top
+	p.Y.Tick.Marker = plot.TickerFunc(func(_, _ float64) []plot.Tick {
+		var ticks []plot.Tick
+		for value := 0.0; value <= top+step/2; value += step {
+			ticks = append(ticks, plot.Tick{Value: value, Label: formatTick(value)})
+		}
+		return ticks
+	})
+	p.X.Min = -0.5
+	p.X.Max = float64(len(c.periods)) - 0.5
+	p.X.Tick.Marker = plot.TickerFunc(c.periodTicks)
+
+	grid := plotter.NewGrid()
+	grid.Vertical.Color = nil
+	grid.Horizontal.Color = chartGrid
+	p.Add(grid)
+
+	if len(c.periods) == 0 {
+		return p, nil
+	}
+	for index, s := range c.series {
+		rgba := chartColors[index%len(chartColors)]
+		if c.bars && index == 0 {
+			bars, err := plotter.NewBarChart(plotter.Values(s.values), vg.Points(chartBarWidth/float64(len(c.periods))))
+			if err != nil {
+				return nil, err
+			}
+			bars.Color = rgba
+			bars.LineStyle.Width = 0
+			p.Add(bars)
+			p.Legend.Add(s.name, bars)
+			continue
+		}
+
+		points := make(plotter.XYs, len(s.values))
+		for i, value := range s.values {
+			points[i] = plotter.XY{X: float64(i), Y: value}
+		}
+		line, scatter, err := plotter.NewLinePoints(points)
+		if err != nil {
+			return nil, err
+		}
+		line.Color = rgba
+		line.Width = vg.Points(2)
+		scatter.Color = rgba
+		scatter.Shape = draw.CircleGlyph{}
+		scatter.Radius = vg.Points(2.5)
+		p.Add(line, scatter)
+		p.Legend.Add(s.name, line, scatter)
+	}
+	return p, nil
+}
+
+// Labels every few periods, so labels do not overlap
+func (c chart) periodTicks(_, _ float64) []plot.Tick {
+	every := max(1, int(math.Ceil(float64(len(c.periods))/chartPeriodLabels)))
+	ticks := make([]plot.Tick, len(c.periods))
+	for i, period := range c.periods {
+		ticks[i] = plot.Tick{Value: float64(i)}
+		if i%every == 0 {
+			ticks[i].Label = period
+		}
+	}
+	return ticks
+}
+
+// Renders the chart as an SVG document or a PNG image at 72 DPI, so a point is a pixel
+func (c chart) render(format string) ([]byte, error) {
+	p, err := c.plot()
+	if err != nil {
+		return nil, err
+	}
+
+	var canvas interface {
+		vg.CanvasSizer
+		io.WriterTo
+	}
+	if format == ChartFormatSVG {
+		canvas = vgsvg.New(vg.Points(chartWidth), vg.Points(chartHeight))
+	} else {
+		canvas = vgimg.PngCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(vg.Points(chartWidth), vg.Points(chartHeight)), vgimg.UseDPI(72))}
+	}
+	p.Draw(draw.Crop(draw.New(canvas), chartMargin, -chartMargin, chartMargin, -chartMargin))
+
+	var buf bytes.Buffer
+	if _, err := canvas.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+func sampleWeeks(count int) []*api.AggregatedMetrics {
+	weeks := make([]*api.AggregatedMetrics, count)
+	for i := range weeks {
+		weeks[i] = &api.AggregatedMetrics{
+			Period:                     fmt.Sprintf("2026-W%02d", i+1),
+			PRCount:                    i%4 + 1,
+			MedianTotalPRLifetimeHours: float64(10 + i),
+			MedianTimeToApprovalHours:  float64(i) / 2,
+		}
+	}
+	return weeks
+}
+
+func TestChartRenderPNG(t *testing.T) {
+	for _, c := range weeklyCharts(sampleWeeks(8)) {
+		data, err := c.render(ChartFormatPNG)
+		if err != nil {
+			t.Fatalf("%s: render() error = %v", c.name, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: not a PNG image: %v", c.name, err)
+		}
+		if size := img.Bounds().Size(); size.X != chartWidth || size.Y != chartHeight {
+			t.Errorf("%s: size = %v, want %dx%d", c.name, size, chartWidth, chartHeight)
+		}
+	}
+}
+
+func TestChartRenderSVG(t *testing.T) {
+	for _, c := range weeklyCharts(sampleWeeks(8)) {
+		data, err := c.render(ChartFormatSVG)
+		if err != nil {
+			t.Fatalf("%s: render() error = %v", c.name, err)
+		}
+		if !bytes.Contains(data, []byte("<svg")) {
+			t.Errorf("%s: output is not an SVG document", c.name)
+		}
+	}
+}
+
+func TestChartRenderWithoutWeeks(t *testing.T) {
+	for _, c := range weeklyCharts(nil) {
+		if _, err := c.render(ChartFormatPNG); err != nil {
+			t.Errorf("%s: render() error = %v", c.name, err)
+		}
+	}
+}
+
+func TestChartPeriodTicks(t *testing.T) {
+	c := chart{periods: make([]string, 30)}
+	for i := range c.periods {
+		c.periods[i] = fmt.Sprintf("2026-W%02d", i+1)
+	}
+
+	var labels []string
+	for _, tick := range c.periodTicks(0, 0) {
+		if tick.Label != "" {
+			labels = append(labels, tick.Label)
+		}
+	}
+	if len(labels) > chartPeriodLabels {
+		t.Errorf("labels = %d, want at most %d", len(labels), chartPeriodLabels)
+	}
+	if labels[0] != "2026-W01" {
+		t.Errorf("first label = %q, want 2026-W01", labels[0])
+	}
+	if got := strings.Join(labels[:2], ","); got != "2026-W01,2026-W04" {
+		t.Errorf("labels = %s, want every third week", got)
+	}
+}
+
+func TestNiceScale(t *testing.T) {
+	tests := []struct {
+		maxValue float64
+		top      float64
+		step     float64
+	}{
+		{maxValue: 0, top: 1, step: 0.2},
+		{maxValue: 7, top: 8, step: 2},
+		{maxValue: 46, top: 50, step: 10},
+		{maxValue: 130, top: 150, step: 50},
+	}
+	for _, tt := range tests {
+		top, step := niceScale(tt.maxValue)
+		if top != tt.top || step != tt.step {
+			t.Errorf("niceScale(%v) = %v, %v; want %v, %v", tt.maxValue, top, step, tt.top, tt.step)
+		}
+	}
+}
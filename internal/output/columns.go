@@ -0,0 +1,149 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Column of a CSV file, bound to the struct field whose csv tag names it
+type column struct {
+	name  string
+	field int
+}
+
+// Columns of pr_metrics.csv and of the weekly and monthly CSVs, read from the struct tags
+var (
+	prMetricsColumns         = structColumns(reflect.TypeFor[api.PRMetrics]())
+	aggregatedMetricsColumns = structColumns(reflect.TypeFor[api.AggregatedMetrics]())
+)
+
+// Returns the columns of pr_metrics.csv
+func PRColumns() []string {
+	names := make([]string, len(prMetricsColumns))
+	for i, column := range prMetricsColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// Returns the value of a pr_metrics.csv column for a PR, as held in its field
+func PRMetricField(pr *api.PRMetrics, name string) (any, bool) {
+	for _, column := range prMetricsColumns {
+		if column.name == name {
+			return reflect.ValueOf(pr).Elem().Field(column.field).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// Formats a value returned by PRMetricField as it is written to pr_metrics.csv
+func FormatField(value any) string {
+	return (&CSVWriter{}).formatValue(value)
+}
+
+// Returns the columns of a struct in field order, skipping fields without a csv tag.
+// Panics on a field type the CSV files cannot hold, so a new field is caught at startup.
+func structColumns(structType reflect.Type) []column {
+	var columns []column
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		name := field.Tag.Get("csv")
+		if name == "" || name == "-" {
+			continue
+		}
+		if !isColumnType(field.Type) {
+			panic(fmt.Sprintf("unsupported type %s of CSV column %q", field.Type, name))
+		}
+		columns = append(columns, column{name: name, field: i})
+	}
+	return columns
+}
+
+// Reports whether formatValue and parseValue handle a field type
+func isColumnType(fieldType reflect.Type) bool {
+	switch fieldType {
+	case reflect.TypeFor[string](), reflect.TypeFor[int](), reflect.TypeFor[float64](), reflect.TypeFor[bool](),
+		reflect.TypeFor[time.Time](), reflect.TypeFor[[]string](), reflect.TypeFor[[]api.LanguageChange]():
+		return true
+	}
+	return false
+}
+
+// Builds the header and rows of a CSV file from the tagged fields of each record
+func marshalTable[T any](w *CSVWriter, columns []column, records []*T) ([]string, [][]string) {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		value := reflect.ValueOf(record).Elem()
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = w.formatValue(value.Field(column.field).Interface())
+		}
+		rows = append(rows, row)
+	}
+
+	return header, rows
+}
+
+// Formats a field value as it is written to the CSV files
+func (w *CSVWriter) formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return w.formatFloat(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return w.formatTime(v)
+	case []string:
+		return strings.Join(v, labelSeparator)
+	case []api.LanguageChange:
+		return formatLanguages(v)
+	}
+	panic(fmt.Sprintf("unsupported CSV value type %T", value))
+}
+
+// Sets a field from its value in a CSV file, the reverse of formatValue
+func (w *CSVWriter) parseValue(field reflect.Value, value string) error {
+	var parsed any
+	var err error
+	switch field.Interface().(type) {
+	case string:
+		parsed = value
+	case int:
+		parsed, err = strconv.Atoi(value)
+	case float64:
+		parsed, err = w.parseFloat(value)
+	case bool:
+		parsed, err = strconv.ParseBool(value)
+	case time.Time:
+		parsed, err = w.parseTime(value)
+	case []string:
+		var values []string
+		if value != "" {
+			values = strings.Split(value, labelSeparator)
+		}
+		parsed = values
+	case []api.LanguageChange:
+		parsed, err = parseLanguages(value)
+	default:
+		return fmt.Errorf("unsupported CSV field type %s", field.Type())
+	}
+	if err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(parsed))
+	return nil
+}
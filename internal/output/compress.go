@@ -0,0 +1,206 @@
+package output
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Ways of compressing the outputs of a run
+const (
+	CompressionGzip = "gzip" // Replace each file with a .gz file
+	CompressionZip  = "zip"  // Replace the files with one outputs.zip archive
+)
+
+// Files that stay uncompressed: the run report, read by scripts and by the report subcommand,
+// and the backfill checkpoint, read when a backfill resumes
+var uncompressedFiles = []string{"run_report.json", "backfill_checkpoint.json"}
+
+// Extensions of formats that are compressed already, which gzip would only grow
+var compressedExtensions = map[string]bool{".gz": true, ".zip": true, ".xlsx": true, ".png": true}
+
+// Compresses the files the namer handed out that exist, replacing them with .gz files or a single
+// archive, and returns the paths written
+func CompressOutputs(namer *FileNamer, compression string, logger *utils.Logger) ([]string, error) {
+	skip := make(map[string]bool, len(uncompressedFiles))
+	for _, file := range uncompressedFiles {
+		skip[namer.Path(file)] = true
+	}
+
+	var files []string
+	for _, path := range namer.Named() {
+		if skip[path] {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	switch compression {
+	case CompressionGzip:
+		var written []string
+		for _, path := range files {
+			if compressedExtensions[strings.ToLower(filepath.Ext(path))] {
+				continue
+			}
+			if err := gzipFile(path); err != nil {
+				return written, fmt.Errorf("failed to compress %s: %v", path, err)
+			}
+			written = append(written, path+".gz")
+		}
+		logger.Info("Compressed %d output files with gzip", len(written))
+		return written, nil
+	case CompressionZip:
+		archive := namer.Path("outputs.zip")
+		if err := zipFiles(archive, files); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %v", archive, err)
+		}
+		logger.Info("Archived %d output files in %s", len(files), archive)
+		return []string{archive}, nil
+	}
+	return nil, fmt.Errorf("unsupported compression: %s", compression)
+}
+
+// Writes the file as path.gz and removes the original once the compressed file is complete
+func gzipFile(path string) error {
+	if err := writeGzip(path); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Writes the gzip-compressed contents of the file to path.gz, keeping its name and modification time
+func writeGzip(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	target, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(target)
+	writer.Name = filepath.Base(path)
+	writer.ModTime = info.ModTime()
+	if _, err := io.Copy(writer, source); err != nil {
+		target.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		target.Close()
+		return err
+	}
+	return target.Close()
+}
+
+// Writes the files into a zip archive under their base names and removes them once the archive
+// is complete
+func zipFiles(archive string, files []string) error {
+	target, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	writer := zip.NewWriter(target)
+	for _, path := range files {
+		if err := addToZip(writer, path); err != nil {
+			target.Close()
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		target.Close()
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deflates one file into the archive, keeping its modification time
+func addToZip(writer *zip.Writer, path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Method = zip.Deflate
+	entry, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, source)
+	return err
+}
+
+// Opens a file for reading, transparently decompressing it if its name ends in .gz. If the file
+// does not exist but a .gz of it does, as left by --compress gzip, that is read instead.
+func openMaybeCompressed(path string) (io.ReadCloser, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) && !strings.HasSuffix(path, ".gz") {
+		if _, err := os.Stat(path + ".gz"); err == nil {
+			path += ".gz"
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &gzipFileReader{Reader: reader, file: file}, nil
+}
+
+// Closes both the gzip stream and the file under it
+type gzipFileReader struct {
+	*gzip.Reader
+	file *os.File
+}
+
+// Closes the gzip stream and the file
+func (r *gzipFileReader) Close() error {
+	err := r.Reader.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
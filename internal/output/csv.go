@@ -4,28 +4,70 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
 	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
 )
 
+// Separates the labels, issue keys, logins, teams, and languages of a PR within their columns
+const labelSeparator = ";"
+
+// Customizes the layout of the CSV files
+type CSVOptions struct {
+	Delimiter         string            `json:"delimiter"`          // Field delimiter; defaults to a comma
+	DecimalSeparator  string            `json:"decimal_separator"`  // Decimal separator; defaults to a dot
+	TimeLayout        string            `json:"time_layout"`        // Go layout of the times, in UTC; defaults to RFC 3339
+	PRColumns         []string          `json:"pr_columns"`         // Columns of pr_metrics.csv in order; defaults to all
+	AggregatedColumns []string          `json:"aggregated_columns"` // Columns of the weekly and monthly CSVs in order; defaults to all
+	HeaderNames       map[string]string `json:"header_names"`       // Header to write in place of each default column name
+}
+
+// Time a custom layout must keep intact to be read back, with every field distinct
+var timeLayoutReference = time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+// Validates the delimiter, decimal separator, and time layout
+func (o CSVOptions) Validate() error {
+	if o.Delimiter != "" && utf8.RuneCountInString(o.Delimiter) != 1 {
+		return fmt.Errorf("CSV delimiter must be a single character: %q", o.Delimiter)
+	}
+	if o.DecimalSeparator != "" && utf8.RuneCountInString(o.DecimalSeparator) != 1 {
+		return fmt.Errorf("decimal separator must be a single character: %q", o.DecimalSeparator)
+	}
+	if o.Delimiter != "" && o.Delimiter == o.DecimalSeparator {
+		return fmt.Errorf("CSV delimiter and decimal separator must differ")
+	}
+	if o.TimeLayout != "" {
+		parsed, err := time.Parse(o.TimeLayout, timeLayoutReference.Format(o.TimeLayout))
+		if err != nil || !parsed.Equal(timeLayoutReference) {
+			return fmt.Errorf("time layout must hold the date and the time to the second: %q", o.TimeLayout)
+		}
+	}
+	return nil
+}
+
 // Handles exporting PR metrics data to CSV format files
 type CSVWriter struct {
-	logger *utils.Logger
+	logger  *utils.Logger
+	options CSVOptions
 }
 
-// Initializes CSV writer with logger dependency
-func NewCSVWriter(logger *utils.Logger) *CSVWriter {
+// Initializes CSV writer with logger dependency and layout options
+func NewCSVWriter(logger *utils.Logger, options CSVOptions) *CSVWriter {
 	return &CSVWriter{
-		logger: logger,
+		logger:  logger,
+		options: options,
 	}
 }
 
 // Exports PR, weekly, and monthly metrics to separate CSV files in target directory
-func (w *CSVWriter) WriteToDirectory(dirPath string, prMetrics []*api.PRMetrics, weeklyMetrics, monthlyMetrics []*api.AggregatedMetrics) error {
+func (w *CSVWriter) WriteToDirectory(namer *FileNamer, prMetrics []*api.PRMetrics, weeklyMetrics, monthlyMetrics []*api.AggregatedMetrics) error {
+	dirPath := namer.Dir()
 	w.logger.Info("Writing metrics to directory: %s", dirPath)
 
 	// Create directory if it doesn't exist
@@ -34,23 +76,28 @@ func (w *CSVWriter) WriteToDirectory(dirPath string, prMetrics []*api.PRMetrics,
 	}
 
 	// Write PR metrics
-	prFilePath := filepath.Join(dirPath, "pr_metrics.csv")
+	prFilePath := namer.Path("pr_metrics.csv")
 	if err := w.writePRMetricsCSV(prFilePath, prMetrics); err != nil {
 		return fmt.Errorf("failed to write PR metrics: %v", err)
 	}
 
 	// Write weekly metrics
-	weeklyFilePath := filepath.Join(dirPath, "weekly_metrics.csv")
+	weeklyFilePath := namer.Path("weekly_metrics.csv")
 	if err := w.writeAggregatedMetricsCSV(weeklyFilePath, weeklyMetrics, "Weekly"); err != nil {
 		return fmt.Errorf("failed to write weekly metrics: %v", err)
 	}
 
 	// Write monthly metrics
-	monthlyFilePath := filepath.Join(dirPath, "monthly_metrics.csv")
+	monthlyFilePath := namer.Path("monthly_metrics.csv")
 	if err := w.writeAggregatedMetricsCSV(monthlyFilePath, monthlyMetrics, "Monthly"); err != nil {
 		return fmt.Errorf("failed to write monthly metrics: %v", err)
 	}
 
+	// Record the schema so later releases can migrate the files
+	if err := WriteMetadata(namer.Path("metadata.json")); err != nil {
+		return fmt.Errorf("failed to write metadata: %v", err)
+	}
+
 	w.logger.Info("Successfully wrote metrics to directory: %s", dirPath)
 	return nil
 }
@@ -74,76 +121,138 @@ func (w *CSVWriter) writePRMetricsCSV(filename string, prMetrics []*api.PRMetric
 		}
 	}()
 
-	writer := csv.NewWriter(file)
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	header, rows := w.prMetricsTable(prMetrics)
+	columns, err := selectColumns(header, w.options.PRColumns)
+	if err != nil {
+		return err
+	}
+
+	// Write header
+	if err := writer.Write(w.projectHeader(header, columns)); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, row := range rows {
+		if err := writer.Write(projectRow(row, columns)); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d PR metrics to CSV file", len(prMetrics))
+	return nil
+}
+
+// Builds the header and rows of the PR metrics CSV
+func (w *CSVWriter) prMetricsTable(prMetrics []*api.PRMetrics) ([]string, [][]string) {
+	return marshalTable(w, prMetricsColumns, prMetrics)
+}
+
+// Formats and exports statistical metrics summaries to CSV format
+func (w *CSVWriter) writeAggregatedMetricsCSV(filename string, metrics []*api.AggregatedMetrics, metricsType string) error {
+	w.logger.Info("Writing %d %s metrics to CSV file: %s", len(metrics), metricsType, filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
 	defer writer.Flush()
 
+	header, rows := w.aggregatedMetricsTable(metrics)
+	columns, err := selectColumns(header, w.options.AggregatedColumns)
+	if err != nil {
+		return err
+	}
+
 	// Write header
-	header := []string{
-		"PR Number",
-		"Title",
-		"Author",
-		"Milestone",
-		"Created At",
-		"Merged At",
-		"State",
-		"Commit Count",
-		"First Commit At",
-		"Last Commit At",
-		"First Commit to Create (Hours)",
-		"Create to Last Commit (Hours)",
-		"Commit Count During PR",
-		"First Commit to Merge (Hours)",
-		"Last Commit to Merge (Hours)",
-		"Comment Count",
-		"First Comment At",
-		"Created to First Comment (Hours)",
-		"Review Count",
-		"Approval Count",
-		"Time to Approval (Hours)",
-		"Total PR Lifetime (Hours)",
-		"Max No Comment Period (Hours)",
-		"Max No Commit Period (Hours)",
-		"Max No Activity Period (Hours)",
-		"Additions",
-		"Deletions",
-		"Changed Files",
+	if err := writer.Write(w.projectHeader(header, columns)); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, row := range rows {
+		if err := writer.Write(projectRow(row, columns)); err != nil {
+			return err
+		}
 	}
 
+	w.logger.Info("Successfully wrote %d %s metrics to CSV file", len(metrics), metricsType)
+	return nil
+}
+
+// Builds the header and rows of an aggregated metrics CSV
+func (w *CSVWriter) aggregatedMetricsTable(metrics []*api.AggregatedMetrics) ([]string, [][]string) {
+	return marshalTable(w, aggregatedMetricsColumns, metrics)
+}
+
+// Returns the columns of the weekly and monthly CSVs, the names config files refer to metrics by
+func AggregatedColumns() []string {
+	header, _ := (&CSVWriter{}).aggregatedMetricsTable(nil)
+	return header
+}
+
+// Returns the value of a numeric column of the weekly and monthly CSVs for one period
+func AggregatedMetricValue(metrics *api.AggregatedMetrics, column string) (float64, bool) {
+	header, rows := (&CSVWriter{}).aggregatedMetricsTable([]*api.AggregatedMetrics{metrics})
+	index := slices.Index(header, column)
+	if index < 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(rows[0][index], 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// Exports SLO attainment, one row per SLO and period
+func (w *CSVWriter) WriteSLOCSV(filename string, attainments []*api.SLOAttainment) error {
+	w.logger.Info("Writing %d SLO attainment rows to CSV file: %s", len(attainments), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "SLO", "Label", "Target", "Within (Hours)", "PR Count", "Met Count", "Missed Count", "Attainment (%)"}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
 
 	// Write data
-	for _, pr := range prMetrics {
+	for _, attainment := range attainments {
 		row := []string{
-			strconv.Itoa(pr.Number),
-			pr.Title,
-			pr.Author,
-			pr.Milestone,
-			formatTime(pr.CreatedAt),
-			formatTime(pr.MergedAt),
-			pr.State,
-			strconv.Itoa(pr.CommitCount),
-			formatTime(pr.FirstCommitAt),
-			formatTime(pr.LastCommitAt),
-			formatFloat(pr.FirstCommitToCreateHours),
-			formatFloat(pr.CreateToLastCommitHours),
-			strconv.Itoa(pr.CommitCountDuringPR),
-			formatFloat(pr.FirstCommitToMergeHours),
-			formatFloat(pr.LastCommitToMergeHours),
-			strconv.Itoa(pr.CommentCount),
-			formatTime(pr.FirstCommentAt),
-			formatFloat(pr.CreatedToFirstCommentHours),
-			strconv.Itoa(pr.ReviewCount),
-			strconv.Itoa(pr.ApprovalCount),
-			formatFloat(pr.TimeToApprovalHours),
-			formatFloat(pr.TotalPRLifetimeHours),
-			formatFloat(pr.MaxNoCommentPeriodHours),
-			formatFloat(pr.MaxNoCommitPeriodHours),
-			formatFloat(pr.MaxNoActivityPeriodHours),
-			strconv.Itoa(pr.Additions),
-			strconv.Itoa(pr.Deletions),
-			strconv.Itoa(pr.ChangedFiles),
+			attainment.Granularity,
+			attainment.Period,
+			w.formatTime(attainment.StartDate),
+			w.formatTime(attainment.EndDate),
+			attainment.SLO,
+			attainment.Label,
+			attainment.Target,
+			w.formatFloat(attainment.WithinHours),
+			strconv.Itoa(attainment.PRCount),
+			strconv.Itoa(attainment.MetCount),
+			strconv.Itoa(attainment.MissedCount),
+			w.formatFloat(attainment.AttainmentPercent),
 		}
 
 		if err := writer.Write(row); err != nil {
@@ -151,13 +260,13 @@ func (w *CSVWriter) writePRMetricsCSV(filename string, prMetrics []*api.PRMetric
 		}
 	}
 
-	w.logger.Info("Successfully wrote %d PR metrics to CSV file", len(prMetrics))
+	w.logger.Info("Successfully wrote SLO attainment to CSV file")
 	return nil
 }
 
-// Formats and exports statistical metrics summaries to CSV format
-func (w *CSVWriter) writeAggregatedMetricsCSV(filename string, metrics []*api.AggregatedMetrics, metricsType string) error {
-	w.logger.Info("Writing %d %s metrics to CSV file: %s", len(metrics), metricsType, filename)
+// Exports the dependency update metrics, one row per period
+func (w *CSVWriter) WriteDependencyUpdatesCSV(filename string, updates []*api.DependencyUpdateMetrics) error {
+	w.logger.Info("Writing %d dependency update rows to CSV file: %s", len(updates), filename)
 
 	file, err := os.Create(filename)
 	if err != nil {
@@ -169,100 +278,121 @@ func (w *CSVWriter) writeAggregatedMetricsCSV(filename string, metrics []*api.Ag
 		}
 	}()
 
-	writer := csv.NewWriter(file)
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "PR Count", "Merged Count", "Auto Merged Count", "Closed Unmerged Count", "Open Count", "Auto Merge (%)", "Failure (%)", "Avg Time to Merge (Hours)", "Median Time to Merge (Hours)"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, update := range updates {
+		row := []string{
+			update.Granularity,
+			update.Period,
+			w.formatTime(update.StartDate),
+			w.formatTime(update.EndDate),
+			strconv.Itoa(update.PRCount),
+			strconv.Itoa(update.MergedCount),
+			strconv.Itoa(update.AutoMergedCount),
+			strconv.Itoa(update.ClosedUnmergedCount),
+			strconv.Itoa(update.OpenCount),
+			w.formatFloat(update.AutoMergePercent),
+			w.formatFloat(update.FailurePercent),
+			w.formatFloat(update.AvgTimeToMergeHours),
+			w.formatFloat(update.MedianTimeToMergeHours),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote dependency update metrics to CSV file")
+	return nil
+}
+
+// Exports the aggregated metrics of each category, one row per category and period, with
+// the columns of the weekly and monthly CSVs
+func (w *CSVWriter) WriteCategoryMetricsCSV(filename string, categoryMetrics []*api.CategoryAggregatedMetrics) error {
+	w.logger.Info("Writing %d category metrics rows to CSV file: %s", len(categoryMetrics), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
 	defer writer.Flush()
 
+	metrics := make([]*api.AggregatedMetrics, len(categoryMetrics))
+	for i, category := range categoryMetrics {
+		metrics[i] = category.Metrics
+	}
+	header, rows := w.aggregatedMetricsTable(metrics)
+	columns, err := selectColumns(header, w.options.AggregatedColumns)
+	if err != nil {
+		return err
+	}
+
 	// Write header
-	header := []string{
-		"Period",
-		"Start Date",
-		"End Date",
-		"PR Count",
-		"Avg Commit Count",
-		"Median Commit Count",
-		"Avg Comment Count",
-		"Median Comment Count",
-		"Avg Review Count",
-		"Median Review Count",
-		"Avg Approval Count",
-		"Median Approval Count",
-		"Avg Additions",
-		"Median Additions",
-		"Avg Deletions",
-		"Median Deletions",
-		"Avg Changed Files",
-		"Median Changed Files",
-		"Avg First Commit to Create (Hours)",
-		"Median First Commit to Create (Hours)",
-		"Avg Create to Last Commit (Hours)",
-		"Median Create to Last Commit (Hours)",
-		"Avg Commit Count During PR",
-		"Median Commit Count During PR",
-		"Avg First Commit to Merge (Hours)",
-		"Median First Commit to Merge (Hours)",
-		"Avg Last Commit to Merge (Hours)",
-		"Median Last Commit to Merge (Hours)",
-		"Avg Created to First Comment (Hours)",
-		"Median Created to First Comment (Hours)",
-		"Avg Time to Approval (Hours)",
-		"Median Time to Approval (Hours)",
-		"Avg Total PR Lifetime (Hours)",
-		"Median Total PR Lifetime (Hours)",
-		"Avg Max No Comment Period (Hours)",
-		"Median Max No Comment Period (Hours)",
-		"Avg Max No Commit Period (Hours)",
-		"Median Max No Commit Period (Hours)",
-		"Avg Max No Activity Period (Hours)",
-		"Median Max No Activity Period (Hours)",
+	if err := writer.Write(append([]string{"Granularity", "Category"}, w.projectHeader(header, columns)...)); err != nil {
+		return err
+	}
+
+	// Write data
+	for i, row := range rows {
+		if err := writer.Write(append([]string{categoryMetrics[i].Granularity, categoryMetrics[i].Category}, projectRow(row, columns)...)); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote category metrics to CSV file")
+	return nil
+}
+
+// Exports the changed lines per language, one row per language and period
+func (w *CSVWriter) WriteLanguageMixCSV(filename string, mixes []*api.LanguageMix) error {
+	w.logger.Info("Writing %d language mix rows to CSV file: %s", len(mixes), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
 
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "Language", "PR Count", "Additions", "Deletions", "Share of Changed Lines (%)"}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
 
 	// Write data
-	for _, m := range metrics {
+	for _, mix := range mixes {
 		row := []string{
-			m.Period,
-			formatTime(m.StartDate),
-			formatTime(m.EndDate),
-			strconv.Itoa(m.PRCount),
-			formatFloat(m.AvgCommitCount),
-			formatFloat(m.MedianCommitCount),
-			formatFloat(m.AvgCommentCount),
-			formatFloat(m.MedianCommentCount),
-			formatFloat(m.AvgReviewCount),
-			formatFloat(m.MedianReviewCount),
-			formatFloat(m.AvgApprovalCount),
-			formatFloat(m.MedianApprovalCount),
-			formatFloat(m.AvgAdditions),
-			formatFloat(m.MedianAdditions),
-			formatFloat(m.AvgDeletions),
-			formatFloat(m.MedianDeletions),
-			formatFloat(m.AvgChangedFiles),
-			formatFloat(m.MedianChangedFiles),
-			formatFloat(m.AvgFirstCommitToCreateHours),
-			formatFloat(m.MedianFirstCommitToCreateHours),
-			formatFloat(m.AvgCreateToLastCommitHours),
-			formatFloat(m.MedianCreateToLastCommitHours),
-			formatFloat(m.AvgCommitCountDuringPR),
-			formatFloat(m.MedianCommitCountDuringPR),
-			formatFloat(m.AvgFirstCommitToMergeHours),
-			formatFloat(m.MedianFirstCommitToMergeHours),
-			formatFloat(m.AvgLastCommitToMergeHours),
-			formatFloat(m.MedianLastCommitToMergeHours),
-			formatFloat(m.AvgCreatedToFirstCommentHours),
-			formatFloat(m.MedianCreatedToFirstCommentHours),
-			formatFloat(m.AvgTimeToApprovalHours),
-			formatFloat(m.MedianTimeToApprovalHours),
-			formatFloat(m.AvgTotalPRLifetimeHours),
-			formatFloat(m.MedianTotalPRLifetimeHours),
-			formatFloat(m.AvgMaxNoCommentPeriodHours),
-			formatFloat(m.MedianMaxNoCommentPeriodHours),
-			formatFloat(m.AvgMaxNoCommitPeriodHours),
-			formatFloat(m.MedianMaxNoCommitPeriodHours),
-			formatFloat(m.AvgMaxNoActivityPeriodHours),
-			formatFloat(m.MedianMaxNoActivityPeriodHours),
+			mix.Granularity,
+			mix.Period,
+			w.formatTime(mix.StartDate),
+			w.formatTime(mix.EndDate),
+			mix.Language,
+			strconv.Itoa(mix.PRCount),
+			strconv.Itoa(mix.Additions),
+			strconv.Itoa(mix.Deletions),
+			w.formatFloat(mix.SharePercent),
 		}
 
 		if err := writer.Write(row); err != nil {
@@ -270,7 +400,490 @@ func (w *CSVWriter) writeAggregatedMetricsCSV(filename string, metrics []*api.Ag
 		}
 	}
 
-	w.logger.Info("Successfully wrote %d %s metrics to CSV file", len(metrics), metricsType)
+	w.logger.Info("Successfully wrote language mix to CSV file")
+	return nil
+}
+
+// Exports the weekly cohorts of opened PRs, one row per cohort with the share of PRs merged within each
+// number of days
+func (w *CSVWriter) WriteCohortsCSV(filename string, cohorts []*api.Cohort) error {
+	w.logger.Info("Writing %d cohorts to CSV file: %s", len(cohorts), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Cohort", "Start Date", "End Date", "Opened"}
+	for _, days := range metrics.CohortMergeDays {
+		header = append(header, fmt.Sprintf("Merged Within %dd (%%)", days))
+	}
+	header = append(header,
+		fmt.Sprintf("Merged After %dd (%%)", metrics.CohortMergeDays[len(metrics.CohortMergeDays)-1]),
+		"Closed Unmerged (%)", "Still Open (%)")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, cohort := range cohorts {
+		percent := func(count int) string {
+			return w.formatFloat(float64(count) / float64(cohort.OpenedCount) * 100)
+		}
+		row := []string{
+			cohort.Period,
+			w.formatTime(cohort.StartDate),
+			w.formatTime(cohort.EndDate),
+			strconv.Itoa(cohort.OpenedCount),
+		}
+		for _, count := range cohort.MergedCount {
+			row = append(row, percent(count))
+		}
+		row = append(row, percent(cohort.MergedLaterCount), percent(cohort.ClosedCount), percent(cohort.OpenCount))
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote cohorts to CSV file")
+	return nil
+}
+
+// Exports the PR cadence of each author, one row per author
+func (w *CSVWriter) WriteAuthorCadenceCSV(filename string, cadences []*api.AuthorCadence) error {
+	w.logger.Info("Writing %d author cadences to CSV file: %s", len(cadences), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Author", "PR Count", "First Created At", "Last Created At", "Median Days Between PRs"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, cadence := range cadences {
+		// An author with a single PR has no gap to take the median of
+		median := ""
+		if cadence.PRCount > 1 {
+			median = w.formatFloat(cadence.MedianDaysBetweenPRs)
+		}
+		row := []string{
+			cadence.Author,
+			strconv.Itoa(cadence.PRCount),
+			w.formatTime(cadence.FirstCreatedAt),
+			w.formatTime(cadence.LastCreatedAt),
+			median,
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote author cadences to CSV file")
+	return nil
+}
+
+// Exports the team's PR cadence, one row per period
+func (w *CSVWriter) WriteTeamCadenceCSV(filename string, cadences []*api.TeamCadence) error {
+	w.logger.Info("Writing %d team cadences to CSV file: %s", len(cadences), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "PR Count", "Author Count", "Gap Count", "Median Days Between PRs"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, cadence := range cadences {
+		median := ""
+		if cadence.GapCount > 0 {
+			median = w.formatFloat(cadence.MedianDaysBetweenPRs)
+		}
+		row := []string{
+			cadence.Granularity,
+			cadence.Period,
+			w.formatTime(cadence.StartDate),
+			w.formatTime(cadence.EndDate),
+			strconv.Itoa(cadence.PRCount),
+			strconv.Itoa(cadence.AuthorCount),
+			strconv.Itoa(cadence.GapCount),
+			median,
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote team cadences to CSV file")
+	return nil
+}
+
+// Exports activity heatmaps, one row per period, activity, and day of week with a column per hour
+func (w *CSVWriter) WriteHeatmapCSV(filename string, heatmaps []*api.Heatmap) error {
+	w.logger.Info("Writing %d heatmaps to CSV file: %s", len(heatmaps), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "Activity", "Day"}
+	for hour := range 24 {
+		header = append(header, fmt.Sprintf("%02d", hour))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, heatmap := range heatmaps {
+		for day, hours := range heatmap.Counts {
+			row := []string{
+				heatmap.Granularity,
+				heatmap.Period,
+				w.formatTime(heatmap.StartDate),
+				w.formatTime(heatmap.EndDate),
+				heatmap.Activity,
+				time.Weekday((day + 1) % 7).String(),
+			}
+			for _, count := range hours {
+				row = append(row, strconv.Itoa(count))
+			}
+
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.logger.Info("Successfully wrote heatmaps to CSV file")
+	return nil
+}
+
+// Exports the review latency by creation time as a pivot table with a row per day of week and a
+// column per hour, each followed by a total over all of them. Median hours to first review come
+// first, then the reviewed PR counts; cells without reviewed PRs are left empty.
+func (w *CSVWriter) WriteCreationTimeReviewLatencyCSV(filename string, latency *api.CreationTimeReviewLatency) error {
+	w.logger.Info("Writing review latency by creation time to CSV file: %s", filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Metric", "Day"}
+	for hour := range 24 {
+		header = append(header, fmt.Sprintf("%02d", hour))
+	}
+	header = append(header, "All")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, metric := range []string{"median_hours_to_first_review", "reviewed_prs"} {
+		for day := range latency.PRCounts {
+			dayName := "All"
+			if day < 7 {
+				dayName = time.Weekday((day + 1) % 7).String()
+			}
+			row := []string{metric, dayName}
+			for hour, count := range latency.PRCounts[day] {
+				switch {
+				case count == 0:
+					row = append(row, "")
+				case metric == "reviewed_prs":
+					row = append(row, strconv.Itoa(count))
+				default:
+					row = append(row, w.formatFloat(latency.MedianHours[day][hour]))
+				}
+			}
+
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.logger.Info("Successfully wrote review latency by creation time to CSV file")
+	return nil
+}
+
+// Exports review routing accuracy, one row per period
+func (w *CSVWriter) WriteReviewRoutingCSV(filename string, routings []*api.ReviewRouting) error {
+	w.logger.Info("Writing %d review routing rows to CSV file: %s", len(routings), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "PR Count", "Request Count", "Requested Reviewed (%)", "Reviewer Count", "Outsider Reviews (%)", "Code Owner Requests (%)", "Code Owner Reviewers (%)"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, routing := range routings {
+		row := []string{
+			routing.Granularity,
+			routing.Period,
+			w.formatTime(routing.StartDate),
+			w.formatTime(routing.EndDate),
+			strconv.Itoa(routing.PRCount),
+			strconv.Itoa(routing.RequestCount),
+			w.formatFloat(routing.RequestReviewedPercent),
+			strconv.Itoa(routing.ReviewerCount),
+			w.formatFloat(routing.OutsiderReviewPercent),
+			w.formatFloat(routing.CodeOwnerRequestPercent),
+			w.formatFloat(routing.CodeOwnerReviewerPercent),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote review routing to CSV file")
+	return nil
+}
+
+// Exports review comments by file type, one row per file type and period
+func (w *CSVWriter) WriteFileTypeCommentsCSV(filename string, fileTypeComments []*api.FileTypeComments) error {
+	w.logger.Info("Writing %d file type comment rows to CSV file: %s", len(fileTypeComments), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "File Type", "PR Count", "Changed Files", "Changed Lines", "Review Comment Count", "Share of Comments (%)", "Comments per 100 Lines"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, comments := range fileTypeComments {
+		row := []string{
+			comments.Granularity,
+			comments.Period,
+			w.formatTime(comments.StartDate),
+			w.formatTime(comments.EndDate),
+			comments.FileType,
+			strconv.Itoa(comments.PRCount),
+			strconv.Itoa(comments.ChangedFiles),
+			strconv.Itoa(comments.ChangedLines),
+			strconv.Itoa(comments.ReviewCommentCount),
+			w.formatFloat(comments.SharePercent),
+			w.formatFloat(comments.CommentsPer100Lines),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote file type comments to CSV file")
+	return nil
+}
+
+// Exports the review bus factor, one row per component and period
+func (w *CSVWriter) WriteBusFactorCSV(filename string, busFactors []*api.BusFactor) error {
+	w.logger.Info("Writing %d bus factor rows to CSV file: %s", len(busFactors), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Granularity", "Period", "Start Date", "End Date", "Component", "PR Count", "Bus Factor", "Reviewers", "Single Reviewer"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, busFactor := range busFactors {
+		row := []string{
+			busFactor.Granularity,
+			busFactor.Period,
+			w.formatTime(busFactor.StartDate),
+			w.formatTime(busFactor.EndDate),
+			busFactor.Component,
+			strconv.Itoa(busFactor.PRCount),
+			strconv.Itoa(busFactor.ReviewerCount),
+			strings.Join(busFactor.Reviewers, labelSeparator),
+			strconv.FormatBool(busFactor.SingleReviewer),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote bus factor to CSV file")
+	return nil
+}
+
+// Exports data quality issues, one row per affected PR field
+func (w *CSVWriter) WriteDataQualityCSV(filename string, issues []*api.DataQualityIssue) error {
+	w.logger.Info("Writing %d data quality issues to CSV file: %s", len(issues), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	if err := writer.Write([]string{"PR Number", "Field", "Value", "Problem", "Action"}); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, issue := range issues {
+		row := []string{
+			strconv.Itoa(issue.PRNumber),
+			issue.Field,
+			w.formatFloat(issue.Value),
+			issue.Problem,
+			issue.Action,
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d data quality issues to CSV file", len(issues))
+	return nil
+}
+
+// Exports per-PR failures, one row per failed stage
+func (w *CSVWriter) WriteErrorsCSV(filename string, prErrors []*api.PRError) error {
+	w.logger.Info("Writing %d errors to CSV file: %s", len(prErrors), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	if err := writer.Write([]string{"PR Number", "Stage", "Error", "Skipped"}); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, prError := range prErrors {
+		row := []string{
+			strconv.Itoa(prError.PRNumber),
+			prError.Stage,
+			prError.Message,
+			strconv.FormatBool(prError.Skipped),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d errors to CSV file", len(prErrors))
 	return nil
 }
 
@@ -282,6 +895,73 @@ func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
+// Formats a time with the configured layout in UTC, or as RFC3339 by default
+func (w *CSVWriter) formatTime(t time.Time) string {
+	if w.options.TimeLayout == "" || t.IsZero() {
+		return formatTime(t)
+	}
+	return t.UTC().Format(w.options.TimeLayout)
+}
+
+// Formats the changed lines per language, such as "Go:+120/-30;Markdown:+4/-0"
+func formatLanguages(languages []api.LanguageChange) string {
+	formatted := make([]string, len(languages))
+	for i, language := range languages {
+		formatted[i] = fmt.Sprintf("%s:+%d/-%d", language.Language, language.Additions, language.Deletions)
+	}
+	return strings.Join(formatted, labelSeparator)
+}
+
+// Creates a CSV writer using the configured delimiter
+func (w *CSVWriter) newWriter(file *os.File) *csv.Writer {
+	writer := csv.NewWriter(file)
+	if w.options.Delimiter != "" {
+		writer.Comma, _ = utf8.DecodeRuneInString(w.options.Delimiter)
+	}
+	return writer
+}
+
+// Resolves the configured columns to indexes into the full header, defaulting to all columns
+func selectColumns(header, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		indexes := make([]int, len(header))
+		for i := range header {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		index := slices.Index(header, column)
+		if index < 0 {
+			return nil, fmt.Errorf("unknown CSV column: %s", column)
+		}
+		indexes[i] = index
+	}
+	return indexes, nil
+}
+
+// Picks the selected columns from a row
+func projectRow(row []string, indexes []int) []string {
+	projected := make([]string, len(indexes))
+	for i, index := range indexes {
+		projected[i] = row[index]
+	}
+	return projected
+}
+
+// Picks the selected columns from the header and applies the configured header names
+func (w *CSVWriter) projectHeader(header []string, indexes []int) []string {
+	projected := projectRow(header, indexes)
+	for i, name := range projected {
+		if renamed, exists := w.options.HeaderNames[name]; exists {
+			projected[i] = renamed
+		}
+	}
+	return projected
+}
+
 // Formats floating point values with 2 decimal places
 func formatFloat(f float64) string {
 	if f == 0 {
@@ -289,3 +969,12 @@ func formatFloat(f float64) string {
 	}
 	return strconv.FormatFloat(f, 'f', 2, 64)
 }
+
+// Formats floating point values using the configured decimal separator
+func (w *CSVWriter) formatFloat(f float64) string {
+	formatted := formatFloat(f)
+	if w.options.DecimalSeparator != "" {
+		formatted = strings.Replace(formatted, ".", w.options.DecimalSeparator, 1)
+	}
+	return formatted
+}
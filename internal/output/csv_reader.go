@@ -0,0 +1,169 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Reads PR metrics back from a pr_metrics.csv written with the same options, or from its .gz
+// Columns missing from the file are left at their zero values
+func (w *CSVWriter) ReadPRMetricsCSV(filename string) ([]*api.PRMetrics, error) {
+	return w.ReadPRMetricsCSVVersion(filename, SchemaVersion)
+}
+
+// Reads PR metrics back from a pr_metrics.csv written in an earlier schema version, carrying
+// the values of renamed columns over to their current names
+func (w *CSVWriter) ReadPRMetricsCSVVersion(filename string, schemaVersion int) ([]*api.PRMetrics, error) {
+	w.logger.Info("Reading PR metrics from CSV file: %s", filename)
+
+	file, err := openMaybeCompressed(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	reader := csv.NewReader(file)
+	if w.options.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(w.options.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+
+	// Map renamed headers back to the default column names
+	defaultNames := make(map[string]string, len(w.options.HeaderNames))
+	for name, renamed := range w.options.HeaderNames {
+		defaultNames[renamed] = name
+	}
+	fieldIndexes := make(map[string]int, len(prMetricsColumns))
+	for _, column := range prMetricsColumns {
+		fieldIndexes[column.name] = column.field
+	}
+	// Unknown columns map to -1 and are ignored
+	fields := make([]int, len(header))
+	hasNumber := false
+	for i, name := range header {
+		if defaultName, exists := defaultNames[name]; exists {
+			name = defaultName
+		}
+		name = currentColumnName(name, schemaVersion)
+		fields[i] = -1
+		if field, exists := fieldIndexes[name]; exists {
+			fields[i] = field
+		}
+		hasNumber = hasNumber || name == "PR Number"
+	}
+	if !hasNumber {
+		return nil, fmt.Errorf("missing PR Number column")
+	}
+
+	var prMetrics []*api.PRMetrics
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pr := &api.PRMetrics{}
+		record := reflect.ValueOf(pr).Elem()
+		for i, value := range row {
+			if fields[i] < 0 {
+				continue
+			}
+			if err := w.parseValue(record.Field(fields[i]), value); err != nil {
+				return nil, fmt.Errorf("line %d, column %s: %v", line, header[i], err)
+			}
+		}
+		prMetrics = append(prMetrics, pr)
+	}
+
+	w.logger.Info("Read %d PR metrics from CSV file", len(prMetrics))
+	return prMetrics, nil
+}
+
+// Replaces existing PRs with updated rows of the same number and appends new ones
+func MergePRMetrics(existing, updated []*api.PRMetrics) []*api.PRMetrics {
+	updatedByNumber := make(map[int]*api.PRMetrics, len(updated))
+	for _, pr := range updated {
+		updatedByNumber[pr.Number] = pr
+	}
+
+	merged := make([]*api.PRMetrics, 0, len(existing)+len(updated))
+	seen := make(map[int]bool, len(existing))
+	for _, pr := range existing {
+		if replacement, exists := updatedByNumber[pr.Number]; exists {
+			pr = replacement
+		}
+		merged = append(merged, pr)
+		seen[pr.Number] = true
+	}
+	for _, pr := range updated {
+		if !seen[pr.Number] {
+			merged = append(merged, pr)
+		}
+	}
+
+	return merged
+}
+
+// Parses an RFC3339 time, treating an empty string as zero
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Parses a time written with the configured layout, treating an empty string as zero
+func (w *CSVWriter) parseTime(value string) (time.Time, error) {
+	if w.options.TimeLayout == "" || value == "" {
+		return parseTime(value)
+	}
+	return time.Parse(w.options.TimeLayout, value)
+}
+
+// Parses the changed lines per language written by formatLanguages
+func parseLanguages(value string) ([]api.LanguageChange, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var languages []api.LanguageChange
+	for _, entry := range strings.Split(value, labelSeparator) {
+		index := strings.LastIndex(entry, ":")
+		if index < 0 {
+			return nil, fmt.Errorf("invalid language entry %q", entry)
+		}
+		language := api.LanguageChange{Language: entry[:index]}
+		if _, err := fmt.Sscanf(entry[index+1:], "+%d/-%d", &language.Additions, &language.Deletions); err != nil {
+			return nil, fmt.Errorf("invalid language entry %q: %v", entry, err)
+		}
+		languages = append(languages, language)
+	}
+	return languages, nil
+}
+
+// Parses a floating point value written with the configured decimal separator
+func (w *CSVWriter) parseFloat(value string) (float64, error) {
+	if w.options.DecimalSeparator != "" {
+		value = strings.Replace(value, w.options.DecimalSeparator, ".", 1)
+	}
+	return strconv.ParseFloat(value, 64)
+}
@@ -0,0 +1,183 @@
+package output
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Supported event export formats
+const (
+	EventFormatCSV   = "csv"
+	EventFormatJSONL = "jsonl"
+)
+
+// Handles exporting the normalized PR event stream for downstream analysis
+type EventWriter struct {
+	logger *utils.Logger
+}
+
+// Initializes event writer with logger dependency
+func NewEventWriter(logger *utils.Logger) *EventWriter {
+	return &EventWriter{
+		logger: logger,
+	}
+}
+
+// Exports the events of all PRs in the given format
+func (w *EventWriter) Write(filename, format string, prMetrics []*api.PRMetrics) error {
+	switch format {
+	case EventFormatCSV:
+		return w.writeCSV(filename, prMetrics)
+	case EventFormatJSONL:
+		return w.writeJSONL(filename, prMetrics)
+	default:
+		return fmt.Errorf("unsupported event format: %s", format)
+	}
+}
+
+// Formats and exports events as CSV rows
+func (w *EventWriter) writeCSV(filename string, prMetrics []*api.PRMetrics) error {
+	w.logger.Info("Writing PR events to CSV file: %s", filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	if err := writer.Write([]string{"PR Number", "Type", "Timestamp", "Actor", "Detail", "Code Owner"}); err != nil {
+		return err
+	}
+
+	// Write data
+	count := 0
+	for _, pr := range prMetrics {
+		for _, event := range pr.Events {
+			row := []string{
+				strconv.Itoa(event.PRNumber),
+				event.Type,
+				formatTime(event.Timestamp),
+				event.Actor,
+				event.Detail,
+				strconv.FormatBool(event.CodeOwner),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			count++
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d PR events to CSV file", count)
+	return nil
+}
+
+// Formats and exports events as one JSON object per line
+func (w *EventWriter) writeJSONL(filename string, prMetrics []*api.PRMetrics) error {
+	w.logger.Info("Writing PR events to JSONL file: %s", filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	encoder := json.NewEncoder(file)
+	count := 0
+	for _, pr := range prMetrics {
+		for _, event := range pr.Events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+			count++
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d PR events to JSONL file", count)
+	return nil
+}
+
+// Reads back events written by Write, or their .gz, in the format given by the file extension
+func (w *EventWriter) Read(filename string) ([]api.PREvent, error) {
+	file, err := openMaybeCompressed(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	var events []api.PREvent
+	switch filepath.Ext(strings.TrimSuffix(filename, ".gz")) {
+	case "." + EventFormatJSONL:
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			var event api.PREvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			events = append(events, event)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case "." + EventFormatCSV:
+		reader := csv.NewReader(file)
+		// Skip header
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("failed to read header: %v", err)
+		}
+		for line := 2; ; line++ {
+			row, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Files written before the Code Owner column have five columns
+			if len(row) != 5 && len(row) != 6 {
+				return nil, fmt.Errorf("line %d: expected 6 columns, got %d", line, len(row))
+			}
+			event := api.PREvent{Type: row[1], Actor: row[3], Detail: row[4]}
+			if len(row) == 6 {
+				event.CodeOwner = row[5] == "true"
+			}
+			if event.PRNumber, err = strconv.Atoi(row[0]); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if event.Timestamp, err = parseTime(row[2]); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			events = append(events, event)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event file: %s", filename)
+	}
+	return events, nil
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Placeholder for the default name of each output file, such as pr_metrics.csv
+const FileNamePlaceholder = "{file}"
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// Builds output file paths from an optional name template, so several repositories
+// and runs can share one output directory
+type FileNamer struct {
+	dir      string
+	template string
+	named    []string        // Paths handed out, in order, for compressing the outputs of a run
+	seen     map[string]bool // Paths in named
+}
+
+// Expands the template placeholders other than {file}, validating that the template
+// names each output file distinctly and stays within the directory
+func NewFileNamer(dir, template string, values map[string]string) (*FileNamer, error) {
+	if template == "" {
+		return &FileNamer{dir: dir}, nil
+	}
+
+	if !strings.Contains(template, FileNamePlaceholder) {
+		return nil, fmt.Errorf("output name template must contain %s", FileNamePlaceholder)
+	}
+
+	var unknown []string
+	expanded := placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if placeholder == FileNamePlaceholder {
+			return placeholder
+		}
+		value, exists := values[strings.Trim(placeholder, "{}")]
+		if !exists {
+			unknown = append(unknown, placeholder)
+			return placeholder
+		}
+		// Nested groups and Azure DevOps projects contain slashes
+		return strings.ReplaceAll(value, "/", "_")
+	})
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown placeholder in output name template: %s", strings.Join(unknown, ", "))
+	}
+
+	if strings.ContainsAny(expanded, `/\`) {
+		return nil, fmt.Errorf("output name template must not contain path separators")
+	}
+
+	return &FileNamer{dir: dir, template: expanded}, nil
+}
+
+// Returns the output directory
+func (n *FileNamer) Dir() string {
+	return n.dir
+}
+
+// Returns the path for an output file given its default name
+func (n *FileNamer) Path(file string) string {
+	path := filepath.Join(n.dir, file)
+	if n.template != "" {
+		path = filepath.Join(n.dir, strings.ReplaceAll(n.template, FileNamePlaceholder, file))
+	}
+	if !n.seen[path] {
+		if n.seen == nil {
+			n.seen = make(map[string]bool)
+		}
+		n.seen[path] = true
+		n.named = append(n.named, path)
+	}
+	return path
+}
+
+// Returns every path handed out so far, including those of files only read or never written
+func (n *FileNamer) Named() []string {
+	return n.named
+}
+
+// Returns the paths handed out so far that exist as files, each replaced by its .gz if only that
+// exists, as left by --compress gzip
+func (n *FileNamer) Existing() []string {
+	var existing []string
+	for _, path := range n.named {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			existing = append(existing, path)
+		} else if info, err := os.Stat(path + ".gz"); err == nil && info.Mode().IsRegular() {
+			existing = append(existing, path+".gz")
+		}
+	}
+	return existing
+}
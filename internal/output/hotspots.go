@@ -0,0 +1,144 @@
+package output
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Hotspot types
+const (
+	HotspotTypeFile      = "file"
+	HotspotTypeDirectory = "directory"
+)
+
+// How often a file or directory changed across PRs and how those PRs fared in review
+type Hotspot struct {
+	Path                  string
+	Type                  string
+	PRCount               int
+	Additions             int
+	Deletions             int
+	ReviewCommentCount    int
+	AvgReviewLatencyHours float64 // Among PRs that received a review
+	reviewLatencyHoursSum float64
+	reviewedPRCount       int
+}
+
+// Churn is the total of additions and deletions
+func (h *Hotspot) Churn() int {
+	return h.Additions + h.Deletions
+}
+
+// Aggregates changed files and their parent directories across PRs, most frequently changed first
+func BuildHotspots(prMetrics []*api.PRMetrics) []*Hotspot {
+	hotspots := make(map[string]*Hotspot)
+	get := func(hotspotPath, hotspotType string) *Hotspot {
+		key := hotspotType + ":" + hotspotPath
+		hotspot, exists := hotspots[key]
+		if !exists {
+			hotspot = &Hotspot{Path: hotspotPath, Type: hotspotType}
+			hotspots[key] = hotspot
+		}
+		return hotspot
+	}
+
+	for _, pr := range prMetrics {
+		reviewed := len(pr.Reviewers) > 0
+		touched := make(map[*Hotspot]bool)
+
+		for _, file := range pr.Files {
+			targets := []*Hotspot{get(file.Path, HotspotTypeFile)}
+			for dir := path.Dir(file.Path); dir != "." && dir != "/"; dir = path.Dir(dir) {
+				targets = append(targets, get(dir, HotspotTypeDirectory))
+			}
+
+			for _, hotspot := range targets {
+				hotspot.Additions += file.Additions
+				hotspot.Deletions += file.Deletions
+				hotspot.ReviewCommentCount += file.ReviewCommentCount
+
+				// Count each PR once per directory even when it changed several files in it
+				if touched[hotspot] {
+					continue
+				}
+				touched[hotspot] = true
+				hotspot.PRCount++
+				if reviewed {
+					hotspot.reviewLatencyHoursSum += pr.WaitingForReviewHours
+					hotspot.reviewedPRCount++
+				}
+			}
+		}
+	}
+
+	result := make([]*Hotspot, 0, len(hotspots))
+	for _, hotspot := range hotspots {
+		if hotspot.reviewedPRCount > 0 {
+			hotspot.AvgReviewLatencyHours = hotspot.reviewLatencyHoursSum / float64(hotspot.reviewedPRCount)
+		}
+		result = append(result, hotspot)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PRCount != result[j].PRCount {
+			return result[i].PRCount > result[j].PRCount
+		}
+		if result[i].Churn() != result[j].Churn() {
+			return result[i].Churn() > result[j].Churn()
+		}
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Type < result[j].Type
+	})
+
+	return result
+}
+
+// Exports hotspots, most frequently changed first
+func (w *CSVWriter) WriteHotspotsCSV(filename string, hotspots []*Hotspot) error {
+	w.logger.Info("Writing %d hotspots to CSV file: %s", len(hotspots), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{"Path", "Type", "PR Count", "Additions", "Deletions", "Churn", "Review Comment Count", "Avg Review Latency (Hours)"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, hotspot := range hotspots {
+		row := []string{
+			hotspot.Path,
+			hotspot.Type,
+			strconv.Itoa(hotspot.PRCount),
+			strconv.Itoa(hotspot.Additions),
+			strconv.Itoa(hotspot.Deletions),
+			strconv.Itoa(hotspot.Churn()),
+			strconv.Itoa(hotspot.ReviewCommentCount),
+			w.formatFloat(hotspot.AvgReviewLatencyHours),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d hotspots to CSV file", len(hotspots))
+	return nil
+}
@@ -0,0 +1,235 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Available leaderboards
+const (
+	LeaderboardFastestReviewers  = "fastest_reviewers"
+	LeaderboardSlowestReviewers  = "slowest_reviewers"
+	LeaderboardLargestPRAuthors  = "largest_pr_authors"
+	LeaderboardMostReviewedFiles = "most_reviewed_files"
+)
+
+// Number of entries per leaderboard unless configured
+const defaultLeaderboardTop = 10
+
+// Selects the leaderboards written to leaderboard.csv
+type LeaderboardOptions struct {
+	Top       int      `json:"top"`       // Entries per leaderboard; defaults to 10
+	Metrics   []string `json:"metrics"`   // Leaderboards to include; defaults to all
+	Anonymize bool     `json:"anonymize"` // Replace logins with stable pseudonyms
+}
+
+// Validates the entry count and leaderboard names
+func (o LeaderboardOptions) Validate() error {
+	if o.Top < 0 {
+		return fmt.Errorf("leaderboard top must not be negative: %d", o.Top)
+	}
+	for _, metric := range o.Metrics {
+		if !slices.Contains(allLeaderboards, metric) {
+			return fmt.Errorf("unknown leaderboard: %s", metric)
+		}
+	}
+	return nil
+}
+
+var allLeaderboards = []string{
+	LeaderboardFastestReviewers,
+	LeaderboardSlowestReviewers,
+	LeaderboardLargestPRAuthors,
+	LeaderboardMostReviewedFiles,
+}
+
+// A ranked person or file on a leaderboard
+type LeaderboardEntry struct {
+	Leaderboard string
+	Rank        int
+	Name        string
+	Metric      string
+	Value       float64
+	PRCount     int
+}
+
+// Ranks reviewers, authors, and files across PRs for retrospectives
+func BuildLeaderboards(prMetrics []*api.PRMetrics, options LeaderboardOptions) []*LeaderboardEntry {
+	top := options.Top
+	if top == 0 {
+		top = defaultLeaderboardTop
+	}
+	metrics := options.Metrics
+	if len(metrics) == 0 {
+		metrics = allLeaderboards
+	}
+	anonymizer := NewAnonymizer("")
+	name := func(login string) string {
+		if options.Anonymize {
+			return anonymizer.Pseudonym(login)
+		}
+		return login
+	}
+
+	var entries []*LeaderboardEntry
+	for _, metric := range metrics {
+		var ranked []*LeaderboardEntry
+		switch metric {
+		case LeaderboardFastestReviewers, LeaderboardSlowestReviewers:
+			ranked = rankReviewers(prMetrics, metric == LeaderboardSlowestReviewers, name)
+		case LeaderboardLargestPRAuthors:
+			ranked = rankAuthors(prMetrics, name)
+		case LeaderboardMostReviewedFiles:
+			ranked = rankFiles(prMetrics)
+		}
+
+		for i, entry := range ranked[:min(top, len(ranked))] {
+			entry.Leaderboard = metric
+			entry.Rank = i + 1
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Ranks reviewers by their median hours to first review, fastest first unless reversed
+func rankReviewers(prMetrics []*api.PRMetrics, slowestFirst bool, name func(string) string) []*LeaderboardEntry {
+	hours := make(map[string][]float64)
+	for _, pr := range prMetrics {
+		for _, response := range pr.Reviewers {
+			hours[response.Reviewer] = append(hours[response.Reviewer], response.HoursToFirstReview)
+		}
+	}
+
+	var ranked []*LeaderboardEntry
+	for reviewer, values := range hours {
+		ranked = append(ranked, &LeaderboardEntry{
+			Name:    name(reviewer),
+			Metric:  "Median Hours to First Review",
+			Value:   median(values),
+			PRCount: len(values),
+		})
+	}
+	sortEntries(ranked, slowestFirst)
+	return ranked
+}
+
+// Ranks authors by the average lines changed per PR
+func rankAuthors(prMetrics []*api.PRMetrics, name func(string) string) []*LeaderboardEntry {
+	lines := make(map[string]int)
+	counts := make(map[string]int)
+	for _, pr := range prMetrics {
+		if pr.Author == "" {
+			continue
+		}
+		lines[pr.Author] += pr.Additions + pr.Deletions
+		counts[pr.Author]++
+	}
+
+	var ranked []*LeaderboardEntry
+	for author, count := range counts {
+		ranked = append(ranked, &LeaderboardEntry{
+			Name:    name(author),
+			Metric:  "Avg Lines Changed",
+			Value:   float64(lines[author]) / float64(count),
+			PRCount: count,
+		})
+	}
+	sortEntries(ranked, true)
+	return ranked
+}
+
+// Ranks files by the review comments they received
+func rankFiles(prMetrics []*api.PRMetrics) []*LeaderboardEntry {
+	comments := make(map[string]int)
+	counts := make(map[string]int)
+	for _, pr := range prMetrics {
+		for _, file := range pr.Files {
+			if file.ReviewCommentCount > 0 {
+				comments[file.Path] += file.ReviewCommentCount
+				counts[file.Path]++
+			}
+		}
+	}
+
+	var ranked []*LeaderboardEntry
+	for path, count := range comments {
+		ranked = append(ranked, &LeaderboardEntry{
+			Name:    path,
+			Metric:  "Review Comments",
+			Value:   float64(count),
+			PRCount: counts[path],
+		})
+	}
+	sortEntries(ranked, true)
+	return ranked
+}
+
+// Orders entries by value, breaking ties by name so the output is stable
+func sortEntries(entries []*LeaderboardEntry, descending bool) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return (entries[i].Value > entries[j].Value) == descending
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// Computes the median of unsorted values
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// Exports leaderboard entries, grouped by leaderboard in rank order
+func (w *CSVWriter) WriteLeaderboardCSV(filename string, entries []*LeaderboardEntry) error {
+	w.logger.Info("Writing %d leaderboard entries to CSV file: %s", len(entries), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	// Write header
+	if err := writer.Write([]string{"Leaderboard", "Rank", "Name", "Metric", "Value", "PR Count"}); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, entry := range entries {
+		row := []string{
+			entry.Leaderboard,
+			strconv.Itoa(entry.Rank),
+			entry.Name,
+			entry.Metric,
+			w.formatFloat(entry.Value),
+			strconv.Itoa(entry.PRCount),
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote %d leaderboard entries to CSV file", len(entries))
+	return nil
+}
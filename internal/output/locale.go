@@ -0,0 +1,101 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Number and date conventions of a region, so spreadsheets opened there parse the CSV files and the
+// report reads naturally. The zero value keeps the default dot decimals and ISO 8601 dates.
+type Locale struct {
+	Name             string
+	Delimiter        string // CSV field delimiter; a semicolon where the comma is the decimal separator
+	DecimalSeparator string
+	DateLayout       string // Go layout of dates in the report
+	TimeLayout       string // Go layout of times in the CSV files, one that spreadsheets of the region parse
+}
+
+// Supported locales, keyed by lowercase BCP 47 tag
+var locales = map[string]Locale{
+	"en-us": {Name: "en-US", Delimiter: ",", DecimalSeparator: ".", DateLayout: "01/02/2006", TimeLayout: "01/02/2006 15:04:05"},
+	"en-gb": {Name: "en-GB", Delimiter: ",", DecimalSeparator: ".", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04:05"},
+	"de-de": {Name: "de-DE", Delimiter: ";", DecimalSeparator: ",", DateLayout: "02.01.2006", TimeLayout: "02.01.2006 15:04:05"},
+	"fr-fr": {Name: "fr-FR", Delimiter: ";", DecimalSeparator: ",", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04:05"},
+	"es-es": {Name: "es-ES", Delimiter: ";", DecimalSeparator: ",", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04:05"},
+	"it-it": {Name: "it-IT", Delimiter: ";", DecimalSeparator: ",", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04:05"},
+	"nl-nl": {Name: "nl-NL", Delimiter: ";", DecimalSeparator: ",", DateLayout: "02-01-2006", TimeLayout: "02-01-2006 15:04:05"},
+	"pt-br": {Name: "pt-BR", Delimiter: ";", DecimalSeparator: ",", DateLayout: "02/01/2006", TimeLayout: "02/01/2006 15:04:05"},
+	"ja-jp": {Name: "ja-JP", Delimiter: ",", DecimalSeparator: ".", DateLayout: "2006/01/02", TimeLayout: "2006/01/02 15:04:05"},
+}
+
+// Returns the locale of a tag such as de-DE or de_DE, matched case-insensitively
+func LookupLocale(tag string) (Locale, error) {
+	locale, exists := locales[strings.ToLower(strings.ReplaceAll(tag, "_", "-"))]
+	if !exists {
+		return Locale{}, fmt.Errorf("unsupported locale %q: must be one of %s", tag, strings.Join(LocaleNames(), ", "))
+	}
+	return locale, nil
+}
+
+// Returns the tags of the supported locales, sorted
+func LocaleNames() []string {
+	names := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		names = append(names, locale.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Fills the options the config file left unset from the locale, so the file can still override
+// single settings
+func (o CSVOptions) WithLocale(locale Locale) CSVOptions {
+	if o.Delimiter == "" {
+		o.Delimiter = locale.Delimiter
+	}
+	if o.DecimalSeparator == "" {
+		o.DecimalSeparator = locale.DecimalSeparator
+	}
+	if o.TimeLayout == "" {
+		o.TimeLayout = locale.TimeLayout
+	}
+	return o
+}
+
+// Returns the formatting functions of the report templates. Mermaid charts keep dot decimals,
+// which their syntax requires.
+func (l Locale) templateFuncs() map[string]any {
+	return map[string]any{
+		"date":    l.formatDate,
+		"hours":   l.formatHours,
+		"percent": l.formatPercent,
+	}
+}
+
+// Formats a date for display in the report
+func (l Locale) formatDate(t time.Time) string {
+	if l.DateLayout == "" {
+		return formatReportDate(t)
+	}
+	return t.Format(l.DateLayout)
+}
+
+// Formats hours for display in the report
+func (l Locale) formatHours(f float64) string {
+	return l.localizeDecimal(formatReportHours(f))
+}
+
+// Formats a percentage for display in the report
+func (l Locale) formatPercent(f float64) string {
+	return l.localizeDecimal(formatReportPercent(f))
+}
+
+// Replaces the decimal point of a formatted number with the locale's separator
+func (l Locale) localizeDecimal(formatted string) string {
+	if l.DecimalSeparator == "" {
+		return formatted
+	}
+	return strings.Replace(formatted, ".", l.DecimalSeparator, 1)
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Renders the weekly PR count and median lead times as Mermaid xychart blocks, which GitHub
+// draws in Markdown files, issues, and wikis
+func mermaidTrendCharts(trends []WeeklyTrend) string {
+	periods := make([]string, len(trends))
+	counts := make([]string, len(trends))
+	lifetimes := make([]string, len(trends))
+	approvals := make([]string, len(trends))
+	for i, week := range trends {
+		periods[i] = strconv.Quote(week.Period)
+		counts[i] = strconv.Itoa(week.PRCount)
+		lifetimes[i] = formatReportHours(week.MedianTotalPRLifetimeHours)
+		approvals[i] = formatReportHours(week.MedianTimeToApprovalHours)
+	}
+	xAxis := "[" + strings.Join(periods, ", ") + "]"
+
+	var text strings.Builder
+	text.WriteString("```mermaid\nxychart-beta\n")
+	text.WriteString("    title \"PRs per Week\"\n")
+	fmt.Fprintf(&text, "    x-axis %s\n", xAxis)
+	text.WriteString("    y-axis \"PRs\"\n")
+	fmt.Fprintf(&text, "    bar [%s]\n", strings.Join(counts, ", "))
+	text.WriteString("```\n")
+
+	// xychart has no legend, so each chart holds a single series
+	for _, chart := range []struct {
+		title  string
+		values []string
+	}{
+		{"Median PR Lifetime (Hours)", lifetimes},
+		{"Median Time to Approval (Hours)", approvals},
+	} {
+		text.WriteString("\n```mermaid\nxychart-beta\n")
+		fmt.Fprintf(&text, "    title %q\n", chart.title)
+		fmt.Fprintf(&text, "    x-axis %s\n", xAxis)
+		text.WriteString("    y-axis \"Hours\"\n")
+		fmt.Fprintf(&text, "    line [%s]\n", strings.Join(chart.values, ", "))
+		text.WriteString("```\n")
+	}
+	return text.String()
+}
+
+// Renders the average phase hours as a Mermaid gantt block with one section per week, each
+// laying the phases of an average PR end to end from the start of the week
+func mermaidPhaseGantt(breakdown []PhaseBreakdown) string {
+	var text strings.Builder
+	text.WriteString("```mermaid\ngantt\n")
+	text.WriteString("    title Average PR Lifecycle per Week\n")
+	text.WriteString("    dateFormat YYYY-MM-DD HH:mm\n")
+	text.WriteString("    axisFormat %m-%d\n")
+	for i, week := range breakdown {
+		if week.TotalHours() <= 0 {
+			continue
+		}
+		fmt.Fprintf(&text, "    section %s\n", week.Period)
+
+		previous := ""
+		for j, phase := range []struct {
+			name  string
+			hours float64
+		}{
+			{"Coding", week.CodingHours},
+			{"Waiting for review", week.WaitingForReviewHours},
+			{"In review", week.InReviewHours},
+			{"Waiting to merge", week.WaitingToMergeHours},
+		} {
+			// Mermaid durations are whole units, so phases are rounded to minutes
+			minutes := int(math.Round(phase.hours * 60))
+			if minutes <= 0 {
+				continue
+			}
+			id := fmt.Sprintf("w%dp%d", i, j)
+			start := week.StartDate.Format("2006-01-02 15:04")
+			if previous != "" {
+				start = "after " + previous
+			}
+			fmt.Fprintf(&text, "    %s :%s, %s, %dm\n", phase.name, id, start, minutes)
+			previous = id
+		}
+	}
+	text.WriteString("```\n")
+	return text.String()
+}
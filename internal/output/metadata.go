@@ -0,0 +1,64 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Version of the layout of the CSV outputs, raised when a release renames, removes, or changes
+// the meaning of a column. Outputs written before metadata.json existed are version 0.
+const SchemaVersion = 1
+
+// Columns of pr_metrics.csv renamed by each schema version, from the old name to the new one
+var renamedColumns = map[int]map[string]string{
+	1: {"Comment Count": "Review Comment Count"},
+}
+
+// Returns the current name of a pr_metrics.csv column written in an earlier schema version
+func currentColumnName(name string, schemaVersion int) string {
+	for version := schemaVersion + 1; version <= SchemaVersion; version++ {
+		if renamed, exists := renamedColumns[version][name]; exists {
+			name = renamed
+		}
+	}
+	return name
+}
+
+// Contents of metadata.json, which records the layout the outputs next to it were written with
+type Metadata struct {
+	SchemaVersion int `json:"schema_version"`
+}
+
+// Exports the metadata of the current schema as indented JSON
+func WriteMetadata(filename string) error {
+	data, err := json.MarshalIndent(Metadata{SchemaVersion: SchemaVersion}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// Reads metadata.json, or its .gz, returning version 0 for outputs written before it existed
+func ReadMetadata(filename string) (*Metadata, error) {
+	file, err := openMaybeCompressed(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Metadata{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &Metadata{}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata file %s: %v", filename, err)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,355 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Supported report formats
+const (
+	ReportFormatHTML     = "html"
+	ReportFormatJSON     = "json"
+	ReportFormatMarkdown = "md"
+)
+
+// Summary of a run rendered for people rather than spreadsheets
+type Report struct {
+	Repository     string           `json:"repository"`
+	StartDate      time.Time        `json:"start_date"`
+	EndDate        time.Time        `json:"end_date"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+	PRCount        int              `json:"pr_count"`
+	MergedCount    int              `json:"merged_count"`
+	PhaseBreakdown []PhaseBreakdown `json:"phase_breakdown"`
+	WeeklyTrends   []WeeklyTrend    `json:"weekly_trends"`
+	Alerts         []api.Alert      `json:"alerts,omitempty"`       // Alert rules triggered by the run
+	CIFlakiness    []CIFlakiness    `json:"ci_flakiness,omitempty"` // Set when some PR re-ran a flaky check
+	Locale         Locale           `json:"-"`                      // Formatting of the numbers and dates in HTML and Markdown
+
+	// Set when the config defines working agreements
+	WorkingAgreements *api.AgreementScorecard `json:"working_agreements,omitempty"`
+}
+
+// Average hours spent in each PR lifecycle phase during one week, for a stacked chart
+type PhaseBreakdown struct {
+	Period                string    `json:"period"`
+	StartDate             time.Time `json:"start_date"`
+	PRCount               int       `json:"pr_count"`
+	CodingHours           float64   `json:"coding_hours"`
+	WaitingForReviewHours float64   `json:"waiting_for_review_hours"`
+	InReviewHours         float64   `json:"in_review_hours"`
+	WaitingToMergeHours   float64   `json:"waiting_to_merge_hours"`
+}
+
+// Total of all phases, the height of the stacked bar
+func (p PhaseBreakdown) TotalHours() float64 {
+	return p.CodingHours + p.WaitingForReviewHours + p.InReviewHours + p.WaitingToMergeHours
+}
+
+// Throughput and lead time of one week, for trend charts
+type WeeklyTrend struct {
+	Period                     string  `json:"period"`
+	PRCount                    int     `json:"pr_count"`
+	MedianTotalPRLifetimeHours float64 `json:"median_total_pr_lifetime_hours"`
+	MedianTimeToApprovalHours  float64 `json:"median_time_to_approval_hours"`
+}
+
+// Flaky check retries of the PRs merged in one week, the CI time spent on failures that were not
+// caused by the changes
+type CIFlakiness struct {
+	Period            string `json:"period"`
+	PRCount           int    `json:"pr_count"`
+	FlakyPRCount      int    `json:"flaky_pr_count"`
+	FlakyCheckRetries int    `json:"flaky_check_retries"`
+}
+
+// Assembles the report from PR and weekly aggregated metrics
+func NewReport(repository string, startDate, endDate time.Time, prMetrics []*api.PRMetrics, weeklyMetrics []*api.AggregatedMetrics) *Report {
+	report := &Report{
+		Repository:  repository,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		GeneratedAt: time.Now(),
+		PRCount:     len(prMetrics),
+	}
+
+	for _, pr := range prMetrics {
+		if !pr.MergedAt.IsZero() {
+			report.MergedCount++
+		}
+	}
+
+	for _, week := range weeklyMetrics {
+		report.PhaseBreakdown = append(report.PhaseBreakdown, PhaseBreakdown{
+			Period:                week.Period,
+			StartDate:             week.StartDate,
+			PRCount:               week.PRCount,
+			CodingHours:           week.AvgCodingHours,
+			WaitingForReviewHours: week.AvgWaitingForReviewHours,
+			InReviewHours:         week.AvgInReviewHours,
+			WaitingToMergeHours:   week.AvgWaitingToMergeHours,
+		})
+		report.WeeklyTrends = append(report.WeeklyTrends, WeeklyTrend{
+			Period:                     week.Period,
+			PRCount:                    week.PRCount,
+			MedianTotalPRLifetimeHours: week.MedianTotalPRLifetimeHours,
+			MedianTimeToApprovalHours:  week.MedianTimeToApprovalHours,
+		})
+	}
+
+	// Weeks without flakiness are listed too once any week has some, so the trend reads right
+	flaky := slices.ContainsFunc(weeklyMetrics, func(week *api.AggregatedMetrics) bool {
+		return week.FlakyCheckRetries > 0
+	})
+	if flaky {
+		for _, week := range weeklyMetrics {
+			report.CIFlakiness = append(report.CIFlakiness, CIFlakiness{
+				Period:            week.Period,
+				PRCount:           week.PRCount,
+				FlakyPRCount:      week.FlakyPRCount,
+				FlakyCheckRetries: week.FlakyCheckRetries,
+			})
+		}
+	}
+
+	return report
+}
+
+// Handles rendering the report as HTML or JSON
+type ReportWriter struct {
+	logger *utils.Logger
+}
+
+// Initializes report writer with logger dependency
+func NewReportWriter(logger *utils.Logger) *ReportWriter {
+	return &ReportWriter{
+		logger: logger,
+	}
+}
+
+// Writes report.<format> to the directory for each requested format
+func (w *ReportWriter) WriteToDirectory(namer *FileNamer, formats []string, report *Report) error {
+	for _, format := range formats {
+		filename := namer.Path("report." + format)
+		w.logger.Info("Writing %s report: %s", format, filename)
+
+		var err error
+		switch format {
+		case ReportFormatHTML:
+			err = w.writeHTML(filename, report)
+		case ReportFormatJSON:
+			err = w.writeJSON(filename, report)
+		case ReportFormatMarkdown:
+			err = w.writeMarkdown(filename, report)
+		default:
+			err = fmt.Errorf("unsupported report format: %s", format)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Exports the report as indented JSON
+func (w *ReportWriter) writeJSON(filename string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// Renders the report as a self-contained HTML page
+func (w *ReportWriter) writeHTML(filename string, report *Report) error {
+	return w.writeRendered(filename, report.RenderHTML)
+}
+
+// Renders the report as Markdown
+func (w *ReportWriter) writeMarkdown(filename string, report *Report) error {
+	return w.writeRendered(filename, report.RenderMarkdown)
+}
+
+// Creates the file and renders the report into it
+func (w *ReportWriter) writeRendered(filename string, render func(io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	return render(file)
+}
+
+// Renders the report as a self-contained HTML page
+func (r *Report) RenderHTML(out io.Writer) error {
+	// The templates are cloned, since an executed HTML template cannot take other functions
+	tmpl, err := reportTemplate.Clone()
+	if err != nil {
+		return err
+	}
+	return tmpl.Funcs(r.Locale.templateFuncs()).Execute(out, r)
+}
+
+// Renders the report as Markdown, for chat messages and plain-text email
+func (r *Report) RenderMarkdown(out io.Writer) error {
+	tmpl, err := markdownReportTemplate.Clone()
+	if err != nil {
+		return err
+	}
+	return tmpl.Funcs(r.Locale.templateFuncs()).Execute(out, r)
+}
+
+// Scales phase hours to bar widths relative to the longest week
+func maxPhaseTotal(breakdown []PhaseBreakdown) float64 {
+	maxTotal := 0.0
+	for _, week := range breakdown {
+		maxTotal = max(maxTotal, week.TotalHours())
+	}
+	return maxTotal
+}
+
+// Formatting shared by the HTML and Markdown templates
+var (
+	formatReportDate    = func(t time.Time) string { return t.Format("2006-01-02") }
+	formatReportHours   = func(f float64) string { return fmt.Sprintf("%.1f", f) }
+	formatReportPercent = func(f float64) string { return fmt.Sprintf("%.0f%%", f) }
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":    formatReportDate,
+	"hours":   formatReportHours,
+	"percent": formatReportPercent,
+	"width": func(hours, maxTotal float64) string {
+		if maxTotal <= 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.2f", hours/maxTotal*100)
+	},
+	"maxTotal": maxPhaseTotal,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>PR Metrics: {{.Repository}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-top: 1em; }
+th, td { padding: 0.3em 0.8em; text-align: right; border-bottom: 1px solid #ddd; }
+th:first-child, td:first-child { text-align: left; }
+.bar { display: flex; height: 1.2em; min-width: 20em; }
+.bar span { display: block; height: 100%; }
+.coding { background: #4e79a7; }
+.waiting-for-review { background: #f28e2b; }
+.in-review { background: #59a14f; }
+.waiting-to-merge { background: #e15759; }
+.legend span { display: inline-block; width: 1em; height: 1em; margin: 0 0.3em 0 1em; vertical-align: middle; }
+</style>
+</head>
+<body>
+<h1>PR Metrics: {{.Repository}}</h1>
+<p>{{date .StartDate}} to {{date .EndDate}} &middot; {{.PRCount}} PRs, {{.MergedCount}} merged &middot; generated {{date .GeneratedAt}}</p>
+{{if .Alerts}}
+<h2>Alerts</h2>
+<ul class="alerts">
+{{range .Alerts}}<li>{{.Message}}</li>
+{{end}}</ul>
+{{end}}
+<h2>Time in Each Phase per Week</h2>
+<p>Average hours per PR spent in each lifecycle phase, among PRs that went through the phase.</p>
+<p class="legend"><span class="coding"></span>Coding<span class="waiting-for-review"></span>Waiting for review<span class="in-review"></span>In review<span class="waiting-to-merge"></span>Waiting to merge</p>
+{{$max := maxTotal .PhaseBreakdown}}
+<table>
+<tr><th>Week</th><th>PRs</th><th>Coding</th><th>Waiting for Review</th><th>In Review</th><th>Waiting to Merge</th><th></th></tr>
+{{range .PhaseBreakdown}}<tr>
+<td>{{.Period}}</td><td>{{.PRCount}}</td><td>{{hours .CodingHours}}</td><td>{{hours .WaitingForReviewHours}}</td><td>{{hours .InReviewHours}}</td><td>{{hours .WaitingToMergeHours}}</td>
+<td><div class="bar"><span class="coding" style="width: {{width .CodingHours $max}}%"></span><span class="waiting-for-review" style="width: {{width .WaitingForReviewHours $max}}%"></span><span class="in-review" style="width: {{width .InReviewHours $max}}%"></span><span class="waiting-to-merge" style="width: {{width .WaitingToMergeHours $max}}%"></span></div></td>
+</tr>
+{{end}}</table>
+{{if .CIFlakiness}}
+<h2>Flaky CI Checks</h2>
+<p>Failed check runs that passed when re-run on the same commit, by week merged.</p>
+<table>
+<tr><th>Week</th><th>PRs</th><th>PRs with Flaky Checks</th><th>Flaky Retries</th></tr>
+{{range .CIFlakiness}}<tr><td>{{.Period}}</td><td>{{.PRCount}}</td><td>{{.FlakyPRCount}}</td><td>{{.FlakyCheckRetries}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .WorkingAgreements}}
+<h2>Working Agreements</h2>
+<p>Share of merged PRs keeping each agreement, by week and month merged.</p>
+<table>
+<tr><th>Week</th><th>PRs</th>{{range .Agreements}}<th>{{.}}</th>{{end}}</tr>
+{{range .Weeks}}<tr><td>{{.Period}}</td><td>{{.PRCount}}</td>{{range .PassPercent}}<td>{{percent .}}</td>{{end}}</tr>
+{{end}}<tr><th>Overall</th><th>{{.PRCount}}</th>{{range .Overall}}<th>{{percent .}}</th>{{end}}</tr>
+</table>
+<table>
+<tr><th>Month</th><th>PRs</th>{{range .Agreements}}<th>{{.}}</th>{{end}}</tr>
+{{range .Months}}<tr><td>{{.Period}}</td><td>{{.PRCount}}</td>{{range .PassPercent}}<td>{{percent .}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+var markdownReportTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(texttemplate.FuncMap{
+	"date":       formatReportDate,
+	"hours":      formatReportHours,
+	"percent":    formatReportPercent,
+	"trendChart": mermaidTrendCharts,
+	"phaseGantt": mermaidPhaseGantt,
+}).Parse(`# PR Metrics: {{.Repository}}
+
+{{date .StartDate}} to {{date .EndDate}} · {{.PRCount}} PRs, {{.MergedCount}} merged · generated {{date .GeneratedAt}}
+{{if .Alerts}}
+## Alerts
+
+{{range .Alerts}}- {{.Message}}
+{{end}}{{end}}
+## Time in Each Phase per Week
+
+Average hours per PR spent in each lifecycle phase, among PRs that went through the phase.
+
+| Week | PRs | Coding | Waiting for Review | In Review | Waiting to Merge |
+|------|----:|-------:|-------------------:|----------:|-----------------:|
+{{range .PhaseBreakdown}}| {{.Period}} | {{.PRCount}} | {{hours .CodingHours}} | {{hours .WaitingForReviewHours}} | {{hours .InReviewHours}} | {{hours .WaitingToMergeHours}} |
+{{end}}{{if .CIFlakiness}}
+## Flaky CI Checks
+
+Failed check runs that passed when re-run on the same commit, by week merged.
+
+| Week | PRs | PRs with Flaky Checks | Flaky Retries |
+|------|----:|----------------------:|--------------:|
+{{range .CIFlakiness}}| {{.Period}} | {{.PRCount}} | {{.FlakyPRCount}} | {{.FlakyCheckRetries}} |
+{{end}}{{end}}{{with .WorkingAgreements}}
+## Working Agreements
+
+Share of merged PRs keeping each agreement, by week and month merged.
+
+| Week | PRs |{{range .Agreements}} {{.}} |{{end}}
+|------|----:|{{range .Agreements}}---:|{{end}}
+{{range .Weeks}}| {{.Period}} | {{.PRCount}} |{{range .PassPercent}} {{percent .}} |{{end}}
+{{end}}| **Overall** | **{{.PRCount}}** |{{range .Overall}} **{{percent .}}** |{{end}}
+
+| Month | PRs |{{range .Agreements}} {{.}} |{{end}}
+|-------|----:|{{range .Agreements}}---:|{{end}}
+{{range .Months}}| {{.Period}} | {{.PRCount}} |{{range .PassPercent}} {{percent .}} |{{end}}
+{{end}}{{end}}{{if .WeeklyTrends}}
+{{phaseGantt .PhaseBreakdown}}
+## Weekly Trends
+
+{{trendChart .WeeklyTrends}}{{end}}`))
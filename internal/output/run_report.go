@@ -0,0 +1,38 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Handles exporting the machine-readable summary of a run
+type RunReportWriter struct {
+	logger *utils.Logger
+}
+
+// Initializes run report writer with logger dependency
+func NewRunReportWriter(logger *utils.Logger) *RunReportWriter {
+	return &RunReportWriter{
+		logger: logger,
+	}
+}
+
+// Exports the run report as indented JSON, creating the directory if needed
+func (w *RunReportWriter) Write(filename string, report *api.RunReport) error {
+	w.logger.Debug("Writing run report to %s", filename)
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,170 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Differences between the PR metrics of two runs
+type SnapshotDiff struct {
+	OldPRCount int              `json:"old_pr_count"`
+	NewPRCount int              `json:"new_pr_count"`
+	Added      []int            `json:"added"`   // PR numbers only in the new run
+	Removed    []int            `json:"removed"` // PR numbers only in the old run
+	Changed    []*PRChange      `json:"changed"`
+	Aggregates []AggregateDelta `json:"aggregates"` // Aggregated metrics over all merged PRs that moved
+}
+
+// Columns of pr_metrics.csv whose values differ for a PR present in both runs
+type PRChange struct {
+	Number  int            `json:"number"`
+	Columns []ColumnChange `json:"columns"`
+}
+
+// Old and new value of a column, as written to the CSV
+type ColumnChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// Change of an aggregated metric over all merged PRs
+type AggregateDelta struct {
+	Metric string  `json:"metric"`
+	Old    float64 `json:"old"`
+	New    float64 `json:"new"`
+	Delta  float64 `json:"delta"`
+}
+
+// Reports whether the runs produced the same metrics
+func (d *SnapshotDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Aggregates) == 0
+}
+
+// Compares the PR metrics of two runs column by column, and the aggregated metrics
+// over all their merged PRs, so a re-run or a changed metric definition can be checked
+func DiffSnapshots(oldPRs, newPRs []*api.PRMetrics, oldSummary, newSummary *api.AggregatedMetrics) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		OldPRCount: len(oldPRs),
+		NewPRCount: len(newPRs),
+		Added:      []int{},
+		Removed:    []int{},
+		Changed:    []*PRChange{},
+		Aggregates: []AggregateDelta{},
+	}
+
+	// Compare the values as written, so differences below the CSV precision are ignored
+	tables := &CSVWriter{}
+	header, oldRows := tables.prMetricsTable(oldPRs)
+	_, newRows := tables.prMetricsTable(newPRs)
+	oldByNumber := make(map[int][]string, len(oldPRs))
+	for i, pr := range oldPRs {
+		oldByNumber[pr.Number] = oldRows[i]
+	}
+	newByNumber := make(map[int][]string, len(newPRs))
+	for i, pr := range newPRs {
+		newByNumber[pr.Number] = newRows[i]
+	}
+
+	for number, newRow := range newByNumber {
+		oldRow, exists := oldByNumber[number]
+		if !exists {
+			diff.Added = append(diff.Added, number)
+			continue
+		}
+
+		change := &PRChange{Number: number}
+		for i, column := range header {
+			if oldRow[i] != newRow[i] {
+				change.Columns = append(change.Columns, ColumnChange{Column: column, Old: oldRow[i], New: newRow[i]})
+			}
+		}
+		if len(change.Columns) > 0 {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for number := range oldByNumber {
+		if _, exists := newByNumber[number]; !exists {
+			diff.Removed = append(diff.Removed, number)
+		}
+	}
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Number < diff.Changed[j].Number
+	})
+
+	for _, column := range AggregatedColumns() {
+		oldValue, ok := AggregatedMetricValue(oldSummary, column)
+		if !ok {
+			continue
+		}
+		newValue, _ := AggregatedMetricValue(newSummary, column)
+		if oldValue != newValue {
+			diff.Aggregates = append(diff.Aggregates, AggregateDelta{
+				Metric: column,
+				Old:    oldValue,
+				New:    newValue,
+				Delta:  newValue - oldValue,
+			})
+		}
+	}
+
+	return diff
+}
+
+// Prints the differences for people, one PR column or aggregated metric per line
+func (d *SnapshotDiff) WriteText(out io.Writer) error {
+	var err error
+	printf := func(format string, v ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(out, format, v...)
+		}
+	}
+
+	printf("PRs: %d -> %d (%d added, %d removed, %d changed)\n", d.OldPRCount, d.NewPRCount, len(d.Added), len(d.Removed), len(d.Changed))
+	if d.IsEmpty() {
+		printf("No differences\n")
+		return err
+	}
+
+	if len(d.Added) > 0 {
+		printf("\nAdded PRs: %s\n", formatPRNumbers(d.Added))
+	}
+	if len(d.Removed) > 0 {
+		printf("\nRemoved PRs: %s\n", formatPRNumbers(d.Removed))
+	}
+	if len(d.Changed) > 0 {
+		printf("\nChanged PRs:\n")
+		for _, change := range d.Changed {
+			printf("  #%d\n", change.Number)
+			for _, column := range change.Columns {
+				printf("    %s: %q -> %q\n", column.Column, column.Old, column.New)
+			}
+		}
+	}
+	if len(d.Aggregates) > 0 {
+		printf("\nAggregated metrics over all merged PRs:\n")
+		for _, delta := range d.Aggregates {
+			printf("  %s: %s -> %s (%+.2f)\n", delta.Metric, formatFloat(delta.Old), formatFloat(delta.New), delta.Delta)
+		}
+	}
+	return err
+}
+
+// Lists PR numbers as #1, #2, ...
+func formatPRNumbers(numbers []int) string {
+	formatted := ""
+	for i, number := range numbers {
+		if i > 0 {
+			formatted += ", "
+		}
+		formatted += "#" + strconv.Itoa(number)
+	}
+	return formatted
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+// Team of logins not listed under any team in the config
+const TeamUnassigned = "(unassigned)"
+
+// Logins of each team member, keyed by team name
+type Teams map[string][]string
+
+// Validates that every team is named and no login belongs to two teams
+func (t Teams) Validate() error {
+	teams := make(map[string]string)
+	for team, members := range t {
+		if team == "" {
+			return fmt.Errorf("team name is required")
+		}
+		for _, login := range members {
+			if other, exists := teams[strings.ToLower(login)]; exists && other != team {
+				return fmt.Errorf("login %s belongs to both team %s and team %s", login, other, team)
+			}
+			teams[strings.ToLower(login)] = team
+		}
+	}
+	return nil
+}
+
+// Returns the teams with every login replaced by its pseudonym, to match anonymized metrics
+func (t Teams) Pseudonymized(anonymizer *Anonymizer) Teams {
+	pseudonymized := make(Teams, len(t))
+	for team, members := range t {
+		for _, login := range members {
+			pseudonymized[team] = append(pseudonymized[team], anonymizer.Pseudonym(login))
+		}
+	}
+	return pseudonymized
+}
+
+// Maps lowercase logins to their team, since logins are case-insensitive
+func (t Teams) byLogin() map[string]string {
+	teams := make(map[string]string)
+	for team, members := range t {
+		for _, login := range members {
+			teams[strings.ToLower(login)] = team
+		}
+	}
+	return teams
+}
+
+// How quickly members of one team reviewed the PRs of another
+type TeamReviewLatency struct {
+	AuthorTeam   string
+	ReviewerTeam string
+	ReviewCount  int     // PRs the reviewer team's members reviewed, once per reviewer
+	MedianHours  float64 // From PR creation to each reviewer's first review
+	reviewHours  []float64
+}
+
+// Computes the median review latency for each pair of author team and reviewer team
+func BuildTeamReviewLatency(prMetrics []*api.PRMetrics, teams Teams) []*TeamReviewLatency {
+	byLogin := teams.byLogin()
+	teamOf := func(login string) string {
+		if team, exists := byLogin[strings.ToLower(login)]; exists {
+			return team
+		}
+		return TeamUnassigned
+	}
+
+	cells := make(map[[2]string]*TeamReviewLatency)
+	for _, pr := range prMetrics {
+		authorTeam := teamOf(pr.Author)
+		for _, reviewer := range pr.Reviewers {
+			key := [2]string{authorTeam, teamOf(reviewer.Reviewer)}
+			cell, exists := cells[key]
+			if !exists {
+				cell = &TeamReviewLatency{AuthorTeam: key[0], ReviewerTeam: key[1]}
+				cells[key] = cell
+			}
+			cell.reviewHours = append(cell.reviewHours, reviewer.HoursToFirstReview)
+		}
+	}
+
+	result := make([]*TeamReviewLatency, 0, len(cells))
+	for _, cell := range cells {
+		cell.ReviewCount = len(cell.reviewHours)
+		cell.MedianHours = median(cell.reviewHours)
+		result = append(result, cell)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AuthorTeam != result[j].AuthorTeam {
+			return teamLess(result[i].AuthorTeam, result[j].AuthorTeam)
+		}
+		return teamLess(result[i].ReviewerTeam, result[j].ReviewerTeam)
+	})
+	return result
+}
+
+// Orders teams by name, with unassigned logins last
+func teamLess(a, b string) bool {
+	if (a == TeamUnassigned) != (b == TeamUnassigned) {
+		return b == TeamUnassigned
+	}
+	return a < b
+}
+
+// Exports the review latency as a matrix with a row per author team and a column per reviewer
+// team. Cells hold the median hours to first review, left empty where no reviews happened.
+func (w *CSVWriter) WriteTeamReviewLatencyCSV(filename string, cells []*TeamReviewLatency) error {
+	w.logger.Info("Writing review latency of %d team pairs to CSV file: %s", len(cells), filename)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	writer := w.newWriter(file)
+	defer writer.Flush()
+
+	var authorTeams, reviewerTeams []string
+	medians := make(map[[2]string]float64)
+	for _, cell := range cells {
+		if !slices.Contains(authorTeams, cell.AuthorTeam) {
+			authorTeams = append(authorTeams, cell.AuthorTeam)
+		}
+		if !slices.Contains(reviewerTeams, cell.ReviewerTeam) {
+			reviewerTeams = append(reviewerTeams, cell.ReviewerTeam)
+		}
+		medians[[2]string{cell.AuthorTeam, cell.ReviewerTeam}] = cell.MedianHours
+	}
+	sort.Slice(reviewerTeams, func(i, j int) bool {
+		return teamLess(reviewerTeams[i], reviewerTeams[j])
+	})
+
+	// Write header
+	header := append([]string{"Author Team"}, reviewerTeams...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Write data
+	for _, authorTeam := range authorTeams {
+		row := []string{authorTeam}
+		for _, reviewerTeam := range reviewerTeams {
+			if hours, exists := medians[[2]string{authorTeam, reviewerTeam}]; exists {
+				row = append(row, w.formatFloat(hours))
+			} else {
+				row = append(row, "")
+			}
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.logger.Info("Successfully wrote review latency of %d author teams to CSV file", len(authorTeams))
+	return nil
+}
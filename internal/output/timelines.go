@@ -0,0 +1,267 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"image/color"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Supported timeline export formats
+const (
+	TimelineFormatJSON = "json"
+	TimelineFormatSVG  = "svg"
+)
+
+// Layout of the timeline SVG in pixels
+const (
+	timelineWidth       = 1000
+	timelineLabelWidth  = 280 // Column of PR numbers and titles left of the bars
+	timelineMarginRight = 20
+	timelineMarginTop   = 60
+	timelineRowHeight   = 30
+	timelineLegendSpace = 50
+	timelineTitleLength = 36 // Characters of the title shown before truncating
+)
+
+// Lifecycle phases of a timeline, in order
+var timelinePhases = []string{"coding", "waiting for review", "in review", "waiting to merge"}
+
+// Colors of event markers, by event type
+var timelineEventColors = map[string]color.RGBA{
+	api.EventTypeCreated:  {0x22, 0x22, 0x22, 0xff},
+	api.EventTypeCommit:   {0x76, 0x76, 0x76, 0xff},
+	api.EventTypeComment:  {0x1f, 0x6f, 0xeb, 0xff},
+	api.EventTypeReview:   {0xbf, 0x87, 0x00, 0xff},
+	api.EventTypeApproval: {0x1a, 0x7f, 0x37, 0xff},
+	api.EventTypeMerge:    {0x82, 0x50, 0xdf, 0xff},
+	api.EventTypeClose:    {0xcf, 0x22, 0x2e, 0xff},
+	api.EventTypeReopen:   {0xcf, 0x22, 0x2e, 0xff},
+}
+
+// The lifecycle of one PR laid out in time, for finding where it stalled
+type Timeline struct {
+	PRNumber             int             `json:"pr_number"`
+	Title                string          `json:"title"`
+	Author               string          `json:"author"`
+	State                string          `json:"state"`
+	TotalPRLifetimeHours float64         `json:"total_pr_lifetime_hours"`
+	Start                time.Time       `json:"start"` // First event, usually the first commit
+	End                  time.Time       `json:"end"`   // Last event
+	Phases               []TimelinePhase `json:"phases"`
+	Events               []api.PREvent   `json:"events"`
+}
+
+// A span of a PR's lifecycle, drawn as a bar of the Gantt chart
+type TimelinePhase struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Returns the PRs to export timelines for: those with the given numbers if any, otherwise the
+// count PRs with the longest lifetime
+func SelectTimelinePRs(prMetrics []*api.PRMetrics, numbers []int, count int) []*api.PRMetrics {
+	if len(numbers) > 0 {
+		byNumber := make(map[int]*api.PRMetrics, len(prMetrics))
+		for _, pr := range prMetrics {
+			byNumber[pr.Number] = pr
+		}
+		var selected []*api.PRMetrics
+		for _, number := range numbers {
+			if pr, ok := byNumber[number]; ok {
+				selected = append(selected, pr)
+			}
+		}
+		return selected
+	}
+
+	slowest := make([]*api.PRMetrics, len(prMetrics))
+	copy(slowest, prMetrics)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].TotalPRLifetimeHours > slowest[j].TotalPRLifetimeHours
+	})
+	return slowest[:min(count, len(slowest))]
+}
+
+// Lays out the events of a PR as a timeline. The phases split the lifecycle at the first commit,
+// creation, first review, first approval, and merge, as the phase hours of pr_metrics.csv do.
+func NewTimeline(pr *api.PRMetrics) Timeline {
+	timeline := Timeline{
+		PRNumber:             pr.Number,
+		Title:                pr.Title,
+		Author:               pr.Author,
+		State:                pr.State,
+		TotalPRLifetimeHours: pr.TotalPRLifetimeHours,
+		Phases:               []TimelinePhase{},
+		Events:               make([]api.PREvent, len(pr.Events)),
+	}
+	copy(timeline.Events, pr.Events)
+	sort.SliceStable(timeline.Events, func(i, j int) bool {
+		return timeline.Events[i].Timestamp.Before(timeline.Events[j].Timestamp)
+	})
+
+	var firstCommitAt, firstReviewAt, firstApprovalAt time.Time
+	for _, event := range timeline.Events {
+		switch event.Type {
+		case api.EventTypeCommit:
+			if firstCommitAt.IsZero() {
+				firstCommitAt = event.Timestamp
+			}
+		case api.EventTypeReview, api.EventTypeApproval:
+			if firstReviewAt.IsZero() {
+				firstReviewAt = event.Timestamp
+			}
+			if event.Type == api.EventTypeApproval && firstApprovalAt.IsZero() {
+				firstApprovalAt = event.Timestamp
+			}
+		}
+	}
+	if len(timeline.Events) > 0 {
+		timeline.Start = timeline.Events[0].Timestamp
+		timeline.End = timeline.Events[len(timeline.Events)-1].Timestamp
+	} else {
+		timeline.Start, timeline.End = pr.CreatedAt, pr.CreatedAt
+	}
+
+	addPhase := func(name string, start, end time.Time) {
+		if !start.IsZero() && !end.IsZero() && end.After(start) {
+			timeline.Phases = append(timeline.Phases, TimelinePhase{Name: name, Start: start, End: end})
+		}
+	}
+	// Commits made after opening the PR are not part of the coding phase
+	if firstCommitAt.Before(pr.CreatedAt) {
+		addPhase(timelinePhases[0], firstCommitAt, pr.CreatedAt)
+	}
+	addPhase(timelinePhases[1], pr.CreatedAt, firstReviewAt)
+	addPhase(timelinePhases[2], firstReviewAt, firstApprovalAt)
+	addPhase(timelinePhases[3], firstApprovalAt, pr.MergedAt)
+
+	return timeline
+}
+
+// Handles exporting per-PR timelines as JSON or a Gantt-style SVG
+type TimelineWriter struct {
+	logger *utils.Logger
+}
+
+// Initializes timeline writer with logger dependency
+func NewTimelineWriter(logger *utils.Logger) *TimelineWriter {
+	return &TimelineWriter{
+		logger: logger,
+	}
+}
+
+// Writes timelines.<format> to the directory for each requested format
+func (w *TimelineWriter) WriteToDirectory(namer *FileNamer, formats []string, prMetrics []*api.PRMetrics) error {
+	timelines := make([]Timeline, len(prMetrics))
+	for i, pr := range prMetrics {
+		timelines[i] = NewTimeline(pr)
+	}
+
+	for _, format := range formats {
+		filename := namer.Path("timelines." + format)
+		w.logger.Info("Writing timelines of %d PRs: %s", len(timelines), filename)
+
+		var data []byte
+		switch format {
+		case TimelineFormatJSON:
+			var err error
+			if data, err = json.MarshalIndent(timelines, "", "  "); err != nil {
+				return err
+			}
+		case TimelineFormatSVG:
+			data = renderTimelinesSVG(timelines)
+		default:
+			return fmt.Errorf("unsupported timeline format: %s", format)
+		}
+		if err := os.WriteFile(filename, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Draws one row per PR on a shared axis of hours since each PR's first event, so the rows
+// compare how long each phase took
+func renderTimelinesSVG(timelines []Timeline) []byte {
+	longest := 0.0
+	for _, timeline := range timelines {
+		longest = max(longest, timeline.End.Sub(timeline.Start).Hours())
+	}
+	top, step := niceScale(longest)
+
+	plotLeft := float64(timelineLabelWidth)
+	plotWidth := float64(timelineWidth - timelineLabelWidth - timelineMarginRight)
+	x := func(timeline Timeline, t time.Time) float64 {
+		return plotLeft + t.Sub(timeline.Start).Hours()/top*plotWidth
+	}
+	plotBottom := timelineMarginTop + len(timelines)*timelineRowHeight
+	height := plotBottom + timelineLegendSpace
+	hex := func(rgba color.RGBA) string {
+		return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", timelineWidth, height, timelineWidth, height)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(chartBackground))
+	fmt.Fprintf(&svg, `<text x="10" y="24" font-size="16" fill="%s">PR Timelines (Hours Since First Event)</text>`+"\n", hex(chartText))
+
+	// Gridlines and hour labels
+	for hours := 0.0; hours <= top+step/2; hours += step {
+		gridX := plotLeft + hours/top*plotWidth
+		fmt.Fprintf(&svg, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s"/>`+"\n", gridX, timelineMarginTop-6, gridX, plotBottom, hex(chartGrid))
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%d" text-anchor="middle" fill="%s">%s</text>`+"\n", gridX, timelineMarginTop-12, hex(chartText), formatTick(hours))
+	}
+
+	for row, timeline := range timelines {
+		rowTop := timelineMarginTop + row*timelineRowHeight
+		label := fmt.Sprintf("#%d %s", timeline.PRNumber, timeline.Title)
+		if utf8.RuneCountInString(label) > timelineTitleLength {
+			label = string([]rune(label)[:timelineTitleLength-1]) + "…"
+		}
+		fmt.Fprintf(&svg, `<text x="10" y="%d" fill="%s">%s<title>%s by %s, %s hours</title></text>`+"\n",
+			rowTop+19, hex(chartText), html.EscapeString(label), html.EscapeString(timeline.Title), html.EscapeString(timeline.Author), formatFloat(timeline.TotalPRLifetimeHours))
+
+		for _, phase := range timeline.Phases {
+			index := max(slices.Index(timelinePhases, phase.Name), 0)
+			left := x(timeline, phase.Start)
+			fmt.Fprintf(&svg, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" fill-opacity="0.6"><title>%s: %s hours</title></rect>`+"\n",
+				left, rowTop+6, x(timeline, phase.End)-left, timelineRowHeight-12, hex(chartColors[index%len(chartColors)]), phase.Name, formatFloat(phase.End.Sub(phase.Start).Hours()))
+		}
+
+		for _, event := range timeline.Events {
+			detail := event.Actor
+			if event.Detail != "" {
+				detail += " " + event.Detail
+			}
+			fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%d" r="4" fill="%s" stroke="#ffffff"><title>%s %s %s</title></circle>`+"\n",
+				x(timeline, event.Timestamp), rowTop+timelineRowHeight/2, hex(timelineEventColors[event.Type]), event.Timestamp.Format(time.RFC3339), event.Type, html.EscapeString(detail))
+		}
+	}
+
+	// Legend of phases, then of event markers
+	legendX := 10
+	for i, name := range timelinePhases {
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="12" height="12" fill="%s" fill-opacity="0.6"/>`+"\n", legendX, plotBottom+16, hex(chartColors[i%len(chartColors)]))
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", legendX+16, plotBottom+26, hex(chartText), name)
+		legendX += 30 + 7*len(name)
+	}
+	for _, eventType := range []string{api.EventTypeCommit, api.EventTypeComment, api.EventTypeReview, api.EventTypeApproval, api.EventTypeMerge} {
+		fmt.Fprintf(&svg, `<circle cx="%d" cy="%d" r="4" fill="%s"/>`+"\n", legendX+6, plotBottom+22, hex(timelineEventColors[eventType]))
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", legendX+16, plotBottom+26, hex(chartText), eventType)
+		legendX += 30 + 7*len(eventType)
+	}
+
+	svg.WriteString("</svg>\n")
+	return []byte(svg.String())
+}
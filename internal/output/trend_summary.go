@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+)
+
+// Weeks before the current one averaged in the trend summary
+const trendBaselineWeeks = 4
+
+// Metrics compared in the trend summary, the ones most often asked about
+var trendMetrics = []struct {
+	name  string
+	value func(week *api.AggregatedMetrics) float64
+	count bool // Counts average over every week; the others only over weeks with merged PRs
+}{
+	{"Merged PRs", func(week *api.AggregatedMetrics) float64 { return float64(week.PRCount) }, true},
+	{"Median First Commit to Merge (Hours)", func(week *api.AggregatedMetrics) float64 { return week.MedianFirstCommitToMergeHours }, false},
+	{"Median Created to First Comment (Hours)", func(week *api.AggregatedMetrics) float64 { return week.MedianCreatedToFirstCommentHours }, false},
+	{"Median Time to Approval (Hours)", func(week *api.AggregatedMetrics) float64 { return week.MedianTimeToApprovalHours }, false},
+	{"Median Total PR Lifetime (Hours)", func(week *api.AggregatedMetrics) float64 { return week.MedianTotalPRLifetimeHours }, false},
+	{"Unreviewed Merge (%)", func(week *api.AggregatedMetrics) float64 { return week.UnreviewedMergePercent }, false},
+}
+
+// Writes a table comparing the week of the reference time with the week before and with the
+// average of the four weeks before it. Weeks without merged PRs are shown as "-" except in the
+// counts, and the baseline averages whichever of its weeks had some.
+func WriteTrendSummary(out io.Writer, weeklyMetrics []*api.AggregatedMetrics, reference time.Time) error {
+	byPeriod := make(map[string]*api.AggregatedMetrics, len(weeklyMetrics))
+	for _, week := range weeklyMetrics {
+		byPeriod[week.Period] = week
+	}
+
+	// Weeks missing from the aggregates had no merged PRs
+	weeks := make([]*api.AggregatedMetrics, trendBaselineWeeks+1)
+	periods := make([]string, trendBaselineWeeks+1)
+	for i := range weeks {
+		period, startDate, endDate := metrics.CalendarPeriod(reference.AddDate(0, 0, -7*i), metrics.GranularityWeek)
+		periods[i] = period
+		weeks[i] = byPeriod[period]
+		if weeks[i] == nil {
+			weeks[i] = &api.AggregatedMetrics{Period: period, StartDate: startDate, EndDate: endDate}
+		}
+	}
+
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Metric\tThis Week (%s)\tLast Week (%s)\t%d-Week Avg (%s to %s)\n", periods[0], periods[1], trendBaselineWeeks, periods[trendBaselineWeeks], periods[1])
+	for _, metric := range trendMetrics {
+		weekValue := func(week *api.AggregatedMetrics) string {
+			if metric.count {
+				return fmt.Sprintf("%.0f", metric.value(week))
+			}
+			if week.PRCount == 0 {
+				return "-"
+			}
+			return formatReportHours(metric.value(week))
+		}
+
+		total, counted := 0.0, 0
+		for _, week := range weeks[1:] {
+			if metric.count || week.PRCount > 0 {
+				total += metric.value(week)
+				counted++
+			}
+		}
+		average := "-"
+		if counted > 0 {
+			average = formatReportHours(total / float64(counted))
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", metric.name, weekValue(weeks[0]), weekValue(weeks[1]), average)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,277 @@
+package output
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Cell styles defined in the workbook stylesheet
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleHeader
+	xlsxStyleInteger
+	xlsxStyleDecimal
+	xlsxStyleDateTime
+)
+
+// Handles exporting all metrics to a single Excel workbook
+type XLSXWriter struct {
+	logger *utils.Logger
+}
+
+// Initializes XLSX writer with logger dependency
+func NewXLSXWriter(logger *utils.Logger) *XLSXWriter {
+	return &XLSXWriter{
+		logger: logger,
+	}
+}
+
+// A worksheet with a header row and rows of CSV-formatted values
+type xlsxSheet struct {
+	name   string
+	header []string
+	rows   [][]string
+	// Set when a column mixes value types, so each cell is styled on its own
+	styleByCell bool
+}
+
+// Exports PR, weekly, monthly, and summary sheets to one workbook
+func (w *XLSXWriter) Write(filename string, prMetrics []*api.PRMetrics, weeklyMetrics, monthlyMetrics []*api.AggregatedMetrics, summary *api.AggregatedMetrics) error {
+	w.logger.Info("Writing metrics to XLSX file: %s", filename)
+
+	// Reuse the CSV tables with default formatting so values parse back into numbers
+	tables := NewCSVWriter(w.logger, CSVOptions{})
+
+	prHeader, prRows := tables.prMetricsTable(prMetrics)
+	weeklyHeader, weeklyRows := tables.aggregatedMetricsTable(weeklyMetrics)
+	monthlyHeader, monthlyRows := tables.aggregatedMetricsTable(monthlyMetrics)
+
+	// Show the summary as one metric per row
+	summaryHeader, summaryRows := tables.aggregatedMetricsTable([]*api.AggregatedMetrics{summary})
+	var summaryValues [][]string
+	for i, name := range summaryHeader {
+		summaryValues = append(summaryValues, []string{name, summaryRows[0][i]})
+	}
+
+	sheets := []xlsxSheet{
+		{name: "Summary", header: []string{"Metric", "Value"}, rows: summaryValues, styleByCell: true},
+		{name: "PR Metrics", header: prHeader, rows: prRows},
+		{name: "Weekly", header: weeklyHeader, rows: weeklyRows},
+		{name: "Monthly", header: monthlyHeader, rows: monthlyRows},
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			w.logger.Warn("Failed to close file: %v", err)
+		}
+	}()
+
+	// Parts in the order spreadsheet applications expect, content types first
+	parts := [][2]string{
+		{"[Content_Types].xml", xlsxContentTypes(len(sheets))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(sheets))},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range sheets {
+		parts = append(parts, [2]string{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxWorksheet(sheet)})
+	}
+
+	archive := zip.NewWriter(file)
+	for _, part := range parts {
+		entry, err := archive.Create(part[0])
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, part[1]); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	w.logger.Info("Successfully wrote %d sheets to XLSX file", len(sheets))
+	return nil
+}
+
+// Picks a number or date style for each column whose values all parse as such
+func xlsxColumnStyles(header []string, rows [][]string) []int {
+	styles := make([]int, len(header))
+	for column := range header {
+		isInteger, isDecimal, isDateTime, hasValue := true, true, true, false
+		for _, row := range rows {
+			value := row[column]
+			if value == "" {
+				continue
+			}
+			hasValue = true
+			if _, err := strconv.Atoi(value); err != nil {
+				isInteger = false
+			}
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				isDecimal = false
+			}
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				isDateTime = false
+			}
+		}
+
+		switch {
+		case !hasValue:
+			styles[column] = xlsxStyleDefault
+		case isInteger:
+			styles[column] = xlsxStyleInteger
+		case isDecimal:
+			styles[column] = xlsxStyleDecimal
+		case isDateTime:
+			styles[column] = xlsxStyleDateTime
+		default:
+			styles[column] = xlsxStyleDefault
+		}
+	}
+	return styles
+}
+
+// Returns the spreadsheet column name for a zero-based index (A, B, ..., Z, AA, ...)
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// Converts a time to an Excel serial date
+func xlsxSerialDate(t time.Time) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return t.UTC().Sub(epoch).Hours() / 24
+}
+
+// Writes a text cell as an inline string
+func writeXLSXText(b *strings.Builder, ref, value string, style int) {
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr" s="%d"><is><t xml:space="preserve">`, ref, style)
+	_ = xml.EscapeText(b, []byte(value))
+	b.WriteString(`</t></is></c>`)
+}
+
+// Renders a worksheet with a frozen header row
+func xlsxWorksheet(sheet xlsxSheet) string {
+	styles := xlsxColumnStyles(sheet.header, sheet.rows)
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<sheetData><row r="1">`)
+	for column, name := range sheet.header {
+		writeXLSXText(&b, xlsxColumnName(column)+"1", name, xlsxStyleHeader)
+	}
+	b.WriteString(`</row>`)
+
+	for i, row := range sheet.rows {
+		rowNumber := strconv.Itoa(i + 2)
+		fmt.Fprintf(&b, `<row r="%s">`, rowNumber)
+		for column, value := range row {
+			if value == "" {
+				continue
+			}
+			ref := xlsxColumnName(column) + rowNumber
+
+			style := styles[column]
+			if sheet.styleByCell {
+				style = xlsxColumnStyles([]string{""}, [][]string{{value}})[0]
+			}
+
+			switch style {
+			case xlsxStyleInteger, xlsxStyleDecimal:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, value)
+			case xlsxStyleDateTime:
+				t, _ := time.Parse(time.RFC3339, value)
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(xlsxSerialDate(t), 'f', -1, 64))
+			default:
+				writeXLSXText(&b, ref, value, xlsxStyleDefault)
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// Lists the sheets of the workbook
+func xlsxWorkbook(sheets []xlsxSheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, sheet.name, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+// Links the workbook to its sheets and stylesheet
+func xlsxWorkbookRels(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheetCount+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// Declares the content type of every part in the package
+func xlsxContentTypes(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// Styles in the order of the xlsxStyle constants: default, bold header, integer, two decimals, date and time
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="5">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="1" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs></styleSheet>`
@@ -0,0 +1,236 @@
+package selfupdate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+const (
+	// Latest release of this tool on GitHub
+	LatestReleaseURL = "https://api.github.com/repos/fukuchancat/github-pr-metrics/releases/latest"
+
+	binaryName      = "github-pr-metrics"
+	checksumsAsset  = "checksums.txt" // sha256sum output listing every asset
+	requestTimeout  = 5 * time.Minute
+	maxBinaryLength = 200 << 20
+)
+
+// A published release and its downloadable files
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// A file attached to a release
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// Checks for and installs releases of this tool
+type Updater struct {
+	releaseURL string
+	token      string
+	httpClient *http.Client
+	logger     *utils.Logger
+}
+
+// Initializes updater for the release URL, optionally authenticating to raise the rate limit
+func NewUpdater(releaseURL, token string, logger *utils.Logger) *Updater {
+	return &Updater{
+		releaseURL: releaseURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		logger:     logger,
+	}
+}
+
+// Returns the latest published release
+func (u *Updater) LatestRelease() (*Release, error) {
+	body, err := u.get(u.releaseURL, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+	defer u.close(body)
+
+	release := &Release{}
+	if err := json.NewDecoder(body).Decode(release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %v", err)
+	}
+	return release, nil
+}
+
+// Returns the name of the binary asset for a platform, such as github-pr-metrics_linux_amd64
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("%s_%s_%s", binaryName, goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Returns the asset with the name, or nil if the release has none
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Replaces the executable with the release's binary for the running platform, verifying it
+// against the release checksums. The new binary is written next to the executable and renamed
+// over it, so an interrupted update leaves the old binary in place.
+func (u *Updater) Install(release *Release, executable string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	asset := release.Asset(name)
+	if asset == nil {
+		return fmt.Errorf("release %s has no binary for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	checksums := release.Asset(checksumsAsset)
+	if checksums == nil {
+		return fmt.Errorf("release %s has no %s to verify the binary with", release.TagName, checksumsAsset)
+	}
+	expected, err := u.checksum(checksums, name)
+	if err != nil {
+		return err
+	}
+
+	u.logger.Info("Downloading %s", asset.BrowserDownloadURL)
+	body, err := u.get(asset.BrowserDownloadURL, "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	defer u.close(body)
+
+	temp, err := os.CreateTemp(filepath.Dir(executable), "."+binaryName+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file next to %s: %v", executable, err)
+	}
+	defer func() {
+		// Only left behind when the update failed
+		_ = os.Remove(temp.Name())
+	}()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(temp, hash), io.LimitReader(body, maxBinaryLength+1))
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %v", name, err)
+	}
+	if written > maxBinaryLength {
+		return fmt.Errorf("%s is larger than %d MB", name, maxBinaryLength>>20)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
+	}
+
+	if err := os.Chmod(temp.Name(), 0755); err != nil {
+		return err
+	}
+	// Windows cannot replace a running executable, but can rename it out of the way
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			return err
+		}
+	}
+	return os.Rename(temp.Name(), executable)
+}
+
+// Reads the SHA-256 of an asset from the release's checksums file
+func (u *Updater) checksum(checksums *Asset, name string) (string, error) {
+	body, err := u.get(checksums.BrowserDownloadURL, "text/plain")
+	if err != nil {
+		return "", err
+	}
+	defer u.close(body)
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sha256sum marks binary mode with an asterisk before the name
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s lists no checksum for %s", checksumsAsset, name)
+}
+
+// Sends a GET request, returning the body of a successful response
+func (u *Updater) get(url, accept string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if u.token != "" && strings.HasPrefix(url, "https://api.github.com/") {
+		req.Header.Set("Authorization", "Bearer "+u.token)
+	}
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		u.close(resp.Body)
+		return nil, &utils.APIError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+	return resp.Body, nil
+}
+
+// Closes a response body, logging a failure
+func (u *Updater) close(body io.Closer) {
+	if err := body.Close(); err != nil {
+		u.logger.Warn("Failed to close response body: %v", err)
+	}
+}
+
+// Reports whether the latest version is newer than the current one. Versions are compared by
+// their major, minor, and patch numbers, and a release is newer than its own pre-releases.
+func IsNewer(current, latest string) bool {
+	currentCore, currentPre := parseVersion(current)
+	latestCore, latestPre := parseVersion(latest)
+	for i := range currentCore {
+		if latestCore[i] != currentCore[i] {
+			return latestCore[i] > currentCore[i]
+		}
+	}
+	return currentPre && !latestPre
+}
+
+// Splits a version such as v1.2.3-rc.1 into its numbers and whether it is a pre-release
+func parseVersion(version string) ([3]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	core, _, isPre := strings.Cut(version, "-")
+	var numbers [3]int
+	for i, part := range strings.SplitN(core, ".", 3) {
+		numbers[i], _ = strconv.Atoi(part)
+	}
+	return numbers, isPre
+}
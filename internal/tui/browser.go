@@ -0,0 +1,521 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/output"
+)
+
+const (
+	defaultPageSize = 20
+	maxCellWidth    = 40
+	chromeHeight    = 4 // Lines around the PR list: the header, a blank line, the status, and a message
+)
+
+// Columns of the PR list until the user picks others
+var defaultColumns = []string{
+	"PR Number",
+	"Title",
+	"Author",
+	"State",
+	"Merged At",
+	"Created to First Comment (Hours)",
+	"Total PR Lifetime (Hours)",
+	"Review Count",
+	"Additions",
+	"Deletions",
+}
+
+const helpText = `Keys:
+  ↑/k, ↓/j         move between PRs
+  PgUp/p, PgDn/n   previous and next page
+  ←/h, →/l         pick a column
+  s                sort by the picked column, again to reverse
+  Enter            show the PR's metrics and timeline (Esc goes back)
+  /                show PRs whose title or author contains a text (empty clears)
+  c                show other columns, e.g. "pr number, title, total" (empty restores the defaults)
+  #                show a PR by number
+  ?                show or hide this help
+  q                quit`
+
+var (
+	headerStyle   = lipgloss.NewStyle().Bold(true)
+	pickedStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	statusStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+// What the keys act on
+type browserMode int
+
+const (
+	modeList   browserMode = iota // The PR list
+	modePrompt                    // A line of text for a filter, columns, or PR number
+	modeDetail                    // The metrics and timeline of one PR
+)
+
+// Commands that read a line of text
+const (
+	promptFilter  = "Filter: "
+	promptColumns = "Columns: "
+	promptNumber  = "PR number: "
+)
+
+// Browses the PR metrics of a run in the terminal
+type Browser struct {
+	prs         []*api.PRMetrics // All PRs, in the current sort order
+	events      map[int][]api.PREvent
+	columns     []string
+	column      int // Index of the picked column
+	sortBy      string
+	reverse     bool
+	filter      string
+	cursor      int // Index of the selected PR among the visible ones
+	offset      int // Index of the first visible PR on screen
+	height      int // Terminal height, zero until the terminal reports it
+	width       int
+	mode        browserMode
+	prompt      textinput.Model
+	detail      viewport.Model
+	message     string // Result of the last command
+	help        bool
+	in          io.Reader
+	out         io.Writer
+	interactive bool // Use the alternate screen, so the terminal is restored on quit
+}
+
+// Initializes browser over the PRs and their events, which may be empty
+func NewBrowser(prs []*api.PRMetrics, events []api.PREvent, in io.Reader, out io.Writer, interactive bool) *Browser {
+	eventsByPR := make(map[int][]api.PREvent)
+	for _, event := range events {
+		eventsByPR[event.PRNumber] = append(eventsByPR[event.PRNumber], event)
+	}
+	for _, prEvents := range eventsByPR {
+		sort.SliceStable(prEvents, func(i, j int) bool {
+			return prEvents[i].Timestamp.Before(prEvents[j].Timestamp)
+		})
+	}
+
+	return &Browser{
+		prs:         prs,
+		events:      eventsByPR,
+		columns:     defaultColumns,
+		prompt:      textinput.New(),
+		in:          in,
+		out:         out,
+		interactive: interactive,
+	}
+}
+
+// Shows the PR list and handles keys until the user quits or the input ends
+func (b *Browser) Run() error {
+	options := []tea.ProgramOption{tea.WithInput(b.in), tea.WithOutput(b.out)}
+	if b.interactive {
+		options = append(options, tea.WithAltScreen())
+	}
+	_, err := tea.NewProgram(b, options...).Run()
+	return err
+}
+
+// Starts without commands; the terminal reports its size on its own
+func (b *Browser) Init() tea.Cmd {
+	return nil
+}
+
+// Handles a key or a change of the terminal size
+func (b *Browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.width, b.height = msg.Width, msg.Height
+		b.detail.Width, b.detail.Height = msg.Width, max(msg.Height-1, 1)
+		b.scroll()
+		return b, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return b, tea.Quit
+		}
+		switch b.mode {
+		case modePrompt:
+			return b.updatePrompt(msg)
+		case modeDetail:
+			return b.updateDetail(msg)
+		}
+		return b.updateList(msg)
+	}
+	return b, nil
+}
+
+// Handles a key on the PR list
+func (b *Browser) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := b.visiblePRs()
+	b.message = ""
+	switch msg.String() {
+	case "q", "esc":
+		return b, tea.Quit
+	case "up", "k":
+		b.cursor--
+	case "down", "j":
+		b.cursor++
+	case "pgup", "p":
+		b.cursor -= b.pageSize()
+	case "pgdown", "n", " ":
+		b.cursor += b.pageSize()
+	case "home", "g":
+		b.cursor = 0
+	case "end", "G":
+		b.cursor = len(visible) - 1
+	case "left", "h":
+		b.column = max(b.column-1, 0)
+	case "right", "l":
+		b.column = min(b.column+1, len(b.columns)-1)
+	case "s":
+		b.sort(b.columns[b.column])
+	case "enter":
+		if len(visible) > 0 {
+			b.showPR(visible[b.cursor].Number)
+		}
+	case "/", "f":
+		return b, b.startPrompt(promptFilter)
+	case "c":
+		return b, b.startPrompt(promptColumns)
+	case "#":
+		return b, b.startPrompt(promptNumber)
+	case "?":
+		b.help = !b.help
+	}
+	b.scroll()
+	return b, nil
+}
+
+// Handles a key while a line of text is typed
+func (b *Browser) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		b.mode = modeList
+		b.prompt.Blur()
+		return b, nil
+	case tea.KeyEnter:
+		b.mode = modeList
+		b.prompt.Blur()
+		b.applyPrompt(b.prompt.Prompt, strings.TrimSpace(b.prompt.Value()))
+		b.scroll()
+		return b, nil
+	}
+	var cmd tea.Cmd
+	b.prompt, cmd = b.prompt.Update(msg)
+	return b, cmd
+}
+
+// Handles a key on the view of one PR
+func (b *Browser) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "backspace", "left", "h", "q":
+		b.mode = modeList
+		return b, nil
+	}
+	var cmd tea.Cmd
+	b.detail, cmd = b.detail.Update(msg)
+	return b, cmd
+}
+
+// Starts reading a line of text for a command
+func (b *Browser) startPrompt(prompt string) tea.Cmd {
+	b.mode = modePrompt
+	b.prompt.Prompt = prompt
+	b.prompt.SetValue("")
+	return b.prompt.Focus()
+}
+
+// Runs the command of a prompt with the text typed
+func (b *Browser) applyPrompt(prompt, value string) {
+	switch prompt {
+	case promptFilter:
+		b.filter = strings.ToLower(value)
+		b.cursor = 0
+	case promptColumns:
+		b.message = b.selectColumns(value)
+	case promptNumber:
+		number, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+		if err != nil {
+			b.message = fmt.Sprintf("Not a PR number: %q", value)
+			return
+		}
+		b.showPR(number)
+	}
+}
+
+// Returns how many PRs fit on screen
+func (b *Browser) pageSize() int {
+	if b.height == 0 {
+		return defaultPageSize
+	}
+	return max(b.height-chromeHeight, 1)
+}
+
+// Keeps the cursor on a visible PR and scrolls the list so it stays on screen
+func (b *Browser) scroll() {
+	visible := b.visiblePRs()
+	b.cursor = max(min(b.cursor, len(visible)-1), 0)
+	pageSize := b.pageSize()
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+pageSize {
+		b.offset = b.cursor - pageSize + 1
+	}
+	b.offset = max(min(b.offset, len(visible)-pageSize), 0)
+}
+
+// Draws the PR list, the view of a PR, or the help
+func (b *Browser) View() string {
+	if b.mode == modeDetail {
+		return b.detail.View() + "\n" + statusStyle.Render("↑/↓ scroll, Esc back")
+	}
+	if b.help {
+		return helpText + "\n\n" + statusStyle.Render("Press ? to go back.")
+	}
+
+	visible := b.visiblePRs()
+	end := min(b.offset+b.pageSize(), len(visible))
+
+	rows := [][]string{b.columns}
+	for _, pr := range visible[b.offset:end] {
+		row := make([]string, len(b.columns))
+		for i, column := range b.columns {
+			value, _ := output.PRMetricField(pr, column)
+			row[i] = truncate(formatCell(value))
+		}
+		rows = append(rows, row)
+	}
+
+	var view strings.Builder
+	for i, line := range b.formatTable(rows) {
+		switch {
+		case i == 0:
+			view.WriteString(line)
+		case b.offset+i-1 == b.cursor:
+			view.WriteString(selectedStyle.Render(line))
+		default:
+			view.WriteString(line)
+		}
+		view.WriteString("\n")
+	}
+
+	status := fmt.Sprintf("PR %d of %d", min(b.cursor+1, len(visible)), len(visible))
+	if b.filter != "" {
+		status += fmt.Sprintf(" matching %q", b.filter)
+	}
+	if b.sortBy != "" {
+		direction := "ascending"
+		if b.reverse {
+			direction = "descending"
+		}
+		status += fmt.Sprintf(", sorted by %s (%s)", b.sortBy, direction)
+	}
+	view.WriteString("\n" + statusStyle.Render(status+". Press ? for help.") + "\n")
+	switch {
+	case b.mode == modePrompt:
+		view.WriteString(b.prompt.View())
+	case b.message != "":
+		view.WriteString(b.message)
+	}
+	return view.String()
+}
+
+// Lays out rows as left-aligned columns, styling the header and the picked column in it
+func (b *Browser) formatTable(rows [][]string) []string {
+	widths := make([]int, len(b.columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if r == 0 {
+				style := headerStyle
+				if i == b.column {
+					style = pickedStyle
+				}
+				cell = style.Render(cell)
+			}
+			cells[i] = cell
+		}
+		lines[r] = strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+	return lines
+}
+
+// Returns the PRs matching the filter, in the current sort order
+func (b *Browser) visiblePRs() []*api.PRMetrics {
+	if b.filter == "" {
+		return b.prs
+	}
+	var visible []*api.PRMetrics
+	for _, pr := range b.prs {
+		if strings.Contains(strings.ToLower(pr.Title), b.filter) || strings.Contains(strings.ToLower(pr.Author), b.filter) {
+			visible = append(visible, pr)
+		}
+	}
+	return visible
+}
+
+// Sorts the PRs by a column, reversing the order when sorted by it already
+func (b *Browser) sort(column string) {
+	b.reverse = column == b.sortBy && !b.reverse
+	b.sortBy = column
+	b.cursor = 0
+
+	sort.SliceStable(b.prs, func(i, j int) bool {
+		left, _ := output.PRMetricField(b.prs[i], column)
+		right, _ := output.PRMetricField(b.prs[j], column)
+		if b.reverse {
+			return less(right, left)
+		}
+		return less(left, right)
+	})
+}
+
+// Replaces the columns of the PR list
+func (b *Browser) selectColumns(names string) string {
+	if names == "" {
+		b.columns = defaultColumns
+		b.column = 0
+		return ""
+	}
+
+	var columns []string
+	for _, name := range strings.Split(names, ",") {
+		column, err := matchColumn(strings.TrimSpace(name))
+		if err != nil {
+			return err.Error()
+		}
+		columns = append(columns, column)
+	}
+	b.columns = columns
+	b.column = 0
+	return ""
+}
+
+// Opens the view of every metric of a PR followed by its timeline
+func (b *Browser) showPR(number int) {
+	index := slices.IndexFunc(b.prs, func(pr *api.PRMetrics) bool {
+		return pr.Number == number
+	})
+	if index < 0 {
+		b.message = fmt.Sprintf("No PR #%d", number)
+		return
+	}
+
+	height := b.height - 1
+	if b.height == 0 {
+		height = defaultPageSize + chromeHeight
+	}
+	b.detail = viewport.New(b.width, height)
+	b.detail.SetContent(b.describePR(b.prs[index]))
+	b.mode = modeDetail
+}
+
+// Returns every metric of a PR followed by its timeline
+func (b *Browser) describePR(pr *api.PRMetrics) string {
+	var text strings.Builder
+	text.WriteString(headerStyle.Render(fmt.Sprintf("PR #%d: %s", pr.Number, pr.Title)) + "\n\n")
+	columns := output.PRColumns()
+	width := 0
+	for _, column := range columns {
+		width = max(width, len(column))
+	}
+	for _, column := range columns {
+		value, _ := output.PRMetricField(pr, column)
+		fmt.Fprintf(&text, "  %-*s  %s\n", width, column, formatCell(value))
+	}
+
+	events := b.events[pr.Number]
+	if len(events) == 0 {
+		text.WriteString("\nNo timeline; write one with --events to browse it here.\n")
+		return text.String()
+	}
+	text.WriteString("\n" + headerStyle.Render("Timeline") + "\n")
+	for _, event := range events {
+		fmt.Fprintf(&text, "  %s  %-8s  %s", event.Timestamp.Format(time.RFC3339), event.Type, event.Actor)
+		if event.Detail != "" {
+			fmt.Fprintf(&text, "  %s", event.Detail)
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// Finds the column named exactly or, failing that, the only one starting with the name,
+// ignoring case
+func matchColumn(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("name a column, such as %q", defaultColumns[0])
+	}
+
+	var matches []string
+	for _, column := range output.PRColumns() {
+		if strings.EqualFold(column, name) {
+			return column, nil
+		}
+		if strings.HasPrefix(strings.ToLower(column), strings.ToLower(name)) {
+			matches = append(matches, column)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no column %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// Orders two values of the same column
+func less(left, right any) bool {
+	switch l := left.(type) {
+	case int:
+		return l < right.(int)
+	case float64:
+		return l < right.(float64)
+	case bool:
+		return !l && right.(bool)
+	case time.Time:
+		return l.Before(right.(time.Time))
+	}
+	return output.FormatField(left) < output.FormatField(right)
+}
+
+// Formats a value for display, showing dates without their time zone
+func formatCell(value any) string {
+	if t, ok := value.(time.Time); ok && !t.IsZero() {
+		return t.Format("2006-01-02 15:04")
+	}
+	return output.FormatField(value)
+}
+
+// Shortens a cell to the maximum width
+func truncate(cell string) string {
+	if utf8.RuneCountInString(cell) <= maxCellWidth {
+		return cell
+	}
+	return string([]rune(cell)[:maxCellWidth-1]) + "…"
+}
@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+)
+
+func newTestBrowser() *Browser {
+	prs := []*api.PRMetrics{
+		{Number: 3, Title: "Add login", Author: "alice", TotalPRLifetimeHours: 5},
+		{Number: 1, Title: "Fix typo", Author: "bob", TotalPRLifetimeHours: 30},
+		{Number: 2, Title: "Refactor auth", Author: "alice", TotalPRLifetimeHours: 12},
+	}
+	events := []api.PREvent{
+		{PRNumber: 3, Type: "merged", Actor: "carol", Timestamp: time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC)},
+		{PRNumber: 3, Type: "opened", Actor: "alice", Timestamp: time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)},
+	}
+	return NewBrowser(prs, events, strings.NewReader(""), &bytes.Buffer{}, false)
+}
+
+// Sends keys to the browser as if typed
+func press(b *Browser, keys ...string) {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		b.Update(msg)
+	}
+}
+
+func numbers(prs []*api.PRMetrics) []int {
+	result := make([]int, len(prs))
+	for i, pr := range prs {
+		result[i] = pr.Number
+	}
+	return result
+}
+
+func TestBrowserSortsByPickedColumn(t *testing.T) {
+	b := newTestBrowser()
+
+	press(b, "s")
+	if got := numbers(b.visiblePRs()); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("after sorting by PR Number = %v, want [1 2 3]", got)
+	}
+	press(b, "s")
+	if got := numbers(b.visiblePRs()); !equalInts(got, []int{3, 2, 1}) {
+		t.Errorf("after sorting again = %v, want [3 2 1]", got)
+	}
+
+	press(b, "c", "pr number, total", "enter", "right", "s")
+	if b.sortBy != "Total PR Lifetime (Hours)" {
+		t.Fatalf("sortBy = %q, want Total PR Lifetime (Hours)", b.sortBy)
+	}
+	if got := numbers(b.visiblePRs()); !equalInts(got, []int{3, 2, 1}) {
+		t.Errorf("after sorting by lifetime = %v, want [3 2 1]", got)
+	}
+}
+
+func TestBrowserFiltersByAuthor(t *testing.T) {
+	b := newTestBrowser()
+
+	press(b, "/", "ALICE", "enter")
+	if got := numbers(b.visiblePRs()); !equalInts(got, []int{3, 2}) {
+		t.Errorf("filtered PRs = %v, want [3 2]", got)
+	}
+	if view := b.View(); !strings.Contains(view, `matching "alice"`) {
+		t.Errorf("status does not show the filter:\n%s", view)
+	}
+
+	press(b, "/", "enter")
+	if got := len(b.visiblePRs()); got != 3 {
+		t.Errorf("PRs after clearing the filter = %d, want 3", got)
+	}
+}
+
+func TestBrowserShowsTimeline(t *testing.T) {
+	b := newTestBrowser()
+
+	press(b, "enter")
+	if b.mode != modeDetail {
+		t.Fatalf("mode = %v, want the PR view", b.mode)
+	}
+	view := b.detail.View()
+	if !strings.Contains(view, "PR #3: Add login") {
+		t.Errorf("PR view does not show the title:\n%s", view)
+	}
+	description := b.describePR(b.prs[0])
+	opened, merged := strings.Index(description, "opened"), strings.Index(description, "merged")
+	if opened < 0 || merged < 0 || opened > merged {
+		t.Errorf("timeline is not in time order:\n%s", description)
+	}
+
+	press(b, "esc", "down", "enter")
+	if !strings.Contains(b.describePR(b.visiblePRs()[1]), "No timeline") {
+		t.Errorf("PR without events does not say so:\n%s", b.describePR(b.visiblePRs()[1]))
+	}
+}
+
+func TestBrowserReportsUnknownInput(t *testing.T) {
+	b := newTestBrowser()
+
+	press(b, "c", "nonexistent", "enter")
+	if !strings.Contains(b.message, `no column "nonexistent"`) {
+		t.Errorf("message = %q, want an unknown column", b.message)
+	}
+	press(b, "#", "42", "enter")
+	if b.message != "No PR #42" {
+		t.Errorf("message = %q, want No PR #42", b.message)
+	}
+	if b.mode != modeList {
+		t.Errorf("mode = %v, want the PR list", b.mode)
+	}
+}
+
+func TestBrowserKeepsCursorOnScreen(t *testing.T) {
+	b := newTestBrowser()
+	b.Update(tea.WindowSizeMsg{Width: 80, Height: chromeHeight + 2})
+
+	press(b, "down", "down", "down")
+	if b.cursor != 2 || b.offset != 1 {
+		t.Errorf("cursor, offset = %d, %d; want 2, 1", b.cursor, b.offset)
+	}
+}
+
+func TestProgressPrintsTenthsWithoutTerminal(t *testing.T) {
+	var out bytes.Buffer
+	progress := NewProgress(&out, false)
+	progress.Start("Fetching PR data")
+	for done := 1; done <= 20; done++ {
+		progress.Update(done, 20)
+	}
+	progress.Finish()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("lines = %d, want 10:\n%s", len(lines), out.String())
+	}
+	if lines[9] != "Fetching PR data: 20/20 PRs" {
+		t.Errorf("last line = %q", lines[9])
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
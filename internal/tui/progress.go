@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const progressBarWidth = 30
+
+// Shows how many PRs of a run have been fetched while it runs. Interactive terminals get a bar
+// redrawn in place by a bubbletea program; other outputs get a line at every tenth of the way,
+// so logs stay readable.
+type Progress struct {
+	out         io.Writer
+	interactive bool
+	label       string
+	reported    int           // Tenths of the way reported so far, on outputs that are not terminals
+	program     *tea.Program  // Draws the bar of the current stage, on interactive terminals
+	done        chan struct{} // Closed when the program has exited and restored the terminal
+}
+
+// Initializes progress display writing to out
+func NewProgress(out io.Writer, interactive bool) *Progress {
+	return &Progress{
+		out:         out,
+		interactive: interactive,
+	}
+}
+
+// Starts a new stage, such as the month of a backfill, ending the line of the previous one
+func (p *Progress) Start(label string) {
+	p.Finish()
+	p.label = label
+	if !p.interactive {
+		return
+	}
+
+	// The run keeps the keyboard and its signals; the program only draws
+	p.program = tea.NewProgram(newProgressModel(label), tea.WithOutput(p.out), tea.WithInput(nil), tea.WithoutSignalHandler())
+	p.done = make(chan struct{})
+	go func(program *tea.Program, done chan struct{}) {
+		defer close(done)
+		_, _ = program.Run()
+	}(p.program, p.done)
+}
+
+// Shows that done of total PRs have been fetched
+func (p *Progress) Update(done, total int) {
+	if total <= 0 {
+		return
+	}
+
+	if p.program != nil {
+		p.program.Send(progressMsg{done: done, total: total})
+		return
+	}
+	if !p.interactive {
+		tenths := done * 10 / total
+		if tenths > p.reported {
+			p.reported = tenths
+			fmt.Fprintf(p.out, "%s: %d/%d PRs\n", p.label, done, total)
+		}
+	}
+}
+
+// Leaves the bar of the current stage on screen and waits for its program to exit
+func (p *Progress) Finish() {
+	if p.program != nil {
+		p.program.Send(progressDoneMsg{})
+		<-p.done
+		p.program = nil
+	}
+	p.reported = 0
+}
+
+// PRs fetched so far in the current stage
+type progressMsg struct {
+	done  int
+	total int
+}
+
+// Ends the current stage
+type progressDoneMsg struct{}
+
+// Draws the label, the bar, and the count of one stage
+type progressModel struct {
+	label string
+	bar   progress.Model
+	done  int
+	total int
+}
+
+func newProgressModel(label string) progressModel {
+	return progressModel{
+		label: label,
+		bar:   progress.New(progress.WithDefaultGradient(), progress.WithWidth(progressBarWidth)),
+	}
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case progressMsg:
+		m.done, m.total = msg.done, msg.total
+	case progressDoneMsg:
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m progressModel) View() string {
+	if m.total == 0 {
+		return m.label + "\n"
+	}
+	percent := float64(m.done) / float64(m.total)
+	return fmt.Sprintf("%s %s %d/%d PRs\n", m.label, m.bar.ViewAs(percent), m.done, m.total)
+}
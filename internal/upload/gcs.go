@@ -0,0 +1,98 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcsUploadURL = "https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s"
+	gcsScope     = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// Puts objects into a Google Cloud Storage bucket through the JSON API
+type gcsStore struct {
+	bucket      string
+	uploadURL   string // Format of the upload URL, taking the escaped bucket and object name
+	client      *http.Client
+	tokenSource oauth2.TokenSource // Found on the first upload; caches and refreshes the token
+}
+
+// Initializes GCS store for the bucket
+func newGCSStore(bucket string, client *http.Client) *gcsStore {
+	return &gcsStore{
+		bucket:    bucket,
+		uploadURL: gcsUploadURL,
+		client:    client,
+	}
+}
+
+// Uploads the file with a single media upload
+func (s *gcsStore) Put(key, filename string) error {
+	token, err := s.accessToken()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(s.uploadURL, url.PathEscape(s.bucket), url.QueryEscape(key)), file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", contentType(filename))
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GCS returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// Returns a valid access token from GOOGLE_OAUTH_ACCESS_TOKEN, or else from Application Default
+// Credentials: the key file in GOOGLE_APPLICATION_CREDENTIALS, the credentials written by
+// `gcloud auth application-default login`, or the attached service account on Google Cloud
+func (s *gcsStore) accessToken() (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	if s.tokenSource == nil {
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.client)
+		credentials, err := google.FindDefaultCredentials(ctx, gcsScope)
+		if err != nil {
+			return "", fmt.Errorf("no Google Cloud credentials found: %v", err)
+		}
+		s.tokenSource = credentials.TokenSource
+	}
+
+	token, err := s.tokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google Cloud access token: %v", err)
+	}
+	return token.AccessToken, nil
+}
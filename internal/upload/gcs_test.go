@@ -0,0 +1,96 @@
+package upload
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGCSStorePutWithAuthorizedUserCredentials(t *testing.T) {
+	tokenRequests := 0
+	var uploads []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			if err := r.ParseForm(); err != nil || r.Form.Get("refresh_token") != "refresh" {
+				http.Error(w, "bad refresh token", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"access_token":"user-token","token_type":"Bearer","expires_in":3600}`)
+		default:
+			body, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, r)
+			bodies = append(bodies, string(body))
+		}
+	}))
+	defer server.Close()
+
+	// Credentials as written by `gcloud auth application-default login`
+	credentials, err := json.Marshal(map[string]string{
+		"type":          "authorized_user",
+		"client_id":     "client",
+		"client_secret": "secret",
+		"refresh_token": "refresh",
+		"token_uri":     server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialsFile := filepath.Join(t.TempDir(), "application_default_credentials.json")
+	if err := os.WriteFile(credentialsFile, credentials, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", credentialsFile)
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+
+	store := newGCSStore("metrics", http.DefaultClient)
+	store.uploadURL = server.URL + "/upload/storage/v1/b/%s/o?uploadType=media&name=%s"
+	for _, name := range []string{"pr_metrics.csv", "run_report.json"} {
+		filename := writeOutput(t, name, "content of "+name)
+		if err := store.Put("acme/"+name, filename); err != nil {
+			t.Fatalf("Put(%s) error = %v", name, err)
+		}
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want 1", tokenRequests)
+	}
+	if len(uploads) != 2 {
+		t.Fatalf("uploads = %d, want 2", len(uploads))
+	}
+	upload := uploads[0]
+	if got := upload.Header.Get("Authorization"); got != "Bearer user-token" {
+		t.Errorf("authorization = %q, want %q", got, "Bearer user-token")
+	}
+	if got := upload.URL.Query().Get("name"); got != "acme/pr_metrics.csv" {
+		t.Errorf("object name = %q, want %q", got, "acme/pr_metrics.csv")
+	}
+	if want := "content of pr_metrics.csv"; bodies[0] != want {
+		t.Errorf("body = %q, want %q", bodies[0], want)
+	}
+}
+
+func TestGCSStorePutWithAccessTokenFromEnvironment(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "env-token")
+
+	store := newGCSStore("metrics", http.DefaultClient)
+	store.uploadURL = server.URL + "/upload/storage/v1/b/%s/o?uploadType=media&name=%s"
+	if err := store.Put("pr_metrics.csv", writeOutput(t, "pr_metrics.csv", "PR Number\n")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if authorization != "Bearer env-token" {
+		t.Errorf("authorization = %q, want %q", authorization, "Bearer env-token")
+	}
+}
@@ -0,0 +1,88 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const defaultS3Region = "us-east-1"
+
+// Puts objects into an S3 bucket, or a bucket of an S3-compatible store such as MinIO, through
+// the AWS SDK, which resolves credentials the same way as the AWS CLI
+type s3Store struct {
+	bucket string
+	client *s3.Client // Created on the first upload, when credentials are loaded
+}
+
+// Initializes S3 store for the bucket
+func newS3Store(bucket string) *s3Store {
+	return &s3Store{
+		bucket: bucket,
+	}
+}
+
+// Uploads the file with a single PUT, which S3 accepts up to 5 GB
+func (s *s3Store) Put(key, filename string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	if s.client == nil {
+		client, err := s.newClient(ctx)
+		if err != nil {
+			return err
+		}
+		s.client = client
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          file,
+		ContentLength: aws.Int64(info.Size()),
+		ContentType:   aws.String(contentType(filename)),
+	})
+	if err != nil {
+		return fmt.Errorf("S3 upload failed: %v", err)
+	}
+	return nil
+}
+
+// Creates the S3 client from the default credential chain: environment variables, the shared
+// config and credentials files with AWS_PROFILE, web identity tokens of IAM roles for service
+// accounts, the container endpoint of ECS and EKS Pod Identity, and the EC2 instance profile.
+// The SDK's own HTTP client is kept so AWS_CA_BUNDLE applies; Put bounds each upload instead.
+func (s *s3Store) newClient(ctx context.Context) (*s3.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %v", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultS3Region
+	}
+
+	// Custom endpoints such as MinIO are addressed path-style, as are bucket names with dots,
+	// which the wildcard certificate of S3 does not cover
+	customEndpoint := firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL") != ""
+	return s3.NewFromConfig(cfg, func(options *s3.Options) {
+		options.UsePathStyle = customEndpoint || strings.Contains(s.bucket, ".")
+		// Send checksums only when S3 requires them, since many S3-compatible stores reject the
+		// chunked trailers the SDK would otherwise add to every upload
+		options.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+	}), nil
+}
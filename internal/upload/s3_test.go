@@ -0,0 +1,126 @@
+package upload
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Points the AWS SDK at a fake S3 endpoint with no credentials from the host
+func setupS3Env(t *testing.T, endpoint string) {
+	dir := t.TempDir()
+	t.Setenv("AWS_ENDPOINT_URL_S3", endpoint)
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+}
+
+// Records the request a fake S3 endpoint received
+type s3Request struct {
+	method        string
+	path          string
+	authorization string
+	contentType   string
+	body          string
+}
+
+func newS3Server(t *testing.T) (*httptest.Server, *s3Request) {
+	received := &s3Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*received = s3Request{
+			method:        r.Method,
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			contentType:   r.Header.Get("Content-Type"),
+			body:          string(body),
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func writeOutput(t *testing.T, name, content string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestS3StorePutWithEnvironmentCredentials(t *testing.T) {
+	server, received := newS3Server(t)
+	setupS3Env(t, server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	filename := writeOutput(t, "pr_metrics.csv", "PR Number\n1\n")
+	store := newS3Store("metrics")
+	if err := store.Put("acme/20260401T060000Z/pr_metrics.csv", filename); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if received.method != http.MethodPut {
+		t.Errorf("method = %s, want PUT", received.method)
+	}
+	if want := "/metrics/acme/20260401T060000Z/pr_metrics.csv"; received.path != want {
+		t.Errorf("path = %s, want %s", received.path, want)
+	}
+	if !strings.HasPrefix(received.authorization, "AWS4-HMAC-SHA256 Credential=AKIDENV/") ||
+		!strings.Contains(received.authorization, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("authorization = %q, want a SigV4 signature for AKIDENV in eu-west-1", received.authorization)
+	}
+	if want := "text/csv; charset=utf-8"; received.contentType != want {
+		t.Errorf("content type = %q, want %q", received.contentType, want)
+	}
+	if want := "PR Number\n1\n"; received.body != want {
+		t.Errorf("body = %q, want %q", received.body, want)
+	}
+}
+
+func TestS3StorePutWithSharedCredentialsProfile(t *testing.T) {
+	server, received := newS3Server(t)
+	setupS3Env(t, server.URL)
+	credentials := "[scheduled]\naws_access_key_id = AKIDPROFILE\naws_secret_access_key = secret\n"
+	if err := os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_PROFILE", "scheduled")
+
+	filename := writeOutput(t, "run_report.json", "{}")
+	store := newS3Store("metrics")
+	if err := store.Put("run_report.json", filename); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if !strings.HasPrefix(received.authorization, "AWS4-HMAC-SHA256 Credential=AKIDPROFILE/") {
+		t.Errorf("authorization = %q, want a signature for the profile's key", received.authorization)
+	}
+}
+
+func TestS3StorePutReportsRejectedUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+	}))
+	defer server.Close()
+	setupS3Env(t, server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	filename := writeOutput(t, "pr_metrics.csv", "PR Number\n")
+	store := newS3Store("metrics")
+	err := store.Put("pr_metrics.csv", filename)
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put() error = %v, want AccessDenied", err)
+	}
+}
@@ -0,0 +1,164 @@
+package upload
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Supported object storage schemes
+const (
+	SchemeS3  = "s3"
+	SchemeGCS = "gs"
+)
+
+const (
+	uploadTimeout = 10 * time.Minute // Per file, generous for outputs of hundreds of megabytes
+	runIDLayout   = "20060102T150405Z"
+)
+
+// Bucket and key prefix the outputs of a run are uploaded under
+type Destination struct {
+	Scheme string // s3 or gs
+	Bucket string
+	Prefix string // Without leading or trailing slashes; may be empty
+}
+
+// Parses an s3://bucket/prefix or gs://bucket/prefix URL
+func ParseDestination(rawURL string) (*Destination, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload destination %q: %v", rawURL, err)
+	}
+	if parsed.Scheme != SchemeS3 && parsed.Scheme != SchemeGCS {
+		return nil, fmt.Errorf("invalid upload destination %q: must start with s3:// or gs://", rawURL)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid upload destination %q: bucket is missing", rawURL)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" {
+		return nil, fmt.Errorf("invalid upload destination %q: must not have a query or fragment", rawURL)
+	}
+
+	return &Destination{
+		Scheme: parsed.Scheme,
+		Bucket: parsed.Host,
+		Prefix: strings.Trim(parsed.Path, "/"),
+	}, nil
+}
+
+// Returns the key prefix of a run, the destination prefix followed by the UTC start time of the
+// run, so scheduled runs never overwrite each other
+func (d *Destination) RunPrefix(startedAt time.Time) string {
+	return path.Join(d.Prefix, startedAt.UTC().Format(runIDLayout))
+}
+
+// Writes one object to a bucket
+type objectStore interface {
+	Put(key, filename string) error
+}
+
+// Uploads the outputs of a run to object storage
+type Uploader struct {
+	destination *Destination
+	store       objectStore
+	logger      *utils.Logger
+}
+
+// Initializes uploader for the destination, reading credentials from the environment
+func NewUploader(destination *Destination, logger *utils.Logger) *Uploader {
+	var store objectStore
+	switch destination.Scheme {
+	case SchemeGCS:
+		store = newGCSStore(destination.Bucket, &http.Client{Timeout: uploadTimeout})
+	default:
+		store = newS3Store(destination.Bucket)
+	}
+
+	return &Uploader{
+		destination: destination,
+		store:       store,
+		logger:      logger,
+	}
+}
+
+// Uploads the files under the run prefix, keyed by their paths relative to the output directory,
+// and returns the URL of the run prefix. Every file is attempted even after a failure, so one
+// rejected upload does not leave the rest of the run behind.
+func (u *Uploader) Upload(dir string, files []string, startedAt time.Time) (string, error) {
+	runPrefix := u.destination.RunPrefix(startedAt)
+	location := u.destination.Scheme + "://" + u.destination.Bucket + "/" + runPrefix
+
+	failures := 0
+	for _, filename := range files {
+		key, err := objectKey(runPrefix, dir, filename)
+		if err != nil {
+			return location, err
+		}
+		if err := u.store.Put(key, filename); err != nil {
+			u.logger.Error("Failed to upload %s: %v", filename, err)
+			failures++
+			continue
+		}
+		u.logger.Debug("Uploaded %s to %s://%s/%s", filename, u.destination.Scheme, u.destination.Bucket, key)
+	}
+
+	if failures > 0 {
+		return location, fmt.Errorf("%d of %d files failed to upload", failures, len(files))
+	}
+	return location, nil
+}
+
+// Joins the run prefix and the path of the file relative to the output directory, with forward
+// slashes on every platform
+func objectKey(runPrefix, dir, filename string) (string, error) {
+	relative, err := filepath.Rel(dir, filename)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the output directory %s", filename, dir)
+	}
+	return path.Join(runPrefix, filepath.ToSlash(relative)), nil
+}
+
+// Returns the first environment variable of the names that is set
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Returns the MIME type of an output file, so browsers open reports from the bucket console
+func contentType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return "text/csv; charset=utf-8"
+	case ".json":
+		return "application/json"
+	case ".jsonl":
+		return "application/x-ndjson"
+	case ".html":
+		return "text/html; charset=utf-8"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	case ".svg":
+		return "image/svg+xml"
+	case ".png":
+		return "image/png"
+	case ".xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ".gz":
+		return "application/gzip"
+	case ".zip":
+		return "application/zip"
+	}
+	return "application/octet-stream"
+}
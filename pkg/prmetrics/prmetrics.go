@@ -0,0 +1,113 @@
+package prmetrics
+
+import (
+	"github.com/fukuchancat/github-pr-metrics/internal/api"
+	"github.com/fukuchancat/github-pr-metrics/internal/metrics"
+	"github.com/fukuchancat/github-pr-metrics/internal/model"
+	"github.com/fukuchancat/github-pr-metrics/pkg/utils"
+)
+
+// Types of the calculator's inputs and results, usable by services embedding it
+type (
+	PRData            = api.PRData
+	RepositoryData    = api.RepositoryData
+	ReviewThread      = api.ReviewThread
+	PRMetrics         = api.PRMetrics
+	AggregatedMetrics = api.AggregatedMetrics
+	PRError           = api.PRError
+	Calculator        = metrics.Calculator
+)
+
+// Types of the option fields, which hold plain values embedders can fill in
+type (
+	SizeExclusionOptions    = metrics.SizeExclusionOptions
+	CategoryRule            = metrics.CategoryRule
+	DependencyUpdateOptions = metrics.DependencyUpdateOptions
+)
+
+// Commit date sources and error policies the options accept
+const (
+	CommitDateSourceAuthor    = metrics.CommitDateSourceAuthor
+	CommitDateSourceCommitter = metrics.CommitDateSourceCommitter
+	ErrorPolicySkip           = metrics.ErrorPolicySkip
+	ErrorPolicyFail           = metrics.ErrorPolicyFail
+	ErrorPolicyRetry          = metrics.ErrorPolicyRetry
+)
+
+// Options tunes how PR metrics are derived from the PR data. Business hours, analysis of a local
+// clone, and Jira lookups need clients only the command builds, so embedders go without them.
+type Options struct {
+	CommitDateSource  string                  // Author or committer date; committer dates survive rebases better
+	ClampCommitTimes  bool                    // Clamp commit times after the merge to the merge time
+	TestFilePatterns  []string                // Files counted as tests for the test change ratio; defaults to common conventions
+	SizeExclusions    SizeExclusionOptions    // Files left out of the size metrics
+	Categories        []CategoryRule          // Rules assigning each PR a category; the first match wins
+	IssueKeyPattern   string                  // Regular expression matching issue keys in titles and branches; none are extracted when empty
+	DependencyUpdates DependencyUpdateOptions // Bots whose PRs are dependency updates besides Dependabot and Renovate
+	OnError           string                  // How failures while reading a PR's data are handled: skip, fail, or retry
+	CIMetrics         bool                    // Read the check runs of each head commit for the CI queue time and flaky checks
+}
+
+// Converts the options to those of the calculator
+func (o Options) metricsOptions() metrics.Options {
+	return metrics.Options{
+		CommitDateSource:  o.CommitDateSource,
+		ClampCommitTimes:  o.ClampCommitTimes,
+		TestFilePatterns:  o.TestFilePatterns,
+		SizeExclusions:    o.SizeExclusions,
+		Categories:        o.Categories,
+		IssueKeys:         metrics.IssueKeyOptions{Pattern: o.IssueKeyPattern},
+		DependencyUpdates: o.DependencyUpdates,
+		OnError:           o.OnError,
+		CIMetrics:         o.CIMetrics,
+	}
+}
+
+// Types of the provider-neutral model, for services supplying PR data from sources other than
+// go-github through a Source
+type (
+	Source           = model.Source
+	PullRequest      = model.PullRequest
+	CommitEvent      = model.CommitEvent
+	CommentEvent     = model.CommentEvent
+	ReviewEvent      = model.ReviewEvent
+	FileChange       = model.FileChange
+	TimelineEvent    = model.TimelineEvent
+	CheckRun         = model.CheckRun
+	CommitStatus     = model.CommitStatus
+	BranchProtection = model.BranchProtection
+)
+
+// Initializes a calculator over PR data the caller already fetched, without an API client or token
+func NewCalculator(prs []*PRData, repository RepositoryData, logger *utils.Logger, options Options) *Calculator {
+	return metrics.NewCalculatorFromData(prs, repository, logger, options.metricsOptions())
+}
+
+// Initializes a calculator reading PR data from a source in the provider-neutral model, for
+// services whose data does not come as go-github types
+func NewCalculatorFromSource(source Source, logger *utils.Logger, options Options) *Calculator {
+	return metrics.NewCalculator(source, logger, options.metricsOptions())
+}
+
+// Converts the go-github PullRequest of each PRData to the provider-neutral model the
+// calculator's methods take
+func PullRequests(prs []*PRData) []*PullRequest {
+	pullRequests := make([]*PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		pullRequests = append(pullRequests, api.GitHubConverter{}.PullRequest(pr.PullRequest))
+	}
+	return pullRequests
+}
+
+// Calculates the metrics of the provided PRs and links stacked PRs, returning the metrics of the
+// PRs that could be calculated and the failures met along the way
+func Calculate(owner, repo string, prs []*PRData, repository RepositoryData, logger *utils.Logger, options Options) ([]*PRMetrics, []*PRError, error) {
+	calculator := NewCalculator(prs, repository, logger, options)
+
+	prMetrics, err := calculator.CalculateAllPRMetrics(owner, repo, PullRequests(prs))
+	if err != nil {
+		return nil, calculator.Errors(), err
+	}
+	calculator.DetectStacks(prMetrics)
+	return prMetrics, calculator.Errors(), nil
+}
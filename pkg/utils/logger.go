@@ -2,10 +2,27 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 )
 
+// Supported log output formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// LoggerOptions configures a logger
+type LoggerOptions struct {
+	Verbose        bool
+	Format         string // LogFormatText or LogFormatJSON; defaults to text
+	Quiet          bool   // Only print errors to the console
+	File           string // Also write logs to this file, rotating it by size
+	MaxFileSize    int64  // Size in bytes at which the log file is rotated; defaults to DefaultLogMaxSize
+	MaxFileBackups int    // Number of rotated log files to keep; defaults to DefaultLogMaxBackups
+}
+
 // Logger represents a structured logger
 type Logger struct {
 	verbose bool
@@ -13,21 +30,65 @@ type Logger struct {
 }
 
 // NewLogger creates a new logger
-func NewLogger(verbose bool) *Logger {
+func NewLogger(options LoggerOptions) (*Logger, error) {
 	var level slog.Level
-	if verbose {
+	if options.Verbose {
 		level = slog.LevelDebug
 	} else {
 		level = slog.LevelInfo
 	}
 
-	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: level,
-	})
+	consoleLevel := level
+	if options.Quiet {
+		consoleLevel = slog.LevelError
+	}
+	handlers := multiHandler{newHandler(os.Stderr, options.Format, consoleLevel)}
+
+	if options.File != "" {
+		maxSize := options.MaxFileSize
+		if maxSize <= 0 {
+			maxSize = DefaultLogMaxSize
+		}
+		maxBackups := options.MaxFileBackups
+		if maxBackups <= 0 {
+			maxBackups = DefaultLogMaxBackups
+		}
+
+		file, err := openRotatingFile(options.File, maxSize, maxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %v", err)
+		}
+		handlers = append(handlers, newHandler(file, options.Format, level))
+	}
+
+	var handler slog.Handler = handlers
+	if len(handlers) == 1 {
+		handler = handlers[0]
+	}
 
 	return &Logger{
-		verbose: verbose,
+		verbose: options.Verbose,
 		logger:  slog.New(handler),
+	}, nil
+}
+
+// newHandler creates a text or JSON handler writing to w
+func newHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	handlerOptions := &slog.HandlerOptions{
+		Level: level,
+	}
+
+	if format == LogFormatJSON {
+		return slog.NewJSONHandler(w, handlerOptions)
+	}
+	return slog.NewTextHandler(w, handlerOptions)
+}
+
+// With returns a logger that adds the given key-value fields, such as repo, pr, or stage, to every message
+func (l *Logger) With(args ...any) *Logger {
+	return &Logger{
+		verbose: l.verbose,
+		logger:  l.logger.With(args...),
 	}
 }
 